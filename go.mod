@@ -4,8 +4,13 @@ go 1.16
 
 require (
 	github.com/andybalholm/brotli v1.0.4
+	github.com/andybalholm/cascadia v1.3.1
+	github.com/antchfx/htmlquery v1.2.3
+	github.com/antchfx/xmlquery v1.3.5
+	github.com/antchfx/xpath v1.2.4
 	github.com/denisbrodbeck/machineid v1.0.1
 	github.com/getsentry/sentry-go v0.13.0
+	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/google/uuid v1.3.0
 	github.com/httprunner/funplugin v0.4.2
 	github.com/jinzhu/copier v0.3.2
@@ -19,6 +24,8 @@ require (
 	github.com/rs/zerolog v1.26.1
 	github.com/spf13/cobra v1.2.1
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/crypto v0.0.0-20220214200702-86341886e292
+	golang.org/x/net v0.0.0-20220225172249-27dd8689420f
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
 
@@ -9,6 +9,8 @@ import (
 
 	"github.com/httprunner/funplugin"
 	"github.com/httprunner/httprunner/hrp/internal/boomer"
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+	"github.com/httprunner/httprunner/hrp/internal/redact"
 	"github.com/httprunner/httprunner/hrp/internal/sdk"
 )
 
@@ -24,6 +26,30 @@ type HRPBoomer struct {
 	*boomer.Boomer
 	plugins      []funplugin.IPlugin // each task has its own plugin process
 	pluginsMutex *sync.RWMutex       // avoid data race
+	redactors    []*redact.Redactor  // each task masks its own secrets
+	seed         *int64              // run seed to reproduce random data of each virtual user
+}
+
+// SetSeed configures the run seed, which makes parameters iteration, random builtin functions
+// and think time randomization of each virtual user reproducible.
+func (b *HRPBoomer) SetSeed(seed int64) *HRPBoomer {
+	log.Info().Int64("seed", seed).Msg("[init] SetSeed")
+	b.seed = &seed
+	return b
+}
+
+// SetFrozenTime freezes clock of time-based builtin functions at t, e.g. get_timestamp and get_time.
+func (b *HRPBoomer) SetFrozenTime(t time.Time) *HRPBoomer {
+	log.Info().Time("frozenTime", t).Msg("[init] SetFrozenTime")
+	builtin.FreezeTime(t)
+	return b
+}
+
+// SetTimeOffset shifts clock of time-based builtin functions by offset, e.g. -24h.
+func (b *HRPBoomer) SetTimeOffset(offset time.Duration) *HRPBoomer {
+	log.Info().Dur("timeOffset", offset).Msg("[init] SetTimeOffset")
+	builtin.SetTimeOffset(offset)
+	return b
 }
 
 // Run starts to run load test for one or multiple testcases.
@@ -47,16 +73,31 @@ func (b *HRPBoomer) Run(testcases ...ITestCase) {
 
 	for _, testcase := range testCases {
 		cfg := testcase.Config
-		err = initParameterIterator(cfg, "boomer")
+		// config parameters are shared by virtual users, parse them with functions seeded by the run seed
+		p := newParser()
+		p.functions = newVirtualUser(b.seed, 0).functions
+		err = initParameterIterator(p, cfg, "boomer")
 		if err != nil {
 			panic(err)
 		}
+		for _, it := range cfg.ParametersSetting.Iterators {
+			if err = it.setUsers(b.GetSpawnCount()); err != nil {
+				panic(err)
+			}
+		}
 		rendezvousList := initRendezvous(testcase, int64(b.GetSpawnCount()))
 		task := b.convertBoomerTask(testcase, rendezvousList)
 		taskSlice = append(taskSlice, task)
 		waitRendezvous(rendezvousList)
 	}
 	b.Boomer.Run(taskSlice...)
+	for _, redactor := range b.redactors {
+		redact.Detach(redactor)
+	}
+	// close parameters files when load test finishes
+	for _, testcase := range testCases {
+		testcase.Config.ParametersSetting.closeIterators()
+	}
 }
 
 func (b *HRPBoomer) Quit() {
@@ -73,6 +114,9 @@ func (b *HRPBoomer) convertBoomerTask(testcase *TestCase, rendezvousList []*Rend
 	hrpRunner := NewRunner(nil)
 	// set client transport for high concurrency load testing
 	hrpRunner.SetClientTransport(b.GetSpawnCount(), b.GetDisableKeepAlive(), b.GetDisableCompression())
+	// mask secrets of the task in logs during load testing
+	redact.Attach(hrpRunner.redactor)
+	b.redactors = append(b.redactors, hrpRunner.redactor)
 	config := testcase.Config
 
 	// each testcase has its own plugin process
@@ -91,11 +135,23 @@ func (b *HRPBoomer) convertBoomerTask(testcase *TestCase, rendezvousList []*Rend
 		}
 	}()
 
+	// each virtual user has its own random generator, seeded by the run seed and worker id
+	var vus sync.Map // workerID => *virtualUser
+	getVirtualUser := func(workerID int) *virtualUser {
+		if vu, ok := vus.Load(workerID); ok {
+			return vu.(*virtualUser)
+		}
+		vu, _ := vus.LoadOrStore(workerID, newVirtualUser(b.seed, workerID))
+		return vu.(*virtualUser)
+	}
+
 	return &boomer.Task{
 		Name:   config.Name,
 		Weight: config.Weight,
-		Fn: func() {
+		WorkerFn: func(workerID int) {
+			vu := getVirtualUser(workerID)
 			runner := hrpRunner.newCaseRunner(testcase)
+			runner.setVirtualUser(vu)
 			runner.parser.plugin = plugin
 
 			testcaseSuccess := true       // flag whole testcase result
@@ -110,9 +166,24 @@ func (b *HRPBoomer) convertBoomerTask(testcase *TestCase, rendezvousList []*Rend
 			}
 			// iterate through all parameter iterators and update case variables
 			for _, it := range caseConfig.ParametersSetting.Iterators {
-				if it.HasNext() {
-					caseConfig.Variables = mergeVariables(it.Next(), caseConfig.Variables)
+				params, ok, err := it.next(vu)
+				if err != nil {
+					log.Error().Err(err).Int("vu", vu.id).Msg("get parameters failed, stop virtual user")
+					b.StopWorker(workerID)
+					return
+				}
+				if !ok {
+					// parameters with once strategy are exhausted
+					if caseConfig.ParametersSetting.OnExhausted == exhaustedStopUser {
+						log.Warn().Int("vu", vu.id).Msg("parameters exhausted, stop virtual user")
+						b.StopWorker(workerID)
+					} else {
+						log.Warn().Msg("parameters exhausted, stop load testing")
+						b.Boomer.Quit()
+					}
+					return
 				}
+				caseConfig.Variables = mergeVariables(params, caseConfig.Variables)
 			}
 
 			if err := runner.parseConfig(caseConfig); err != nil {
@@ -129,7 +200,7 @@ func (b *HRPBoomer) convertBoomerTask(testcase *TestCase, rendezvousList []*Rend
 					if stepData != nil {
 						elapsed = stepData.Elapsed
 					}
-					b.RecordFailure(step.Type(), step.Name(), elapsed, err.Error())
+					b.RecordFailure(step.Type(), step.Name(), elapsed, hrpRunner.redactor.String(err.Error()))
 
 					// update flag
 					testcaseSuccess = false
@@ -1,8 +1,13 @@
 package hrp
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestBoomerStandaloneRun(t *testing.T) {
@@ -32,3 +37,41 @@ func TestBoomerStandaloneRun(t *testing.T) {
 	time.Sleep(5 * time.Second)
 	b.Quit()
 }
+
+func TestBoomerParametersOnce(t *testing.T) {
+	var mutex sync.Mutex
+	users := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		users[r.URL.Query().Get("user")]++
+		mutex.Unlock()
+	}))
+	defer ts.Close()
+
+	testcase := &TestCase{
+		Config: NewConfig("once").
+			SetBaseURL(ts.URL).
+			WithParameters(map[string]interface{}{
+				"user": []interface{}{"u1", "u2", "u3", "u4", "u5"},
+			}),
+		TestSteps: []IStep{
+			NewStep("get user").GET("/user").WithParams(map[string]interface{}{"user": "$user"}),
+		},
+	}
+	testcase.Config.ParametersSetting = &TParamsConfig{Strategy: "once"}
+
+	// load testing is stopped once parameters are exhausted
+	done := make(chan struct{})
+	go func() {
+		NewBoomer(2, 10).Run(testcase)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("load testing is not stopped when parameters are exhausted")
+	}
+	if !assert.Equal(t, map[string]int{"u1": 1, "u2": 1, "u3": 1, "u4": 1, "u5": 1}, users) {
+		t.Fail()
+	}
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"os"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/httprunner/httprunner/hrp"
@@ -44,6 +46,18 @@ var boomCmd = &cobra.Command{
 		hrpBoomer.EnableCPUProfile(cpuProfile, cpuProfileDuration)
 		hrpBoomer.EnableMemoryProfile(memoryProfile, memoryProfileDuration)
 		hrpBoomer.EnableGracefulQuit()
+		opts, err := parseReplayFlags(cmd)
+		if err != nil {
+			log.Error().Err(err).Msg("parse flags failed")
+			os.Exit(1)
+		}
+		hrpBoomer.SetSeed(opts.seed)
+		if opts.frozenTime != nil {
+			hrpBoomer.SetFrozenTime(*opts.frozenTime)
+		}
+		if opts.timeOffset != 0 {
+			hrpBoomer.SetTimeOffset(opts.timeOffset)
+		}
 		hrpBoomer.Run(paths...)
 	},
 }
@@ -80,4 +94,5 @@ func init() {
 	boomCmd.Flags().BoolVar(&disableConsoleOutput, "disable-console-output", false, "Disable console output.")
 	boomCmd.Flags().BoolVar(&disableCompression, "disable-compression", false, "Disable compression")
 	boomCmd.Flags().BoolVar(&disableKeepalive, "disable-keepalive", false, "Disable keepalive")
+	addReplayFlags(boomCmd)
 }
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/httprunner/httprunner/hrp/internal/curl2case"
+)
+
+// curl2caseCmd represents the curl2case command
+var curl2caseCmd = &cobra.Command{
+	Use:   "curl2case $curl_path...",
+	Short: "convert curl commands to json/yaml testcase files",
+	Long:  `convert text files with one or more curl commands to json/yaml testcase files`,
+	Example: `  $ hrp curl2case demo.curl	# convert to demo.json
+  $ hrp curl2case demo.curl -y	# convert to demo.yaml`,
+	Args: cobra.MinimumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		setLogLevel(logLevel)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var outputFiles []string
+		for _, arg := range args {
+			// must choose one
+			if !genYAMLFlag && !genJSONFlag {
+				return errors.New("please select convert format type")
+			}
+			var outputPath string
+			var err error
+
+			c := curl2case.NewCurl(arg)
+
+			// specify output dir
+			if outputDir != "" {
+				c.SetOutputDir(outputDir)
+			}
+
+			// generate json/yaml files
+			if genYAMLFlag {
+				outputPath, err = c.GenYAML()
+			} else {
+				outputPath, err = c.GenJSON() // default
+			}
+			if err != nil {
+				return err
+			}
+			outputFiles = append(outputFiles, outputPath)
+		}
+		log.Info().Strs("output", outputFiles).Msg("convert testcase success")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(curl2caseCmd)
+	curl2caseCmd.Flags().BoolVarP(&genJSONFlag, "to-json", "j", true, "convert to JSON format")
+	curl2caseCmd.Flags().BoolVarP(&genYAMLFlag, "to-yaml", "y", false, "convert to YAML format")
+	curl2caseCmd.Flags().StringVarP(&outputDir, "output-dir", "d", "", "specify output directory, default to the same dir with curl file")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/httprunner/httprunner/hrp/internal/postman2case"
+)
+
+// postman2caseCmd represents the postman2case command
+var postman2caseCmd = &cobra.Command{
+	Use:   "postman2case $collection_path...",
+	Short: "convert postman collection to json/yaml testcase files",
+	Long:  `convert postman collection v2.1 to json/yaml testcase files, each folder is converted to one testcase file`,
+	Example: `  $ hrp postman2case demo.postman_collection.json	# convert to demo/*.json
+  $ hrp postman2case demo.postman_collection.json -y	# convert to demo/*.yaml
+  $ hrp postman2case demo.postman_collection.json -e dev.postman_environment.json	# convert with environment`,
+	Args: cobra.MinimumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		setLogLevel(logLevel)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var outputFiles []string
+		var unconverted []string
+		for _, arg := range args {
+			// must choose one
+			if !genYAMLFlag && !genJSONFlag {
+				return errors.New("please select convert format type")
+			}
+			var outputPaths []string
+			var err error
+
+			p := postman2case.NewCollection(arg)
+
+			// specify output dir
+			if outputDir != "" {
+				p.SetOutputDir(outputDir)
+			}
+			// specify postman environment
+			if postmanEnvPath != "" {
+				p.SetEnvironment(postmanEnvPath)
+			}
+
+			// generate json/yaml files
+			if genYAMLFlag {
+				outputPaths, err = p.GenYAML()
+			} else {
+				outputPaths, err = p.GenJSON() // default
+			}
+			if err != nil {
+				return err
+			}
+			outputFiles = append(outputFiles, outputPaths...)
+			unconverted = append(unconverted, p.Unconverted()...)
+		}
+		if len(unconverted) > 0 {
+			log.Warn().Strs("unconverted", unconverted).Msg("contents not converted")
+		}
+		log.Info().Strs("output", outputFiles).Msg("convert testcase success")
+		return nil
+	},
+}
+
+var postmanEnvPath string
+
+func init() {
+	rootCmd.AddCommand(postman2caseCmd)
+	postman2caseCmd.Flags().BoolVarP(&genJSONFlag, "to-json", "j", true, "convert to JSON format")
+	postman2caseCmd.Flags().BoolVarP(&genYAMLFlag, "to-yaml", "y", false, "convert to YAML format")
+	postman2caseCmd.Flags().StringVarP(&outputDir, "output-dir", "d", "", "specify output directory, default to the same dir with collection file")
+	postman2caseCmd.Flags().StringVarP(&postmanEnvPath, "environment", "e", "", "specify postman environment file, values are converted to config variables")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+)
+
+var (
+	seed       int64
+	frozenTime string
+	timeOffset string
+)
+
+// addReplayFlags adds flags to reproduce random data and time of a run
+func addReplayFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64Var(&seed, "seed", 0, "set run seed to reproduce parameters iteration, random functions and think time, generated if not set")
+	cmd.Flags().StringVar(&frozenTime, "frozen-time", "", "freeze clock of time-based functions at RFC3339 time, e.g. 2022-03-08T15:04:05+08:00")
+	cmd.Flags().StringVar(&timeOffset, "time-offset", "", "shift clock of time-based functions by offset, e.g. -1d, 2h30m")
+}
+
+type replayOptions struct {
+	seed       int64
+	frozenTime *time.Time
+	timeOffset time.Duration
+}
+
+// parseReplayFlags parses replay flags, seed is generated from current time and logged if not specified,
+// thus the run could be replayed with the same seed.
+func parseReplayFlags(cmd *cobra.Command) (*replayOptions, error) {
+	opts := &replayOptions{seed: seed}
+	if !cmd.Flags().Changed("seed") {
+		opts.seed = time.Now().UnixNano()
+	}
+	log.Info().Int64("seed", opts.seed).Msg("specify --seed to replay the run")
+
+	if frozenTime != "" {
+		t, err := time.Parse(time.RFC3339, frozenTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid --frozen-time")
+		}
+		opts.frozenTime = &t
+	}
+	if timeOffset != "" {
+		offset, err := builtin.ParseTimeOffset(timeOffset)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid --time-offset")
+		}
+		opts.timeOffset = offset
+	}
+	return opts, nil
+}
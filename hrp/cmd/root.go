@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
+	"github.com/httprunner/httprunner/hrp/internal/redact"
 	"github.com/httprunner/httprunner/hrp/internal/version"
 )
 
@@ -36,9 +37,13 @@ Copyright 2021 debugtalk`,
 		if runtime.GOOS == "windows" {
 			noColor = true
 		}
+		// mask secrets in logs
+		out := redact.Writer(os.Stderr)
 		if !logJSON {
-			log.Logger = zerolog.New(zerolog.ConsoleWriter{NoColor: noColor, Out: os.Stderr}).With().Timestamp().Logger()
+			log.Logger = zerolog.New(zerolog.ConsoleWriter{NoColor: noColor, Out: out}).With().Timestamp().Logger()
 			log.Info().Msg("Set log to color console other than JSON format.")
+		} else {
+			log.Logger = zerolog.New(out).With().Timestamp().Logger()
 		}
 	},
 	Version: version.VERSION,
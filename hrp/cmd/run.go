@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/httprunner/httprunner/hrp"
@@ -41,7 +42,35 @@ var runCmd = &cobra.Command{
 		if proxyUrl != "" {
 			runner.SetProxyUrl(proxyUrl)
 		}
-		err := runner.Run(paths...)
+		if dryRun {
+			runner.SetDryRun(true)
+		}
+		if exportCurl {
+			// curl command lines are exported in tests summary
+			runner.SetExportCurl(true).SetSaveTests(true)
+		}
+		if updateSnapshots {
+			runner.SetUpdateSnapshots(true)
+		}
+		if softAssert {
+			runner.SetSoftAssert(true)
+		}
+		if exportOnFailure {
+			runner.SetExportOnFailure(true)
+		}
+		opts, err := parseReplayFlags(cmd)
+		if err != nil {
+			log.Error().Err(err).Msg("parse flags failed")
+			os.Exit(1)
+		}
+		runner.SetSeed(opts.seed)
+		if opts.frozenTime != nil {
+			runner.SetFrozenTime(*opts.frozenTime)
+		}
+		if opts.timeOffset != 0 {
+			runner.SetTimeOffset(opts.timeOffset)
+		}
+		err = runner.Run(paths...)
 		if err != nil {
 			os.Exit(1)
 		}
@@ -55,6 +84,11 @@ var (
 	proxyUrl          string
 	saveTests         bool
 	genHTMLReport     bool
+	dryRun            bool
+	exportCurl        bool
+	updateSnapshots   bool
+	softAssert        bool
+	exportOnFailure   bool
 )
 
 func init() {
@@ -65,4 +99,10 @@ func init() {
 	runCmd.Flags().StringVarP(&proxyUrl, "proxy-url", "p", "", "set proxy url")
 	runCmd.Flags().BoolVarP(&saveTests, "save-tests", "s", false, "save tests summary")
 	runCmd.Flags().BoolVarP(&genHTMLReport, "gen-html-report", "g", false, "generate html report")
+	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "render requests without sending them or sleeping think time")
+	runCmd.Flags().BoolVar(&exportCurl, "export-curl", false, "export rendered requests as curl commands in tests summary, implies --save-tests")
+	runCmd.Flags().BoolVar(&updateSnapshots, "update-snapshots", false, "create or rewrite snapshot files with current values instead of comparing with them")
+	runCmd.Flags().BoolVar(&softAssert, "soft-assert", false, "evaluate all validators of each step and report every failure")
+	runCmd.Flags().BoolVar(&exportOnFailure, "export-on-failure", false, "export extracted variables of steps failed in soft assert mode, used with --continue-on-failure")
+	addReplayFlags(runCmd)
 }
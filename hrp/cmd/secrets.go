@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/httprunner/httprunner/hrp/internal/secrets"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "encrypt, decrypt or edit secrets file of testcase variables",
+	Long: `Secrets file is a YAML or JSON mapping of variables encrypted with AES-256-GCM, which could be
+referenced by secrets_file in testcase config and is decrypted in memory when running testcases.
+
+Secrets key is loaded from --key-file, or environment variable ` + secrets.KeyEnv + `,
+or the key file specified by environment variable ` + secrets.KeyFileEnv + `.`,
+}
+
+var secretsEncryptCmd = &cobra.Command{
+	Use:   "encrypt $plain_file",
+	Short: "encrypt plain YAML/JSON variables file",
+	Example: `  $ hrp secrets encrypt secrets.yml	# encrypt to secrets.yml.enc
+  $ hrp secrets encrypt secrets.yml -o secrets.enc`,
+	Args: cobra.ExactValidArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		setLogLevel(logLevel)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := secrets.LoadKey(keyFile)
+		if err != nil {
+			return err
+		}
+		plaintext, err := os.ReadFile(args[0])
+		if err != nil {
+			return errors.Wrap(err, "read plain file failed")
+		}
+		if secrets.IsEncrypted(plaintext) {
+			return errors.Errorf("%s is already encrypted", args[0])
+		}
+		if _, err := secrets.Unmarshal(plaintext); err != nil {
+			return err
+		}
+		encrypted, err := secrets.Encrypt(plaintext, key)
+		if err != nil {
+			return err
+		}
+		output := secretsOutput
+		if output == "" {
+			output = args[0] + ".enc"
+		}
+		if err := os.WriteFile(output, encrypted, 0o600); err != nil {
+			return errors.Wrap(err, "write secrets file failed")
+		}
+		log.Info().Str("output", output).Msg("encrypt secrets success")
+		return nil
+	},
+}
+
+var secretsDecryptCmd = &cobra.Command{
+	Use:   "decrypt $secrets_file",
+	Short: "decrypt secrets file to stdout or plain file",
+	Example: `  $ hrp secrets decrypt secrets.enc	# print to stdout
+  $ hrp secrets decrypt secrets.enc -o secrets.yml`,
+	Args: cobra.ExactValidArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		setLogLevel(logLevel)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plaintext, err := decryptSecretsFile(args[0])
+		if err != nil {
+			return err
+		}
+		if secretsOutput == "" {
+			_, err = os.Stdout.Write(plaintext)
+			return err
+		}
+		if err := os.WriteFile(secretsOutput, plaintext, 0o600); err != nil {
+			return errors.Wrap(err, "write plain file failed")
+		}
+		log.Info().Str("output", secretsOutput).Msg("decrypt secrets success")
+		return nil
+	},
+}
+
+var secretsEditCmd = &cobra.Command{
+	Use:   "edit $secrets_file",
+	Short: "edit secrets file with $EDITOR, created if not exists",
+	Long: `Decrypt secrets file to a temporary file only readable by current user, open it with $VISUAL or $EDITOR
+(vi by default), and encrypt it back once the editor exits. The temporary file is removed afterwards.`,
+	Example: `  $ EDITOR=nano hrp secrets edit secrets.enc`,
+	Args:    cobra.ExactValidArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		setLogLevel(logLevel)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := secrets.LoadKey(keyFile)
+		if err != nil {
+			return err
+		}
+		path := args[0]
+		var plaintext []byte
+		if _, err := os.Stat(path); err == nil {
+			if plaintext, err = decryptSecretsFile(path); err != nil {
+				return err
+			}
+		} else if os.IsNotExist(err) {
+			plaintext = []byte("# secret variables in YAML format, e.g.\n# password: p@ssw0rd\n")
+		} else {
+			return errors.Wrap(err, "stat secrets file failed")
+		}
+
+		edited, err := editInTempFile(plaintext, filepath.Ext(strings.TrimSuffix(path, ".enc")))
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(edited, plaintext) {
+			log.Info().Str("path", path).Msg("secrets not changed")
+			return nil
+		}
+		if _, err := secrets.Unmarshal(edited); err != nil {
+			return err
+		}
+		encrypted, err := secrets.Encrypt(edited, key)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, encrypted, 0o600); err != nil {
+			return errors.Wrap(err, "write secrets file failed")
+		}
+		log.Info().Str("path", path).Msg("edit secrets success")
+		return nil
+	},
+}
+
+func decryptSecretsFile(path string) ([]byte, error) {
+	key, err := secrets.LoadKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read secrets file failed")
+	}
+	return secrets.Decrypt(data, key)
+}
+
+// editInTempFile writes content to temporary file, opens it with editor and returns edited content
+func editInTempFile(content []byte, ext string) ([]byte, error) {
+	if ext == "" {
+		ext = ".yml"
+	}
+	file, err := os.CreateTemp("", "hrp-secrets-*"+ext)
+	if err != nil {
+		return nil, errors.Wrap(err, "create temp file failed")
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, "write temp file failed")
+	}
+	file.Close()
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// editor may contain arguments, e.g. code --wait
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], file.Name())...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return nil, errors.Wrapf(err, "run editor %s failed", editor)
+	}
+	return os.ReadFile(file.Name())
+}
+
+var (
+	keyFile       string
+	secretsOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+	secretsCmd.AddCommand(secretsEncryptCmd, secretsDecryptCmd, secretsEditCmd)
+	secretsCmd.PersistentFlags().StringVar(&keyFile, "key-file", "", "specify secrets key file")
+	secretsEncryptCmd.Flags().StringVarP(&secretsOutput, "output", "o", "", "specify output file, default to $plain_file.enc")
+	secretsDecryptCmd.Flags().StringVarP(&secretsOutput, "output", "o", "", "specify output plain file, default to stdout")
+}
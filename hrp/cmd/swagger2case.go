@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/httprunner/httprunner/hrp/internal/swagger2case"
+)
+
+// swagger2caseCmd represents the swagger2case command
+var swagger2caseCmd = &cobra.Command{
+	Use:   "swagger2case $openapi_path...",
+	Short: "convert OpenAPI 3 document to api files and smoke testcases",
+	Long: `convert OpenAPI 3 document to json/yaml api files and smoke testcases,
+each operation is converted to api/<operationId> file and each tag is converted to testcases/<tag>_smoke file.
+Referenced api paths are relative to output directory, run smoke testcases in output directory.
+Files modified by hand are kept unchanged when converting again.`,
+	Example: `  $ hrp swagger2case petstore.yaml	# convert to api/*.json and testcases/*_smoke.json
+  $ hrp swagger2case petstore.yaml -y -d demo	# convert to demo/api/*.yaml and demo/testcases/*_smoke.yaml`,
+	Args: cobra.MinimumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		setLogLevel(logLevel)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var outputFiles []string
+		for _, arg := range args {
+			// must choose one
+			if !genYAMLFlag && !genJSONFlag {
+				return errors.New("please select convert format type")
+			}
+			var outputPaths []string
+			var err error
+
+			s := swagger2case.NewSwagger(arg)
+
+			// specify output dir
+			if outputDir != "" {
+				s.SetOutputDir(outputDir)
+			}
+
+			// generate json/yaml files
+			if genYAMLFlag {
+				outputPaths, err = s.GenYAML()
+			} else {
+				outputPaths, err = s.GenJSON() // default
+			}
+			if err != nil {
+				return err
+			}
+			outputFiles = append(outputFiles, outputPaths...)
+		}
+		log.Info().Strs("output", outputFiles).Msg("convert testcase success")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(swagger2caseCmd)
+	swagger2caseCmd.Flags().BoolVarP(&genJSONFlag, "to-json", "j", true, "convert to JSON format")
+	swagger2caseCmd.Flags().BoolVarP(&genYAMLFlag, "to-yaml", "y", false, "convert to YAML format")
+	swagger2caseCmd.Flags().StringVarP(&outputDir, "output-dir", "d", "", "specify output directory, default to the same dir with openapi file")
+}
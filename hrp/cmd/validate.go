@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/httprunner/httprunner/hrp"
+	"github.com/httprunner/httprunner/hrp/internal/json"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate $path...",
+	Short: "validate testcases without running them",
+	Long: `Validate yaml/json testcase files strictly without running them. Unknown fields, referenced
+api/testcase not found, variables not defined or extracted before use, and assertions or functions
+not found in builtin or plugin are reported as issues with file:line:column positions.
+
+Exit with code 1 if any error is found, warnings are only reported.`,
+	Example: `  $ hrp validate demo.yaml	# validate specified testcase file
+  $ hrp validate testcases/	# validate testcases in specified folder
+  $ hrp validate testcases/ --format json	# print issues in json format`,
+	Args: cobra.MinimumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		setLogLevel(logLevel)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if validateFormat != "text" && validateFormat != "json" {
+			return errors.Errorf("unsupported format %s, should be text or json", validateFormat)
+		}
+		issues, err := hrp.Lint(args...)
+		if err != nil {
+			return err
+		}
+
+		if validateFormat == "json" {
+			if issues == nil {
+				issues = []*hrp.LintIssue{}
+			}
+			output, err := json.MarshalIndent(issues, "", "    ")
+			if err != nil {
+				return errors.Wrap(err, "marshal issues failed")
+			}
+			fmt.Println(string(output))
+		} else {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		}
+
+		errorsCount := 0
+		for _, issue := range issues {
+			if issue.Severity == "error" {
+				errorsCount++
+			}
+		}
+		log.Info().Int("issues", len(issues)).Int("errors", errorsCount).Msg("validate testcases finished")
+		if errorsCount > 0 {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var validateFormat string
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format of issues, text or json")
+}
@@ -0,0 +1,100 @@
+package hrp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	assertAnyOf = "any_of"
+	assertAllOf = "all_of"
+	assertNot   = "not"
+)
+
+func isCombinator(validator Validator) bool {
+	return validator.AnyOf != nil || validator.AllOf != nil || validator.Not != nil
+}
+
+// combinatorValidators returns combinator name and its nested validators
+func combinatorValidators(validator Validator) (string, []Validator) {
+	switch {
+	case validator.AnyOf != nil:
+		return assertAnyOf, validator.AnyOf
+	case validator.AllOf != nil:
+		return assertAllOf, validator.AllOf
+	default:
+		return assertNot, []Validator{*validator.Not}
+	}
+}
+
+// validateCombinator validates nested validators of any_of/all_of/not, all nested validators are evaluated
+// and their results are kept as children of the combinator result, thus the result tree could be rendered.
+func (v *responseObject) validateCombinator(validator Validator, variablesMapping map[string]interface{}) error {
+	assertMethod, validators := combinatorValidators(validator)
+
+	// evaluate nested validators with separated results, and failures of nested validators
+	// should not fail the test directly, e.g. any_of passes even if some validators failed.
+	results, t := v.validationResults, v.t
+	v.validationResults, v.t = nil, &testing.T{}
+	var failures []string
+	for _, nested := range validators {
+		count := len(v.validationResults)
+		err := v.validate(nested, variablesMapping)
+		if err == nil {
+			continue
+		}
+		if len(v.validationResults) == count {
+			// no result recorded, e.g. unexpected assert method, which is not assertion failure
+			v.validationResults, v.t = results, t
+			return err
+		}
+		failures = append(failures, err.Error())
+	}
+	children := v.validationResults
+	v.validationResults, v.t = results, t
+
+	var result bool
+	switch assertMethod {
+	case assertAnyOf:
+		result = len(failures) < len(validators)
+	case assertAllOf:
+		result = len(failures) == 0
+	case assertNot:
+		result = len(failures) == 1
+	}
+
+	validResult := &validationResult{
+		Validator: Validator{
+			Assert:  assertMethod,
+			Message: validator.Message,
+		},
+		CheckResult: "fail",
+		Children:    children,
+	}
+	if result {
+		validResult.CheckResult = "pass"
+	}
+	v.validationResults = append(v.validationResults, validResult)
+	log.Info().
+		Str("assertMethod", assertMethod).
+		Int("validators", len(validators)).
+		Int("failures", len(failures)).
+		Bool("result", result).
+		Msgf("validate %s", assertMethod)
+	if result {
+		return nil
+	}
+
+	v.t.Fail()
+	if assertMethod == assertNot {
+		return errors.New(fmt.Sprintf(
+			"do assertion failed, assertMethod: not, nested validator passed, checkExpr: %v, assertMethod: %v, expectValue: %v",
+			validator.Not.Check, validator.Not.Assert, validator.Not.Expect))
+	}
+	return errors.New(fmt.Sprintf("do assertion failed, assertMethod: %s, %d of %d validators failed: %s",
+		assertMethod, len(failures), len(validators), strings.Join(failures, "; ")))
+}
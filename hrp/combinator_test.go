@@ -0,0 +1,159 @@
+package hrp
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCombinator(t *testing.T) {
+	testData := []struct {
+		validator Validator
+		result    string
+		children  []string
+	}{
+		{
+			Validator{AnyOf: []Validator{
+				{Check: "status_code", Assert: "equals", Expect: 200},
+				{Check: "status_code", Assert: "equals", Expect: 204},
+			}, Message: "status is 200 or 204"},
+			"pass", []string{"pass", "fail"},
+		},
+		{
+			Validator{AnyOf: []Validator{
+				{Check: "body.items", Assert: "length_equals", Expect: 0},
+				{Check: "body.next", Assert: "equals", Expect: nil},
+			}},
+			"fail", []string{"fail", "fail"},
+		},
+		{
+			Validator{AllOf: []Validator{
+				{Check: "body.items", Assert: "not_contains", Expect: "c"},
+				{Check: "body.name", Assert: "not_startswith", Expect: "cat"},
+				{Not: &Validator{Check: "body.name", Assert: "regex_match", Expect: "^\\d+$"}},
+			}},
+			"pass", []string{"pass", "pass", "pass"},
+		},
+		{
+			Validator{Not: &Validator{AnyOf: []Validator{
+				{Check: "body.name", Assert: "equals", Expect: "doggie"},
+				{Check: "body.name", Assert: "equals", Expect: "kitty"},
+			}}},
+			"fail", []string{"pass"},
+		},
+	}
+
+	for _, data := range testData {
+		respObj := newTestResponseObject(t, `{"items": ["a", "b"], "next": "/pets?page=2", "name": "doggie"}`)
+		respObj.t = &testing.T{}
+		err := respObj.Validate([]interface{}{data.validator}, map[string]interface{}{})
+		if data.result == "pass" && !assert.NoError(t, err) {
+			t.Fail()
+		}
+		if data.result == "fail" && !assert.Error(t, err) {
+			t.Fail()
+		}
+		if !assert.Len(t, respObj.validationResults, 1) {
+			t.FailNow()
+		}
+		result := respObj.validationResults[0]
+		if !assert.Equal(t, data.result, result.CheckResult) {
+			t.Fail()
+		}
+		var children []string
+		for _, child := range result.Children {
+			children = append(children, child.CheckResult)
+		}
+		if !assert.Equal(t, data.children, children) {
+			t.Fail()
+		}
+	}
+}
+
+func TestValidateCombinatorNotFailTest(t *testing.T) {
+	// failures of nested validators should not fail the test if combinator passed
+	respObj := newTestResponseObject(t, `{"status": "ok"}`)
+	validator := Validator{AnyOf: []Validator{
+		{Check: "body.status", Assert: "equals", Expect: "error"},
+		{Check: "body.status", Assert: "equals", Expect: "ok"},
+	}}
+	if !assert.NoError(t, respObj.Validate([]interface{}{validator}, map[string]interface{}{})) {
+		t.Fail()
+	}
+
+	// unexpected assert method is not regarded as assertion failure
+	validator = Validator{Not: &Validator{Check: "body.status", Assert: "not_exist", Expect: "ok"}}
+	err := respObj.Validate([]interface{}{validator}, map[string]interface{}{})
+	if !assert.Error(t, err) || !assert.Contains(t, err.Error(), "unexpected assertMethod") {
+		t.Fail()
+	}
+}
+
+func TestConvertCombinatorValidator(t *testing.T) {
+	validators := []interface{}{
+		map[string]interface{}{
+			"any_of": []interface{}{
+				map[string]interface{}{"check": "status_code", "assert": "equals", "expect": 200},
+				map[string]interface{}{"eq": []interface{}{"status_code", 204}},
+			},
+			"msg": "status is 200 or 204",
+		},
+		map[string]interface{}{
+			"not": map[string]interface{}{"check": "body.items", "assert": "contains", "expect": 3},
+		},
+	}
+	if err := convertCompatValidator(validators); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	expected := []interface{}{
+		Validator{
+			AnyOf: []Validator{
+				{Check: "status_code", Assert: "equals", Expect: 200},
+				{Check: "status_code", Assert: "eq", Expect: 204},
+			},
+			Message: "status is 200 or 204",
+		},
+		Validator{Not: &Validator{Check: "body.items", Assert: "contains", Expect: 3}},
+	}
+	if !assert.Equal(t, expected, validators) {
+		t.Fail()
+	}
+
+	invalid := []interface{}{map[string]interface{}{"all_of": []interface{}{}}}
+	if err := convertCompatValidator(invalid); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestRenderValidationResultTree(t *testing.T) {
+	summary := newOutSummary()
+	caseSummary := newSummary()
+	caseSummary.Records = []*stepData{{
+		Name:     "combinator",
+		StepType: stepTypeRequest,
+		Data: &SessionData{
+			ReqResps: &reqResps{},
+			Validators: []*validationResult{{
+				Validator:   Validator{Assert: assertAnyOf, Message: "status is 200 or 204"},
+				CheckResult: "pass",
+				Children: []*validationResult{
+					{Validator: Validator{Check: "status_code", Assert: "equals", Expect: 200}, CheckValue: 204, CheckResult: "fail"},
+					{Validator: Validator{Check: "status_code", Assert: "equals", Expect: 204}, CheckValue: 204, CheckResult: "pass"},
+				},
+			}},
+		},
+	}}
+	summary.appendCaseSummary(caseSummary)
+
+	var buffer bytes.Buffer
+	tmpl := template.Must(template.New("report").Parse(reportTemplate))
+	if err := tmpl.Execute(&buffer, summary); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	report := buffer.String()
+	if !assert.Contains(t, report, `class="nested"`) || !assert.Contains(t, report, "status is 200 or 204") {
+		t.Fail()
+	}
+}
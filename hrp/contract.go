@@ -0,0 +1,113 @@
+package hrp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/openapi"
+)
+
+const assertOpenAPI = "openapi"
+
+// contract holds OpenAPI document and request info for validating response against matched operation
+type contract struct {
+	doc            *openapi.Document
+	method         string
+	path           string
+	allowUnmatched bool // record unmatched request as unchecked instead of failure
+}
+
+// loadOpenAPI loads OpenAPI document specified in config, documents are cached in runner,
+// relative path is located in project root directory like referenced api and testcase.
+func (r *HRPRunner) loadOpenAPI(cfg *TConfig) (*openapi.Document, error) {
+	path := cfg.OpenAPI
+	if !filepath.IsAbs(path) && cfg.Path != "" {
+		projectRootDir, err := getProjectRootDirPath(cfg.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get project root dir")
+		}
+		path = filepath.Join(projectRootDir, path)
+	}
+	if doc, ok := r.openapiDocs.Load(path); ok {
+		return doc.(*openapi.Document), nil
+	}
+	doc, err := openapi.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	r.openapiDocs.Store(path, doc)
+	return doc, nil
+}
+
+// validateContract validates response against matched OpenAPI operation,
+// each violation is recorded as a failed validation result with JSON pointer as check item.
+// Request matching no operation fails unless unmatched requests are allowed in config.
+func (v *responseObject) validateContract() error {
+	c := v.contract
+	mo := c.doc.FindOperation(c.method, c.path)
+	if mo == nil {
+		result := &validationResult{
+			Validator: Validator{
+				Check:   "/",
+				Assert:  assertOpenAPI,
+				Expect:  fmt.Sprintf("%s %s", c.method, c.path),
+				Message: "no openapi operation matched",
+			},
+		}
+		v.validationResults = append(v.validationResults, result)
+		if c.allowUnmatched {
+			log.Warn().Str("method", c.method).Str("path", c.path).Msg("no openapi operation matched, skip contract validation")
+			result.CheckResult = "unchecked"
+			return nil
+		}
+		result.CheckResult = "fail"
+		v.t.Fail()
+		return errors.Errorf("openapi contract validation failed, no operation matched %s %s", c.method, c.path)
+	}
+	operation := fmt.Sprintf("%s %s", mo.Method, mo.Path)
+
+	respMap, _ := v.respObjMeta.(map[string]interface{})
+	statusCode, _ := strconv.Atoi(fmt.Sprint(respMap["status_code"]))
+	headers := make(map[string]string)
+	if respHeaders, ok := respMap["headers"].(map[string]interface{}); ok {
+		for key, value := range respHeaders {
+			headers[key] = fmt.Sprint(value)
+		}
+	}
+
+	violations := c.doc.ValidateResponse(mo, statusCode, headers, respMap["body"])
+	log.Info().Str("operation", operation).Int("violations", len(violations)).Msg("validate openapi contract")
+	if len(violations) == 0 {
+		v.validationResults = append(v.validationResults, &validationResult{
+			Validator: Validator{
+				Check:   "/",
+				Assert:  assertOpenAPI,
+				Expect:  operation,
+				Message: "response conforms to openapi operation",
+			},
+			CheckValue:  statusCode,
+			CheckResult: "pass",
+		})
+		return nil
+	}
+
+	for _, violation := range violations {
+		v.validationResults = append(v.validationResults, &validationResult{
+			Validator: Validator{
+				Check:   violation.Path,
+				Assert:  assertOpenAPI,
+				Expect:  operation,
+				Message: violation.Message,
+			},
+			CheckValue:  violation.Value,
+			CheckResult: "fail",
+		})
+	}
+	v.t.Fail()
+	return errors.Errorf("openapi contract validation failed for %s with %d violations, first: %s",
+		operation, len(violations), violations[0].Error())
+}
@@ -0,0 +1,121 @@
+package hrp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func startPetstoreServer(t *testing.T) string {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/pets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": "00000000-0000-0000-0000-000000000001", "name": "doggie", "status": "sold"}]`))
+	})
+	// breaking change: id becomes integer and name is missing
+	mux.HandleFunc("/v1/pets/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "status": "lost"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRunStepWithOpenAPIContract(t *testing.T) {
+	baseURL := startPetstoreServer(t)
+	testcase := &TestCase{
+		Config: NewConfig("openapi contract").
+			SetBaseURL(baseURL).
+			SetOpenAPI("../examples/data/openapi/petstore.yaml"),
+		TestSteps: []IStep{
+			NewStep("list pets").
+				GET("/v1/pets").
+				Validate().
+				AssertEqual("status_code", 200, "check status code"),
+			NewStep("show pet").
+				GET("/v1/pets/1"),
+		},
+	}
+	runner := NewRunner(nil).newCaseRunner(testcase)
+	if err := runner.parseConfig(testcase.Config); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	stepResult, err := runner.runStep(0, testcase.Config)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	validators := stepResult.Data.(*SessionData).Validators
+	if !assert.Len(t, validators, 2) {
+		t.FailNow()
+	}
+	if !assert.Equal(t, assertOpenAPI, validators[1].Assert) || !assert.Equal(t, "pass", validators[1].CheckResult) {
+		t.Fail()
+	}
+
+	// violations are reported with JSON pointer even though no validator is written
+	stepResult, err = runner.runStep(1, testcase.Config)
+	if !assert.Error(t, err) {
+		t.Fail()
+	}
+	var checks []string
+	for _, result := range stepResult.Data.(*SessionData).Validators {
+		if !assert.Equal(t, "fail", result.CheckResult) {
+			t.Fail()
+		}
+		checks = append(checks, result.Check)
+	}
+	if !assert.Equal(t, []string{"/body/name", "/body/id", "/body/status"}, checks) {
+		t.Fail()
+	}
+}
+
+func TestRunStepWithOpenAPIUnmatched(t *testing.T) {
+	baseURL := startPetstoreServer(t)
+	testcase := &TestCase{
+		Config: NewConfig("openapi contract").
+			SetBaseURL(baseURL).
+			SetOpenAPI("../examples/data/openapi/petstore.yaml"),
+		TestSteps: []IStep{
+			NewStep("list stores").
+				GET("/v1/stores"),
+		},
+	}
+	runner := NewRunner(nil).newCaseRunner(testcase)
+	if err := runner.parseConfig(testcase.Config); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	// request matching no operation fails by default
+	stepResult, err := runner.runStep(0, testcase.Config)
+	if !assert.Error(t, err) {
+		t.Fail()
+	}
+	validators := stepResult.Data.(*SessionData).Validators
+	if !assert.Len(t, validators, 1) || !assert.Equal(t, "fail", validators[0].CheckResult) {
+		t.FailNow()
+	}
+
+	testcase.Config.AllowOpenAPIUnmatched()
+	stepResult, err = runner.runStep(0, testcase.Config)
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	validators = stepResult.Data.(*SessionData).Validators
+	if !assert.Len(t, validators, 1) || !assert.Equal(t, "unchecked", validators[0].CheckResult) {
+		t.Fail()
+	}
+}
+
+func TestLoadOpenAPIFailed(t *testing.T) {
+	testcase := &TestCase{
+		Config: NewConfig("openapi contract").SetOpenAPI("not_exist.yaml"),
+	}
+	runner := NewRunner(nil).newCaseRunner(testcase)
+	if err := runner.parseConfig(testcase.Config); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
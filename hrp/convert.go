@@ -15,41 +15,90 @@ import (
 func convertCompatValidator(Validators []interface{}) (err error) {
 	for i, iValidator := range Validators {
 		validatorMap := iValidator.(map[string]interface{})
-		validator := Validator{}
-		_, checkExisted := validatorMap["check"]
-		_, assertExisted := validatorMap["assert"]
-		_, expectExisted := validatorMap["expect"]
-		// check priority: HRP > HttpRunner
-		if checkExisted && assertExisted && expectExisted {
-			// HRP validator format
-			validator.Check = validatorMap["check"].(string)
-			validator.Assert = validatorMap["assert"].(string)
-			validator.Expect = validatorMap["expect"]
-			if msg, existed := validatorMap["msg"]; existed {
-				validator.Message = msg.(string)
-			}
-			validator.Check = convertCheckExpr(validator.Check)
-			Validators[i] = validator
-		} else if len(validatorMap) == 1 {
-			// HttpRunner validator format
-			for assertMethod, iValidatorContent := range validatorMap {
-				checkAndExpect := iValidatorContent.([]interface{})
-				if len(checkAndExpect) != 2 {
-					return fmt.Errorf("unexpected validator format: %v", validatorMap)
-				}
-				validator.Check = checkAndExpect[0].(string)
-				validator.Assert = assertMethod
-				validator.Expect = checkAndExpect[1]
-			}
-			validator.Check = convertCheckExpr(validator.Check)
-			Validators[i] = validator
-		} else {
-			return fmt.Errorf("unexpected validator format: %v", validatorMap)
+		validator, err := convertValidator(validatorMap)
+		if err != nil {
+			return err
 		}
+		Validators[i] = validator
 	}
 	return nil
 }
 
+// convertValidator converts validator map to Validator, nested validators of logical combinators
+// any_of/all_of/not are converted recursively.
+func convertValidator(validatorMap map[string]interface{}) (validator Validator, err error) {
+	if msg, existed := validatorMap["msg"]; existed {
+		validator.Message = msg.(string)
+	}
+	if anyOf, existed := validatorMap["any_of"]; existed {
+		validator.AnyOf, err = convertValidators(anyOf)
+		return validator, err
+	}
+	if allOf, existed := validatorMap["all_of"]; existed {
+		validator.AllOf, err = convertValidators(allOf)
+		return validator, err
+	}
+	if not, existed := validatorMap["not"]; existed {
+		notMap, ok := not.(map[string]interface{})
+		if !ok {
+			return validator, fmt.Errorf("unexpected not validator format: %v", not)
+		}
+		notValidator, err := convertValidator(notMap)
+		if err != nil {
+			return validator, err
+		}
+		validator.Not = &notValidator
+		return validator, nil
+	}
+
+	_, checkExisted := validatorMap["check"]
+	_, assertExisted := validatorMap["assert"]
+	_, expectExisted := validatorMap["expect"]
+	// check priority: HRP > HttpRunner
+	if checkExisted && assertExisted && expectExisted {
+		// HRP validator format
+		validator.Check = validatorMap["check"].(string)
+		validator.Assert = validatorMap["assert"].(string)
+		validator.Expect = validatorMap["expect"]
+		validator.Check = convertCheckExpr(validator.Check)
+	} else if len(validatorMap) == 1 {
+		// HttpRunner validator format
+		for assertMethod, iValidatorContent := range validatorMap {
+			checkAndExpect := iValidatorContent.([]interface{})
+			if len(checkAndExpect) != 2 {
+				return validator, fmt.Errorf("unexpected validator format: %v", validatorMap)
+			}
+			validator.Check = checkAndExpect[0].(string)
+			validator.Assert = assertMethod
+			validator.Expect = checkAndExpect[1]
+		}
+		validator.Check = convertCheckExpr(validator.Check)
+	} else {
+		return validator, fmt.Errorf("unexpected validator format: %v", validatorMap)
+	}
+	return validator, nil
+}
+
+func convertValidators(iValidators interface{}) ([]Validator, error) {
+	validatorList, ok := iValidators.([]interface{})
+	if !ok || len(validatorList) == 0 {
+		return nil, fmt.Errorf("nested validators should be a non-empty list, got %v", iValidators)
+	}
+	validators := make([]Validator, 0, len(validatorList))
+	for _, iValidator := range validatorList {
+		validatorMap, ok := iValidator.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected validator format: %v", iValidator)
+		}
+		validator, err := convertValidator(validatorMap)
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, validator)
+	}
+	return validators, nil
+}
+
 func convertCompatTestCase(tc *TCase) (err error) {
 	defer func() {
 		if p := recover(); p != nil {
@@ -72,13 +121,19 @@ func convertCompatTestCase(tc *TCase) (err error) {
 		if err != nil {
 			return err
 		}
+
+		// 3. check extractor objects
+		err = checkExtractors(step.Extractors)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 // convertCheckExpr deals with check expression including hyphen
 func convertCheckExpr(checkExpr string) string {
-	if strings.Contains(checkExpr, textExtractorSubRegexp) {
+	if strings.Contains(checkExpr, textExtractorSubRegexp) || isSelectorExpr(checkExpr) {
 		return checkExpr
 	}
 	checkItems := strings.Split(checkExpr, ".")
@@ -118,6 +173,10 @@ func (path *APIPath) ToAPI() (*API, error) {
 		return nil, err
 	}
 	err = convertCompatValidator(api.Validators)
+	if err != nil {
+		return nil, err
+	}
+	err = checkExtractors(api.Extractors)
 	return api, err
 }
 
@@ -197,6 +256,10 @@ func (path *TestCasePath) ToTestCase() (*TestCase, error) {
 			testCase.TestSteps = append(testCase.TestSteps, &StepThinkTime{
 				step: step,
 			})
+		} else if step.Socket != nil {
+			testCase.TestSteps = append(testCase.TestSteps, &StepSocket{
+				step: step,
+			})
 		} else if step.Request != nil {
 			testCase.TestSteps = append(testCase.TestSteps, &StepRequestWithOptionalArgs{
 				step: step,
@@ -211,6 +211,9 @@ func TestConvertCheckExpr(t *testing.T) {
 		// check expression using regex
 		{"covering (.*) testing,", "covering (.*) testing,"},
 		{" (.*) a-b-c", " (.*) a-b-c"},
+		// check expression using xpath or css selector
+		{"xpath://div[@data-id='a-b']/text()", "xpath://div[@data-id='a-b']/text()"},
+		{"css:li.item-name > a", "css:li.item-name > a"},
 		// abnormal check expression
 		{"-", "\"-\""},
 		{"b-c", "\"b-c\""},
@@ -0,0 +1,51 @@
+package hrp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// toCurl converts rendered request to copy-pasteable curl command line
+func toCurl(req *http.Request, insecure bool) (string, error) {
+	parts := []string{"curl", "-X", req.Method}
+
+	// sort header keys to keep command line stable
+	keys := make([]string, 0, len(req.Header))
+	for key := range req.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range req.Header[key] {
+			parts = append(parts, "-H", shellQuote(fmt.Sprintf("%s: %s", key, value)))
+		}
+	}
+
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return "", err
+		}
+		// restore request body for sending
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 {
+			parts = append(parts, "--data-binary", shellQuote(string(body)))
+		}
+	}
+
+	// skip certificate verification if it is disabled in step or config
+	if insecure && req.URL.Scheme == "https" {
+		parts = append(parts, "-k")
+	}
+	parts = append(parts, shellQuote(req.URL.String()))
+	return strings.Join(parts, " "), nil
+}
+
+// shellQuote quotes string with single quotes for POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
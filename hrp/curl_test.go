@@ -0,0 +1,42 @@
+package hrp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToCurl(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://postman-echo.com/post?a=1", strings.NewReader(`{"name":"it's me"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "HttpRunnerPlus")
+	curl, err := toCurl(req, true)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	expected := `curl -X POST -H 'Content-Type: application/json' -H 'User-Agent: HttpRunnerPlus' ` +
+		`--data-binary '{"name":"it'\''s me"}' -k 'https://postman-echo.com/post?a=1'`
+	if !assert.Equal(t, expected, curl) {
+		t.Fail()
+	}
+
+	// certificate is verified unless verification is disabled
+	curl, err = toCurl(req, false)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	expected = `curl -X POST -H 'Content-Type: application/json' -H 'User-Agent: HttpRunnerPlus' ` +
+		`--data-binary '{"name":"it'\''s me"}' 'https://postman-echo.com/post?a=1'`
+	if !assert.Equal(t, expected, curl) {
+		t.Fail()
+	}
+
+	// request body should be restored
+	body, _ := io.ReadAll(req.Body)
+	if !assert.Equal(t, `{"name":"it's me"}`, string(body)) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,288 @@
+package hrp
+
+import (
+	builtinJSON "encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/maja42/goval"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+/*
+Expression is evaluated in ${ expr } notation, variables are referenced with $ prefix, e.g.
+${ $a + $b * 2 }, ${ $status == 200 && $count > 0 }, ${ $resp.items[0].id }, ${ $vip ? "gold" : "normal" }.
+Builtin and plugin functions could also be called in expression, e.g. ${ max($a, $b) > 0 }.
+Notice: ${var} and ${func($a)} are not regarded as expressions, which are parsed the same way as before,
+and ${...} which could not be parsed as expression is kept as literal text, e.g. ${} and ${HOME:-x} in shell scripts.
+*/
+
+var regexCompileVariableName = regexp.MustCompile(fmt.Sprintf(`^\s*%s\s*$`, regexVariable))
+
+// exprKeywords are identifiers supported in expression without $ prefix
+var exprKeywords = map[string]bool{"true": true, "false": true, "nil": true, "in": true, "IN": true}
+
+// matchExpression matches ${ expr } at the beginning of raw string, returns expression content
+// and length of the whole notation. Braces in string literals are ignored.
+func matchExpression(raw string) (string, int, bool) {
+	if !strings.HasPrefix(raw, "${") {
+		return "", 0, false
+	}
+	depth := 1
+	var quote rune
+	escaped := false
+	for i, r := range raw[2:] {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\' && quote != '`':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch r {
+		case '"', '\'', '`':
+			quote = r
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return raw[2 : i+2], i + 3, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// isExpression checks if ${...} notation is expression instead of variable ${var} or function ${func($a)}
+func isExpression(notation, content string) bool {
+	if regexCompileVariableName.MatchString(content) {
+		return false
+	}
+	if _, _, length, ok := matchFunction(notation); ok && length == len(notation) {
+		return false
+	}
+	return isValidExpression(content)
+}
+
+// matchLiteral matches ${...} at the beginning of raw string which is neither expression, function nor variable,
+// e.g. ${} and ${HOME:-x}, returns length of the notation which is kept as literal text.
+func matchLiteral(raw string) (int, bool) {
+	content, length, ok := matchExpression(raw)
+	if !ok || regexCompileVariableName.MatchString(content) {
+		return 0, false
+	}
+	if _, _, funcLength, ok := matchFunction(raw); ok && funcLength == length {
+		return 0, false
+	}
+	return length, !isValidExpression(content)
+}
+
+// isValidExpression checks if content could be parsed as expression. Goval evaluates while parsing,
+// thus content is evaluated with stub variables and functions, and only parsing errors are regarded as invalid.
+func isValidExpression(content string) bool {
+	if strings.TrimSpace(content) == "" {
+		return false
+	}
+	expr, varNames, funcNames, err := translateExpression(content)
+	if err != nil {
+		return false
+	}
+	variables := make(map[string]interface{}, len(varNames))
+	for _, varName := range varNames {
+		variables[varName] = nil
+	}
+	functions := make(map[string]goval.ExpressionFunction, len(funcNames))
+	for _, funcName := range funcNames {
+		functions[funcName] = func(args ...interface{}) (interface{}, error) {
+			return nil, nil
+		}
+	}
+	_, err = eval.Evaluate(expr, variables, functions)
+	return err == nil || !isParsingError(err)
+}
+
+// isParsingError checks if error is raised by goval parser or lexer, errors of evaluating stub values,
+// e.g. "syntax error: cannot access fields on type nil", are not parsing errors
+func isParsingError(err error) bool {
+	msg := err.Error()
+	return msg == "syntax error" || strings.HasPrefix(msg, "syntax error: unexpected") ||
+		strings.HasPrefix(msg, "parse error:") || strings.HasPrefix(msg, "unknown token")
+}
+
+// translateExpression translates expression to goval syntax: $var is converted to var,
+// and single quoted strings are converted to double quoted. Referenced variables and called functions are returned.
+// Identifiers without $ prefix should be functions, fields or keywords, e.g. HOME is invalid.
+func translateExpression(content string) (string, []string, []string, error) {
+	var b strings.Builder
+	var variables, functions []string
+	runes := []rune(content)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '"' || r == '`' || r == '\'':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && r != '`' {
+					j++
+				}
+			}
+			if j >= len(runes) {
+				return "", nil, nil, errors.Errorf("unclosed string in expression: %s", content)
+			}
+			if r == '\'' {
+				// 'it\'s "ok"' => "it's \"ok\""
+				b.WriteRune('"')
+				for k := i + 1; k < j; k++ {
+					switch {
+					case runes[k] == '\\' && runes[k+1] == '\'':
+						b.WriteRune('\'')
+						k++
+					case runes[k] == '\\':
+						b.WriteRune(runes[k])
+						b.WriteRune(runes[k+1])
+						k++
+					case runes[k] == '"':
+						b.WriteString(`\"`)
+					default:
+						b.WriteRune(runes[k])
+					}
+				}
+				b.WriteRune('"')
+			} else {
+				b.WriteString(string(runes[i : j+1]))
+			}
+			i = j + 1
+		case r == '$':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			name := string(runes[i+1 : j])
+			if !regexCompileVariableName.MatchString(name) {
+				return "", nil, nil, errors.Errorf("invalid variable reference in expression: %s", content)
+			}
+			variables = append(variables, name)
+			b.WriteString(name)
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			name := string(runes[i:j])
+			// identifier followed by ( is function call, unless it is field access, e.g. $a.b(
+			k := j
+			for k < len(runes) && unicode.IsSpace(runes[k]) {
+				k++
+			}
+			isField := i > 0 && runes[i-1] == '.'
+			switch {
+			case k < len(runes) && runes[k] == '(' && !isField:
+				functions = append(functions, name)
+			case !isField && !exprKeywords[name]:
+				return "", nil, nil, errors.Errorf("invalid identifier %s in expression: %s", name, content)
+			}
+			b.WriteString(name)
+			i = j
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+	return b.String(), variables, functions, nil
+}
+
+// evalExpression evaluates expression content of ${ expr } with variables mapping
+func (p *parser) evalExpression(content string, variablesMapping map[string]interface{}) (interface{}, error) {
+	expr, varNames, funcNames, err := translateExpression(content)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string]interface{}, len(varNames))
+	for _, varName := range varNames {
+		varValue, ok := variablesMapping[varName]
+		if !ok {
+			return nil, errors.Errorf("variable %s not found", varName)
+		}
+		variables[varName] = normalizeExprValue(varValue)
+	}
+	functions := make(map[string]goval.ExpressionFunction, len(funcNames))
+	for _, funcName := range funcNames {
+		funcName := funcName
+		functions[funcName] = func(args ...interface{}) (interface{}, error) {
+			result, err := p.callFunc(funcName, args...)
+			if err != nil {
+				return nil, err
+			}
+			return normalizeExprValue(result), nil
+		}
+	}
+
+	result, err := eval.Evaluate(expr, variables, functions)
+	if err != nil {
+		log.Error().Str("expr", content).Err(err).Msg("evaluate expression failed")
+		return nil, errors.Wrapf(err, "evaluate expression %s failed", strings.TrimSpace(content))
+	}
+	log.Info().Str("expr", content).Interface("output", result).Msg("evaluate expression success")
+	return result, nil
+}
+
+// findExpressionVariables returns variables referenced in expression
+func findExpressionVariables(content string) []string {
+	_, variables, _, err := translateExpression(content)
+	if err != nil {
+		return nil
+	}
+	return variables
+}
+
+// normalizeExprValue converts value to types supported in expression: nil, bool, int, float64, string,
+// []interface{} and map[string]interface{}
+func normalizeExprValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, int, float64, string:
+		return v
+	case builtinJSON.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case float32:
+		return float64(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint())
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			list[i] = normalizeExprValue(rv.Index(i).Interface())
+		}
+		return list
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		m := make(map[string]interface{}, rv.Len())
+		for _, key := range rv.MapKeys() {
+			m[key.String()] = normalizeExprValue(rv.MapIndex(key).Interface())
+		}
+		return m
+	}
+	return value
+}
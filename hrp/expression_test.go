@@ -0,0 +1,168 @@
+package hrp
+
+import (
+	builtinJSON "encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchExpression(t *testing.T) {
+	testData := []struct {
+		raw     string
+		content string
+		length  int
+		ok      bool
+	}{
+		{"${ $a + 1 }", " $a + 1 ", 11, true},
+		{"${ {\"a\": 1}.a }abc", " {\"a\": 1}.a ", 15, true},
+		{"${ $a == \"}\" }", " $a == \"}\" ", 14, true},
+		{"${ $a == '\\'}' }", " $a == '\\'}' ", 16, true},
+		{"${ $a + 1", "", 0, false},
+		{"$a", "", 0, false},
+	}
+	for _, data := range testData {
+		content, length, ok := matchExpression(data.raw)
+		if !assert.Equal(t, data.ok, ok, data.raw) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.content, content, data.raw) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.length, length, data.raw) {
+			t.Fail()
+		}
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	testData := []struct {
+		notation string
+		expected bool
+	}{
+		{"${var}", false},
+		{"${func($a, 1)}", false},
+		{"${func()}", false},
+		{"${ var }", false},
+		{"${ $a + 1 }", true},
+		{"${$a}", true},
+		{"${ max($a, 1) > 0 }", true},
+		{"${func('a', [1], k=${g()})}", false},
+		{"${func(1) + 1}", true},
+		{"${}", false},
+		{"${ }", false},
+		{"${HOME:-x}", false},
+		{"${ $a + }", false},
+		{"${ $a in [1, 2] && true }", true},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, isExpression(data.notation, data.notation[2:len(data.notation)-1]), data.notation) {
+			t.Fail()
+		}
+	}
+}
+
+func TestTranslateExpression(t *testing.T) {
+	expr, variables, functions, err := translateExpression(
+		` $resp.items[0].id > 1 && len($items) == max(1, $n) ? 'it\'s "ok"' : "a\"b" `)
+	if !assert.Nil(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, ` resp.items[0].id > 1 && len(items) == max(1, n) ? "it's \"ok\"" : "a\"b" `, expr) {
+		t.Fail()
+	}
+	if !assert.Equal(t, []string{"resp", "items", "n"}, variables) {
+		t.Fail()
+	}
+	if !assert.Equal(t, []string{"len", "max"}, functions) {
+		t.Fail()
+	}
+
+	for _, content := range []string{"$ + 1", "$1 + 1", "'abc", "HOME:-x", "$a + b"} {
+		if _, _, _, err := translateExpression(content); !assert.Error(t, err, content) {
+			t.Fail()
+		}
+	}
+}
+
+func TestParseDataStringWithExpressions(t *testing.T) {
+	variablesMapping := map[string]interface{}{
+		"a":      1,
+		"b":      int64(2),
+		"price":  builtinJSON.Number("9.5"),
+		"name":   "hrp",
+		"vip":    true,
+		"items":  []interface{}{"x", "y"},
+		"resp":   map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": int64(1001)}}},
+		"status": 200,
+		"tags":   map[string]string{"env": "prod"},
+	}
+	testData := []struct {
+		expr   string
+		expect interface{}
+	}{
+		{"${ $a + $b * 3 }", 7},
+		{"${ $price * 2 }", 19.0},
+		{"${ $a / 2.0 }", 0.5},
+		{"${ $b % 2 == 0 }", true},
+		{"${ $status == 200 && !$vip }", false},
+		{"${ $status >= 400 || $vip }", true},
+		{`${ "hello " + $name }`, "hello hrp"},
+		{"${ 'hello ' + $name }", "hello hrp"},
+		{"${ $resp.items[0].id }", 1001},
+		{"${ $items[1] }", "y"},
+		{"${ $tags.env }", "prod"},
+		{`${ $vip ? "gold" : "normal" }`, "gold"},
+		{`${ md5($name) == md5("hrp") }`, true},
+		{"${ max($a, $b) + 1 }", 3.0},
+		{"id-${ $resp.items[0].id + 1 }-$name", "id-1002-hrp"},
+		{"${ $a + 1 }${ $b + 1 }", "23"},
+		{"${a}", 1},
+		{"${max($a, $b)}", 2.0},
+	}
+
+	parser := newParser()
+	for _, data := range testData {
+		parsedData, err := parser.parseData(data.expr, variablesMapping)
+		if !assert.NoError(t, err, data.expr) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expect, parsedData, data.expr) {
+			t.Fail()
+		}
+	}
+
+	for _, expr := range []string{"${ $undefined + 1 }", "${ not_found($a) }", "${ $name - 1 }"} {
+		if _, err := parser.parseData(expr, variablesMapping); !assert.Error(t, err, expr) {
+			t.Fail()
+		}
+	}
+}
+
+func TestParseDataStringWithLiteralNotations(t *testing.T) {
+	variablesMapping := map[string]interface{}{"a": 1, "name": "hrp"}
+	testData := []struct {
+		raw    string
+		expect interface{}
+	}{
+		{"${}", "${}"},
+		{"${HOME:-x}", "${HOME:-x}"},
+		{"${HOME:-/tmp}/$name-${ $a + 1 }", "${HOME:-/tmp}/hrp-2"},
+		{"echo ${ $a + } $a", "echo ${ $a + } 1"},
+	}
+	parser := newParser()
+	for _, data := range testData {
+		parsedData, err := parser.parseData(data.raw, variablesMapping)
+		if !assert.NoError(t, err, data.raw) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expect, parsedData, data.raw) {
+			t.Fail()
+		}
+	}
+
+	// variables in literal text are not referenced
+	if !assert.Equal(t, variableSet{"name": struct{}{}}, findallVariables("${HOME:-$a}/$name")) {
+		t.Fail()
+	}
+}
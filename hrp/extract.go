@@ -13,6 +13,20 @@ func (s *StepRequestExtraction) WithJmesPath(jmesPath string, varName string) *S
 	return s
 }
 
+// WithJsonPath sets the JSONPath expression to extract from the response.
+func (s *StepRequestExtraction) WithJsonPath(jsonPath string, varName string) *StepRequestExtraction {
+	return s.WithExtractor(Extractor{Type: extractorJSONPath, Expr: jsonPath}, varName)
+}
+
+// WithExtractor sets the extractor with type, default value and required flag to extract from the response.
+func (s *StepRequestExtraction) WithExtractor(extractor Extractor, varName string) *StepRequestExtraction {
+	if s.step.Extractors == nil {
+		s.step.Extractors = make(map[string]Extractor)
+	}
+	s.step.Extractors[varName] = extractor
+	return s
+}
+
 // Validate switches to step validation.
 func (s *StepRequestExtraction) Validate() *StepRequestValidation {
 	return &StepRequestValidation{
@@ -25,6 +39,9 @@ func (s *StepRequestExtraction) Name() string {
 }
 
 func (s *StepRequestExtraction) Type() string {
+	if s.step.Socket != nil {
+		return fmt.Sprintf("socket-%v", s.step.Socket.Network)
+	}
 	return fmt.Sprintf("request-%v", s.step.Request.Method)
 }
 
@@ -0,0 +1,210 @@
+package hrp
+
+import (
+	builtinJSON "encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/jsonpath"
+)
+
+// extractor types
+const (
+	extractorJmesPath = "jmespath"
+	extractorJSONPath = "jsonpath"
+	extractorRegex    = "regex"
+	extractorXPath    = "xpath"
+	extractorCSS      = "css"
+)
+
+const jsonPathExprPrefix = "jsonpath:"
+
+var errNotMatched = errors.New("nothing matched")
+
+// detectExtractorType detects extractor type by expression, e.g. xpath:, css: and jsonpath: prefixes,
+// regex if expression contains (.*), otherwise jmespath
+func detectExtractorType(expr string) string {
+	switch {
+	case strings.HasPrefix(expr, xpathExprPrefix):
+		return extractorXPath
+	case strings.HasPrefix(expr, cssExprPrefix):
+		return extractorCSS
+	case strings.HasPrefix(expr, jsonPathExprPrefix):
+		return extractorJSONPath
+	case strings.Contains(expr, textExtractorSubRegexp):
+		return extractorRegex
+	default:
+		return extractorJmesPath
+	}
+}
+
+// checkExtractors checks extractor objects loaded from testcase file, thus invalid extractors
+// are reported before running.
+func checkExtractors(extractors map[string]Extractor) error {
+	for varName, extractor := range extractors {
+		if extractor.Expr == "" {
+			return errors.Errorf("extractor expr of %s is empty", varName)
+		}
+		switch extractor.Type {
+		case "", extractorJmesPath, extractorJSONPath, extractorRegex, extractorXPath, extractorCSS:
+		default:
+			return errors.Errorf("unexpected extractor type of %s: %s", varName, extractor.Type)
+		}
+	}
+	return nil
+}
+
+// extract extracts value with extractor, default value is used if nothing matched,
+// error is returned if nothing matched for required extractor or expression is invalid.
+func (v *responseObject) extract(extractor *Extractor) (interface{}, error) {
+	extractorType := extractor.Type
+	if extractorType == "" {
+		extractorType = detectExtractorType(extractor.Expr)
+	}
+
+	var value interface{}
+	var err error
+	switch extractorType {
+	case extractorJmesPath:
+		value, err = v.queryJmespath(extractor.Expr)
+	case extractorJSONPath:
+		value, err = v.queryJSONPath(extractor.Expr)
+	case extractorRegex:
+		value, err = v.queryRegexp(extractor.Expr, extractor.All, extractor.Group)
+	case extractorXPath:
+		value, err = v.queryXPath(extractor.Expr)
+	case extractorCSS:
+		value, err = v.queryCSS(extractor.Expr)
+	default:
+		return nil, errors.Errorf("unexpected extractor type: %s", extractorType)
+	}
+	if err == nil && value == nil {
+		err = errNotMatched
+	}
+	if err == errNotMatched {
+		if extractor.Required {
+			return nil, errors.Errorf("%s %s: %v", extractorType, extractor.Expr, err)
+		}
+		return extractor.Default, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s", extractorType, extractor.Expr)
+	}
+	return value, nil
+}
+
+func (v *responseObject) queryJmespath(expr string) (interface{}, error) {
+	value, err := jmespath.Search(expr, v.respObjMeta)
+	if err != nil {
+		return nil, err
+	}
+	return convertJSONNumber(value), nil
+}
+
+// queryJSONPath searches response with JSONPath, e.g. $.body.items[*].id, list is returned unless path is definite
+func (v *responseObject) queryJSONPath(expr string) (interface{}, error) {
+	path, err := jsonpath.Compile(strings.TrimSpace(strings.TrimPrefix(expr, jsonPathExprPrefix)))
+	if err != nil {
+		return nil, err
+	}
+	values := path.Search(v.respObjMeta)
+	if len(values) == 0 {
+		return nil, errNotMatched
+	}
+	if path.Definite() {
+		return convertJSONNumber(values[0]), nil
+	}
+	for i, value := range values {
+		values[i] = convertJSONNumber(value)
+	}
+	return values, nil
+}
+
+// queryRegexp searches response body with regexp, returns capture group of the first match,
+// or capture groups of all matches as list if all is set
+func (v *responseObject) queryRegexp(expr string, all bool, group *int) (interface{}, error) {
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	if compiled.NumSubexp() > 0 {
+		index = 1
+	}
+	if group != nil {
+		index = *group
+	}
+	if index < 0 || index > compiled.NumSubexp() {
+		return nil, errors.Errorf("capture group %d out of range, %d groups in pattern", index, compiled.NumSubexp())
+	}
+
+	body := v.bodyText()
+	if !all {
+		match := compiled.FindStringSubmatch(body)
+		if match == nil {
+			return nil, errNotMatched
+		}
+		return match[index], nil
+	}
+	matches := compiled.FindAllStringSubmatch(body, -1)
+	if len(matches) == 0 {
+		return nil, errNotMatched
+	}
+	values := make([]interface{}, len(matches))
+	for i, match := range matches {
+		values[i] = match[index]
+	}
+	return values, nil
+}
+
+// bodyText returns raw response body, or body in string format if raw body is unavailable
+func (v *responseObject) bodyText() string {
+	if v.rawBody != nil {
+		return string(v.rawBody)
+	}
+	if respMap, ok := v.respObjMeta.(map[string]interface{}); ok {
+		if body, ok := respMap["body"].(string); ok {
+			return body
+		}
+		content, _ := json.Marshal(respMap["body"])
+		return string(content)
+	}
+	return ""
+}
+
+func convertJSONNumber(value interface{}) interface{} {
+	number, ok := value.(builtinJSON.Number)
+	if !ok {
+		return value
+	}
+	checkNumber, err := parseJSONNumber(number)
+	if err != nil {
+		log.Error().Interface("json number", number).Err(err).Msg("convert json number failed")
+	}
+	return checkNumber
+}
+
+// extractError aggregates errors of all failed extractors
+type extractError struct {
+	errs map[string]error
+}
+
+func (e *extractError) Error() string {
+	varNames := make([]string, 0, len(e.errs))
+	for varName := range e.errs {
+		varNames = append(varNames, varName)
+	}
+	sort.Strings(varNames)
+	var messages []string
+	for _, varName := range varNames {
+		messages = append(messages, fmt.Sprintf("%s: %v", varName, e.errs[varName]))
+	}
+	return fmt.Sprintf("extract variables failed, %s", strings.Join(messages, "; "))
+}
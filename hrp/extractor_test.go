@@ -0,0 +1,163 @@
+package hrp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractWithExtractor(t *testing.T) {
+	respObj := newTestResponseObject(t,
+		`{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}], "code": "x-12 y-34"}`)
+	group := 2
+	extractors := map[string]string{
+		"legacy":     "body.items[0].name",
+		"prefixed":   "jsonpath:$.body.items[0].id",
+		"overridden": "body.items[0].id",
+	}
+	typedExtractors := map[string]Extractor{
+		"jmespath":    {Expr: "body.items[1].id"},
+		"jsonpath":    {Type: "jsonpath", Expr: "$.body.items[?(@.id > 1)].name"},
+		"definite":    {Type: "jsonpath", Expr: "$.body.items[-1].id"},
+		"regex":       {Type: "regex", Expr: `"name": "(\w)"`},
+		"regex_all":   {Type: "regex", Expr: `"id": (\d+)`, All: true},
+		"regex_group": {Type: "regex", Expr: `([a-z])-(\d+)`, All: true, Group: &group},
+		"regex_whole": {Type: "regex", Expr: `[a-z]-\d+`},
+		"default":     {Expr: "body.missing", Default: "none"},
+		"no_default":  {Type: "jsonpath", Expr: "$.body.missing"},
+		"overridden":  {Type: "regex", Expr: `y-(\d+)`},
+	}
+	extractMapping, err := respObj.Extract(extractors, typedExtractors)
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	expected := map[string]interface{}{
+		"legacy":      "a",
+		"jmespath":    int64(2),
+		"jsonpath":    []interface{}{"b", "c"},
+		"definite":    int64(3),
+		"prefixed":    int64(1),
+		"regex":       "a",
+		"regex_all":   []interface{}{"1", "2", "3"},
+		"regex_group": []interface{}{"12", "34"},
+		"regex_whole": "x-12",
+		"default":     "none",
+		"no_default":  nil,
+		"overridden":  "34",
+	}
+	if !assert.Equal(t, expected, extractMapping) {
+		t.Fail()
+	}
+}
+
+func TestExtractRequired(t *testing.T) {
+	respObj := newTestResponseObject(t, `{"id": 1}`)
+	invalidGroup := 3
+	extractors := map[string]Extractor{
+		"id":      {Expr: "body.id", Required: true},
+		"token":   {Expr: "body.token", Required: true},
+		"invalid": {Type: "jsonpath", Expr: "body.id"},
+		"group":   {Type: "regex", Expr: `"id": (\d+)`, Group: &invalidGroup},
+	}
+	extractMapping, err := respObj.Extract(nil, extractors)
+	if !assert.Error(t, err) {
+		t.Fatal()
+	}
+	if !assert.Contains(t, err.Error(), "token: jmespath body.token: nothing matched") {
+		t.Fail()
+	}
+	if !assert.Contains(t, err.Error(), "invalid: jsonpath body.id") {
+		t.Fail()
+	}
+	if !assert.Contains(t, err.Error(), "group: regex") {
+		t.Fail()
+	}
+	// variables extracted successfully are kept
+	if !assert.Equal(t, map[string]interface{}{"id": int64(1)}, extractMapping) {
+		t.Fail()
+	}
+}
+
+func TestLoadExtractors(t *testing.T) {
+	dir := writeVariablesFiles(t, map[string]string{
+		"api.yml": `name: get
+request:
+    method: GET
+    url: /get
+extract:
+    token: body.token
+extractors:
+    ids:
+        type: jsonpath
+        expr: $.body[*].id
+        default: []
+    code: {type: regex, expr: code=(\d+), group: 1, all: true}
+`,
+		"invalid.yml": "name: get\nrequest: {method: GET, url: /get}\nextractors:\n    code: {type: unknown, expr: body}\n",
+		"empty.yml":   "name: get\nrequest: {method: GET, url: /get}\nextractors:\n    code: {type: regex}\n",
+	})
+
+	path := APIPath(filepath.Join(dir, "api.yml"))
+	api, err := path.ToAPI()
+	if !assert.Nil(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, map[string]string{"token": "body.token"}, api.Extract) {
+		t.Fail()
+	}
+	group := 1
+	expected := map[string]Extractor{
+		"ids":  {Type: "jsonpath", Expr: "$.body[*].id", Default: []interface{}{}},
+		"code": {Type: "regex", Expr: `code=(\d+)`, Group: &group, All: true},
+	}
+	if !assert.Equal(t, expected, api.Extractors) {
+		t.Fail()
+	}
+
+	for _, name := range []string{"invalid.yml", "empty.yml"} {
+		path := APIPath(filepath.Join(dir, name))
+		if _, err := path.ToAPI(); !assert.Error(t, err, name) {
+			t.Fail()
+		}
+	}
+}
+
+func TestRunCaseWithRequiredExtractor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"user": {"name": "hrp"}}`)
+	}))
+	defer server.Close()
+
+	testcase := &TestCase{
+		Config: NewConfig("required extractor").SetBaseURL(server.URL),
+		TestSteps: []IStep{
+			NewStep("login").
+				GET("/login").
+				Extract().
+				WithJsonPath("$.body.user.name", "name").
+				WithExtractor(Extractor{Expr: "body.user.role", Default: "guest"}, "role").
+				WithExtractor(Extractor{Expr: "body.token", Required: true}, "token").
+				Validate().
+				AssertEqual("status_code", 200, "check status code"),
+		},
+	}
+	caseRunner := NewRunner(nil).SetFailfast(false).newCaseRunner(testcase)
+	if err := caseRunner.run(); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	records := caseRunner.getSummary().Records
+	if !assert.False(t, records[0].Success) {
+		t.Fail()
+	}
+	if !assert.Contains(t, records[0].Attachment, "token: jmespath body.token: nothing matched") {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{"name": "hrp", "role": "guest"}, records[0].ExportVars) {
+		t.Fail()
+	}
+}
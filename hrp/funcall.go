@@ -0,0 +1,376 @@
+package hrp
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+/*
+Function call notation: ${func(arg1, arg2, key=value)}
+
+Arguments could be:
+- numbers, e.g. 123, -1.23
+- quoted strings, e.g. 'a,b', "http://x.com/?a=1&b=2", which could contain any character and are used literally
+  without parsing variables and functions, e.g. '$5' is not a variable
+- variables, e.g. $var, ${var}, and strings with variables, e.g. /api/$id
+- nested function calls, e.g. ${f(${g($a)})}
+- list and map literals, e.g. [1, 'a', $b], {"a": 1, b: [2, 3]}
+- true, false and null
+- keyword arguments, e.g. key=value, which are collected into a map appended as the last argument,
+  since plugin functions only accept positional arguments. Builtin functions accept keyword arguments
+  only if their last parameter could take the map, e.g. parameterize(path, stream=true).
+
+Other unquoted strings are kept as before, e.g. abc, a-b.
+*/
+
+// matchFunction matches function call notation ${func(...)} at the beginning of raw string,
+// returns function name, arguments string and length of the whole notation.
+func matchFunction(raw string) (funcName string, argsStr string, length int, ok bool) {
+	if !strings.HasPrefix(raw, "${") {
+		return "", "", 0, false
+	}
+	i := 2
+	for i < len(raw) && isIdentRune(rune(raw[i]), i == 2) {
+		i++
+	}
+	if i == 2 || i >= len(raw) || raw[i] != '(' {
+		return "", "", 0, false
+	}
+	funcName = raw[2:i]
+	end, err := scanBalanced(raw, i)
+	if err != nil || end+1 >= len(raw) || raw[end+1] != '}' {
+		return "", "", 0, false
+	}
+	return funcName, raw[i+1 : end], end + 2, true
+}
+
+func isIdentRune(r rune, first bool) bool {
+	if first {
+		return unicode.IsLetter(r) || r == '_'
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+var closingBrackets = map[byte]byte{'(': ')', '[': ']', '{': '}'}
+
+// scanBalanced returns index of the bracket closing the one at start, quoted strings are skipped
+func scanBalanced(s string, start int) (int, error) {
+	var stack []byte
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\'', '"':
+			end, err := scanQuoted(s, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case '(', '[', '{':
+			stack = append(stack, closingBrackets[c])
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != c {
+				return 0, errors.Errorf("unexpected %q at position %d", c, i)
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, errors.Errorf("unclosed %q", s[start])
+}
+
+// scanQuoted returns index of the quote closing the one at start, quote could be escaped with backslash
+func scanQuoted(s string, start int) (int, error) {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("unclosed string %s", s[start:])
+}
+
+// quotedString is quoted string argument, which is used literally without parsing variables and functions
+type quotedString string
+
+// keywordArguments are keyword arguments collected into a map, which is passed as the last argument
+type keywordArguments map[string]interface{}
+
+// parseFunctionArguments parses arguments string of function call, variables and nested function calls
+// are kept in string and parsed later with variables mapping, quoted strings are kept as quotedString.
+func parseFunctionArguments(argsStr string) ([]interface{}, error) {
+	p := &argsParser{input: argsStr}
+	arguments := []interface{}{}
+	var kwargs keywordArguments
+	if strings.TrimSpace(argsStr) == "" {
+		return arguments, nil
+	}
+	for {
+		p.skipSpaces()
+		key := p.parseKeyword()
+		value, err := p.parseValue(",", true)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse function arguments %s failed", argsStr)
+		}
+		if key != "" {
+			if kwargs == nil {
+				kwargs = make(keywordArguments)
+			}
+			if _, ok := kwargs[key]; ok {
+				return nil, errors.Errorf("keyword argument %s repeated in %s", key, argsStr)
+			}
+			kwargs[key] = value
+		} else if kwargs != nil {
+			return nil, errors.Errorf("positional argument follows keyword argument in %s", argsStr)
+		} else {
+			arguments = append(arguments, value)
+		}
+
+		p.skipSpaces()
+		if p.done() {
+			break
+		}
+		if p.input[p.pos] != ',' {
+			return nil, errors.Errorf("parse function arguments %s failed: unexpected %q at position %d",
+				argsStr, p.input[p.pos], p.pos)
+		}
+		p.pos++
+	}
+	if kwargs != nil {
+		arguments = append(arguments, kwargs)
+	}
+	return arguments, nil
+}
+
+type argsParser struct {
+	input string
+	pos   int
+}
+
+func (p *argsParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *argsParser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// parseKeyword parses key= of keyword argument, returns empty string if it is positional argument
+func (p *argsParser) parseKeyword() string {
+	i := p.pos
+	for i < len(p.input) && isIdentRune(rune(p.input[i]), i == p.pos) {
+		i++
+	}
+	if i == p.pos {
+		return ""
+	}
+	key := p.input[p.pos:i]
+	for i < len(p.input) && unicode.IsSpace(rune(p.input[i])) {
+		i++
+	}
+	// key=value, but not key==value
+	if i >= len(p.input) || p.input[i] != '=' || (i+1 < len(p.input) && p.input[i+1] == '=') {
+		return ""
+	}
+	p.pos = i + 1
+	p.skipSpaces()
+	return key
+}
+
+// parseValue parses one value until one of terminators at top level,
+// bare words at top level of arguments are kept as strings, e.g. abc, /api/$id.
+func (p *argsParser) parseValue(terminators string, topLevel bool) (interface{}, error) {
+	p.skipSpaces()
+	if p.done() || strings.IndexByte(terminators, p.input[p.pos]) != -1 {
+		// empty value, e.g. f(1,,2)
+		return nil, nil
+	}
+
+	switch c := p.input[p.pos]; {
+	case c == '\'' || c == '"':
+		end, err := scanQuoted(p.input, p.pos)
+		if err != nil {
+			return nil, err
+		}
+		s := unquote(p.input[p.pos+1:end], c)
+		p.pos = end + 1
+		return quotedString(s), p.expectEnd(terminators)
+	case c == '[':
+		return p.parseList()
+	case c == '{':
+		return p.parseMap()
+	}
+
+	// bare word, e.g. 123, true, $var, ${var}, ${f($a)}, /api/$id
+	start := p.pos
+	for !p.done() && strings.IndexByte(terminators, p.input[p.pos]) == -1 {
+		switch p.input[p.pos] {
+		case '(', '[', '{':
+			end, err := scanBalanced(p.input, p.pos)
+			if err != nil {
+				return nil, err
+			}
+			p.pos = end + 1
+		case ')', ']', '}':
+			return nil, errors.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+		default:
+			p.pos++
+		}
+	}
+	word := strings.TrimSpace(p.input[start:p.pos])
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	value, err := literalEval(word)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := value.(string); ok && !topLevel && !strings.HasPrefix(s, "$") {
+		return nil, errors.Errorf("unquoted string %s in list or map", s)
+	}
+	return value, nil
+}
+
+func (p *argsParser) expectEnd(terminators string) error {
+	p.skipSpaces()
+	if p.done() || strings.IndexByte(terminators, p.input[p.pos]) != -1 {
+		return nil
+	}
+	return errors.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+}
+
+func (p *argsParser) parseList() ([]interface{}, error) {
+	p.pos++ // [
+	list := []interface{}{}
+	p.skipSpaces()
+	if !p.done() && p.input[p.pos] == ']' {
+		p.pos++
+		return list, nil
+	}
+	for {
+		value, err := p.parseValue(",]", false)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+		p.skipSpaces()
+		if p.done() {
+			return nil, errors.New("unclosed list")
+		}
+		c := p.input[p.pos]
+		p.pos++
+		if c == ']' {
+			return list, nil
+		}
+	}
+}
+
+func (p *argsParser) parseMap() (map[string]interface{}, error) {
+	p.pos++ // {
+	m := make(map[string]interface{})
+	p.skipSpaces()
+	if !p.done() && p.input[p.pos] == '}' {
+		p.pos++
+		return m, nil
+	}
+	for {
+		p.skipSpaces()
+		if p.done() {
+			return nil, errors.New("unclosed map")
+		}
+		// key is quoted string or identifier
+		var key string
+		if c := p.input[p.pos]; c == '\'' || c == '"' {
+			end, err := scanQuoted(p.input, p.pos)
+			if err != nil {
+				return nil, err
+			}
+			key = unquote(p.input[p.pos+1:end], c)
+			p.pos = end + 1
+		} else {
+			start := p.pos
+			for !p.done() && isIdentRune(rune(p.input[p.pos]), p.pos == start) {
+				p.pos++
+			}
+			key = p.input[start:p.pos]
+			if key == "" {
+				return nil, errors.Errorf("unexpected %q in map key at position %d", c, p.pos)
+			}
+		}
+		p.skipSpaces()
+		if p.done() || p.input[p.pos] != ':' {
+			return nil, errors.Errorf("expect : after map key %s", key)
+		}
+		p.pos++
+		value, err := p.parseValue(",}", false)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+		p.skipSpaces()
+		if p.done() {
+			return nil, errors.New("unclosed map")
+		}
+		c := p.input[p.pos]
+		p.pos++
+		if c == '}' {
+			return m, nil
+		}
+	}
+}
+
+// stripQuoted removes quoted strings in arguments string, which contain no variables or functions
+func stripQuoted(argsStr string) string {
+	var b strings.Builder
+	for i := 0; i < len(argsStr); i++ {
+		if c := argsStr[i]; c != '\'' && c != '"' {
+			b.WriteByte(c)
+			continue
+		}
+		end, err := scanQuoted(argsStr, i)
+		if err != nil {
+			b.WriteString(argsStr[i:])
+			break
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// unquote unescapes quoted string content, e.g. it\'s => it's
+func unquote(s string, quote byte) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case quote, '\\':
+				i++
+			case 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			case 't':
+				b.WriteByte('\t')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -163,6 +163,12 @@ func (b *Boomer) Quit() {
 	b.localRunner.stop()
 }
 
+// StopWorker stops the worker(virtual user) specified by workerID after its running task returns,
+// load testing is stopped when all workers are stopped.
+func (b *Boomer) StopWorker(workerID int) {
+	b.localRunner.stopWorker(workerID)
+}
+
 func (b *Boomer) GetSpawnDoneChan() chan struct{} {
 	return b.localRunner.spawnDone
 }
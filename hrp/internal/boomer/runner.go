@@ -67,6 +67,8 @@ type runner struct {
 	spawnRate         float64
 	loop              *Loop // specify loop count for testcase, count = loopCount * spawnCount
 	spawnDone         chan struct{}
+	stoppedWorkers    sync.Map // workerID => true, workers stopped by task
+	stoppedWorkersNum int32    // count of workers stopped by task
 
 	outputs []Output
 }
@@ -202,12 +204,20 @@ func (r *localRunner) spawnWorkers(spawnCount int, spawnRate float64, quit chan
 			return
 		default:
 			atomic.AddInt32(&r.currentClientsNum, 1)
-			go func() {
+			go func(workerID int) {
 				for {
 					select {
 					case <-quit:
 						return
 					default:
+						if _, ok := r.stoppedWorkers.Load(workerID); ok {
+							atomic.AddInt32(&r.currentClientsNum, -1)
+							// stop running when all workers are stopped
+							if int(atomic.AddInt32(&r.stoppedWorkersNum, 1)) == r.spawnCount {
+								r.stop()
+							}
+							return
+						}
 						if workerLoop != nil && !workerLoop.acquire() {
 							return
 						}
@@ -215,11 +225,11 @@ func (r *localRunner) spawnWorkers(spawnCount int, spawnRate float64, quit chan
 							blocked := r.rateLimiter.Acquire()
 							if !blocked {
 								task := r.getTask()
-								r.safeRun(task.Fn)
+								r.safeRun(func() { task.run(workerID) })
 							}
 						} else {
 							task := r.getTask()
-							r.safeRun(task.Fn)
+							r.safeRun(func() { task.run(workerID) })
 						}
 						if workerLoop != nil {
 							// finished count of total
@@ -232,7 +242,7 @@ func (r *localRunner) spawnWorkers(spawnCount int, spawnRate float64, quit chan
 						}
 					}
 				}
-			}()
+			}(i)
 		}
 	}
 
@@ -288,6 +298,7 @@ type localRunner struct {
 
 	// close this channel will stop all goroutines used in runner.
 	stopChan chan bool
+	stopOnce sync.Once
 }
 
 func newLocalRunner(spawnCount int, spawnRate float64) *localRunner {
@@ -377,5 +388,12 @@ func (r *localRunner) start() {
 }
 
 func (r *localRunner) stop() {
-	close(r.stopChan)
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+// stopWorker stops the worker after its running task returns, runner is stopped when all workers are stopped.
+func (r *localRunner) stopWorker(workerID int) {
+	r.stoppedWorkers.Store(workerID, true)
 }
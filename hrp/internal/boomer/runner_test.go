@@ -1,6 +1,7 @@
 package boomer
 
 import (
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -112,3 +113,52 @@ func TestLoopCount(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestWorkerFn(t *testing.T) {
+	var mutex sync.Mutex
+	workerIDs := make(map[int]int)
+	taskA := &Task{
+		Weight: 10,
+		WorkerFn: func(workerID int) {
+			mutex.Lock()
+			workerIDs[workerID]++
+			mutex.Unlock()
+			time.Sleep(time.Millisecond)
+		},
+		Name: "TaskA",
+	}
+	runner := newLocalRunner(3, 10)
+	runner.loop = &Loop{loopCount: 6}
+	runner.setTasks([]*Task{taskA})
+	go runner.start()
+	<-runner.stopChan
+	if !assert.Equal(t, map[int]int{1: 2, 2: 2, 3: 2}, workerIDs) {
+		t.Fail()
+	}
+}
+
+func TestStopWorker(t *testing.T) {
+	var mutex sync.Mutex
+	workerIDs := make(map[int]int)
+	runner := newLocalRunner(3, 10)
+	taskA := &Task{
+		Weight: 10,
+		WorkerFn: func(workerID int) {
+			mutex.Lock()
+			workerIDs[workerID]++
+			if workerIDs[workerID] == workerID {
+				runner.stopWorker(workerID)
+			}
+			mutex.Unlock()
+			time.Sleep(time.Millisecond)
+		},
+		Name: "TaskA",
+	}
+	runner.setTasks([]*Task{taskA})
+	go runner.start()
+	// runner is stopped when all workers are stopped
+	<-runner.stopChan
+	if !assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 3}, workerIDs) {
+		t.Fail()
+	}
+}
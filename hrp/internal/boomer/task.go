@@ -8,6 +8,17 @@ type Task struct {
 	// The weight is used to distribute goroutines over multiple tasks.
 	Weight int
 	// Fn is called by the goroutines allocated to this task, in a loop.
-	Fn   func()
-	Name string
+	Fn func()
+	// WorkerFn is called instead of Fn if it is set, workerID is the index of the goroutine starting from 1,
+	// which could be used to keep data of each virtual user apart.
+	WorkerFn func(workerID int)
+	Name     string
+}
+
+func (t *Task) run(workerID int) {
+	if t.WorkerFn != nil {
+		t.WorkerFn(workerID)
+		return
+	}
+	t.Fn()
 }
@@ -23,6 +23,7 @@ var Assertions = map[string]func(t assert.TestingT, actual interface{}, expected
 	"ne":                assert.NotEqual,
 	"not_equal":         assert.NotEqual,
 	"contains":          assert.Contains,
+	"not_contains":      assert.NotContains,
 	"type_match":        assert.IsType,
 	// custom assertions
 	"startswith":               StartsWith,
@@ -46,6 +47,9 @@ var Assertions = map[string]func(t assert.TestingT, actual interface{}, expected
 	"str_eq":                   StringEqual,
 	"string_equals":            StringEqual,
 	"regex_match":              RegexMatch,
+	"not_regex_match":          NotRegexMatch,
+	"not_startswith":           NotStartsWith,
+	"not_endswith":             NotEndsWith,
 }
 
 // StartsWith check if string starts with substring
@@ -161,7 +165,37 @@ func StringEqual(t assert.TestingT, actual, expected interface{}, msgAndArgs ...
 }
 
 func RegexMatch(t assert.TestingT, actual, expected interface{}, msgAndArgs ...interface{}) bool {
-	return assert.Regexp(t, expected, actual, msgAndArgs)
+	return assert.Regexp(t, expected, actual, msgAndArgs...)
+}
+
+func NotRegexMatch(t assert.TestingT, actual, expected interface{}, msgAndArgs ...interface{}) bool {
+	return assert.NotRegexp(t, expected, actual, msgAndArgs...)
+}
+
+// NotStartsWith check if string does not start with substring
+func NotStartsWith(t assert.TestingT, actual, expected interface{}, msgAndArgs ...interface{}) bool {
+	if !assert.IsType(t, "string", actual, fmt.Sprintf("actual is %v", actual)) {
+		return false
+	}
+	if !assert.IsType(t, "string", expected, fmt.Sprintf("expected is %v", expected)) {
+		return false
+	}
+	actualString := actual.(string)
+	expectedString := expected.(string)
+	return assert.False(t, strings.HasPrefix(actualString, expectedString), msgAndArgs...)
+}
+
+// NotEndsWith check if string does not end with substring
+func NotEndsWith(t assert.TestingT, actual, expected interface{}, msgAndArgs ...interface{}) bool {
+	if !assert.IsType(t, "string", actual, fmt.Sprintf("actual is %v", actual)) {
+		return false
+	}
+	if !assert.IsType(t, "string", expected, fmt.Sprintf("expected is %v", expected)) {
+		return false
+	}
+	actualString := actual.(string)
+	expectedString := expected.(string)
+	return assert.False(t, strings.HasSuffix(actualString, expectedString), msgAndArgs...)
 }
 
 func convertInt(value interface{}) (int, error) {
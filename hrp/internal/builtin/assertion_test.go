@@ -1,6 +1,7 @@
 package builtin
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 
@@ -189,3 +190,66 @@ func TestRegexMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestNotRegexMatch(t *testing.T) {
+	testData := []struct {
+		raw      interface{}
+		expected interface{}
+	}{
+		{"it's starting...", regexp.MustCompile("^start")},
+		{"it's starting", "ing\\.$"},
+	}
+
+	for _, data := range testData {
+		if !assert.True(t, NotRegexMatch(t, data.raw, data.expected)) {
+			t.Fail()
+		}
+	}
+}
+
+type mockT struct {
+	message string
+}
+
+func (m *mockT) Errorf(format string, args ...interface{}) {
+	m.message = fmt.Sprintf(format, args...)
+}
+
+func TestRegexMatchWithMessage(t *testing.T) {
+	mock := &mockT{}
+	if !assert.False(t, RegexMatch(mock, "abc", "^b", "check %s", "name")) ||
+		!assert.Contains(t, mock.message, "Messages:   	check name") {
+		t.Fail()
+	}
+	mock = &mockT{}
+	if !assert.False(t, NotRegexMatch(mock, "abc", "^a", "check %s", "name")) ||
+		!assert.Contains(t, mock.message, "Messages:   	check name") {
+		t.Fail()
+	}
+}
+
+func TestNotStartsWithAndEndsWith(t *testing.T) {
+	testData := []struct {
+		raw      string
+		expected string
+	}{
+		{"", "a"},
+		{"abc", "b"},
+		{"abc", "abcd"},
+	}
+
+	for _, data := range testData {
+		if !assert.True(t, NotStartsWith(t, data.raw, data.expected)) {
+			t.Fail()
+		}
+		if !assert.True(t, NotEndsWith(t, data.raw, data.expected)) {
+			t.Fail()
+		}
+	}
+	if !assert.False(t, NotStartsWith(&testing.T{}, "abc", "ab")) {
+		t.Fail()
+	}
+	if !assert.False(t, NotEndsWith(&testing.T{}, "abc", "bc")) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,46 @@
+package builtin
+
+import (
+	"sync"
+	"time"
+)
+
+// clock is used by time-based builtin functions, e.g. get_timestamp and get_time,
+// it could be frozen or shifted to replay a run exactly.
+var clock struct {
+	sync.RWMutex
+	frozen *time.Time
+	offset time.Duration
+}
+
+// Now returns current time of clock
+func Now() time.Time {
+	clock.RLock()
+	defer clock.RUnlock()
+	if clock.frozen != nil {
+		return *clock.frozen
+	}
+	return time.Now().Add(clock.offset)
+}
+
+// FreezeTime freezes clock at t, time-based builtin functions always get t as current time
+func FreezeTime(t time.Time) {
+	clock.Lock()
+	defer clock.Unlock()
+	clock.frozen = &t
+}
+
+// SetTimeOffset shifts clock by offset, e.g. -24h makes current time be yesterday
+func SetTimeOffset(offset time.Duration) {
+	clock.Lock()
+	defer clock.Unlock()
+	clock.offset = offset
+}
+
+// ResetClock resets clock to system time
+func ResetClock() {
+	clock.Lock()
+	defer clock.Unlock()
+	clock.frozen = nil
+	clock.offset = 0
+}
@@ -0,0 +1,276 @@
+package builtin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const defaultLocale = "en_US"
+
+// fakerLocale holds data of one locale to generate fake data
+type fakerLocale struct {
+	firstNames    []string
+	lastNames     []string
+	nameFormat    string // %[1]s is first name, %[2]s is last name
+	emailDomains  []string
+	phoneFormats  []string // # is replaced with random digit
+	provinces     []string
+	cities        []string
+	streets       []string
+	addressFormat string // %[1]s is building number, %[2]s street, %[3]s city, %[4]s province, %[5]s postcode
+	postcode      string
+	companyNames  []string
+	companyTypes  []string
+	companyFormat string // %[1]s is name, %[2]s is type
+	words         []string
+	wordSeparator string
+	sentenceEnd   string
+}
+
+var fakerLocales = map[string]*fakerLocale{
+	"en_US": {
+		firstNames: []string{
+			"James", "John", "Robert", "Michael", "William", "David", "Richard", "Joseph", "Thomas", "Charles",
+			"Mary", "Patricia", "Jennifer", "Linda", "Elizabeth", "Barbara", "Susan", "Jessica", "Sarah", "Karen",
+			"Daniel", "Matthew", "Anthony", "Mark", "Emily", "Emma", "Olivia", "Sophia", "Grace", "Lucas",
+		},
+		lastNames: []string{
+			"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+			"Hernandez", "Lopez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin", "Lee",
+			"Thompson", "White", "Harris", "Clark", "Lewis", "Walker", "Hall", "Allen", "Young", "King",
+		},
+		nameFormat:   "%[1]s %[2]s",
+		emailDomains: []string{"example.com", "example.org", "example.net", "mail.test"},
+		phoneFormats: []string{"(###) ###-####", "###-###-####", "+1-###-###-####"},
+		provinces: []string{
+			"Alabama", "Arizona", "California", "Colorado", "Florida", "Georgia", "Illinois", "Massachusetts",
+			"Michigan", "New York", "Ohio", "Oregon", "Pennsylvania", "Texas", "Virginia", "Washington",
+		},
+		cities: []string{
+			"Springfield", "Riverside", "Franklin", "Greenville", "Bristol", "Clinton", "Fairview", "Salem",
+			"Madison", "Georgetown", "Arlington", "Ashland", "Dover", "Oxford", "Jackson", "Burlington",
+		},
+		streets: []string{
+			"Main Street", "Oak Avenue", "Pine Street", "Maple Avenue", "Cedar Lane", "Elm Street",
+			"Washington Avenue", "Lake Drive", "Hill Road", "Park Boulevard", "Sunset Drive", "River Road",
+		},
+		addressFormat: "%[1]s %[2]s, %[3]s, %[4]s %[5]s",
+		postcode:      "#####",
+		companyNames: []string{
+			"Acme", "Globex", "Initech", "Umbrella", "Stark", "Wayne", "Hooli", "Vandelay", "Soylent", "Cyberdyne",
+			"Aperture", "Wonka", "Tyrell", "Oscorp", "Gringotts", "Monarch",
+		},
+		companyTypes:  []string{"Inc", "LLC", "Group", "Ltd", "Corp", "and Sons"},
+		companyFormat: "%[1]s %[2]s",
+		words: []string{
+			"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+			"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+			"ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip",
+		},
+		wordSeparator: " ",
+		sentenceEnd:   ".",
+	},
+	"zh_CN": {
+		firstNames: []string{
+			"伟", "芳", "娜", "敏", "静", "丽", "强", "磊", "军", "洋", "勇", "艳", "杰", "娟", "涛",
+			"明", "超", "秀英", "霞", "平", "刚", "桂英", "子涵", "浩然", "欣怡", "梓萱", "宇轩", "思远", "雨桐", "俊杰",
+		},
+		lastNames: []string{
+			"王", "李", "张", "刘", "陈", "杨", "黄", "赵", "吴", "周", "徐", "孙", "马", "朱", "胡",
+			"郭", "何", "高", "林", "罗", "郑", "梁", "谢", "宋", "唐", "许", "韩", "冯", "邓", "曹",
+		},
+		nameFormat:   "%[2]s%[1]s",
+		emailDomains: []string{"example.cn", "example.com.cn", "mail.test"},
+		phoneFormats: []string{
+			"130########", "131########", "132########", "133########", "135########", "136########",
+			"137########", "138########", "139########", "150########", "151########", "152########",
+			"155########", "156########", "158########", "159########", "176########", "177########",
+			"180########", "181########", "186########", "187########", "188########", "189########",
+		},
+		provinces: []string{
+			"北京市", "上海市", "天津市", "重庆市", "广东省", "浙江省", "江苏省", "四川省",
+			"湖北省", "湖南省", "山东省", "河南省", "福建省", "陕西省", "辽宁省", "云南省",
+		},
+		cities: []string{
+			"广州市", "深圳市", "杭州市", "宁波市", "南京市", "苏州市", "成都市", "武汉市",
+			"长沙市", "青岛市", "郑州市", "厦门市", "西安市", "大连市", "昆明市", "合肥市",
+		},
+		streets: []string{
+			"人民路", "解放路", "中山路", "建设路", "和平路", "新华路", "文化路", "胜利路",
+			"长江路", "黄河路", "朝阳路", "光明街", "友谊街", "幸福街", "学府路", "科技路",
+		},
+		addressFormat: "%[4]s%[3]s%[2]s%[1]s号 %[5]s",
+		postcode:      "######",
+		companyNames: []string{
+			"华信", "恒通", "鼎盛", "宏达", "天元", "博远", "新创", "金桥", "瑞丰", "中科",
+			"联合", "东方", "星辰", "远景", "海纳", "卓越",
+		},
+		companyTypes: []string{
+			"科技有限公司", "信息技术有限公司", "网络科技有限公司", "贸易有限公司", "传媒有限公司", "实业有限公司",
+		},
+		companyFormat: "%[1]s%[2]s",
+		words: []string{
+			"我们", "一个", "时间", "发展", "工作", "系统", "用户", "数据", "服务", "产品",
+			"公司", "市场", "技术", "管理", "信息", "网络", "问题", "方法", "质量", "测试",
+			"性能", "接口", "需要", "能够", "进行", "已经", "可以", "通过", "提供", "支持",
+		},
+		wordSeparator: "",
+		sentenceEnd:   "。",
+	},
+}
+
+// getLocale returns locale data, locale is optional and defaults to en_US, zh-CN is regarded as zh_CN
+func getLocale(locale []string) (*fakerLocale, error) {
+	name := defaultLocale
+	if len(locale) > 0 && locale[0] != "" {
+		name = strings.Replace(locale[0], "-", "_", 1)
+	}
+	l, ok := fakerLocales[name]
+	if !ok {
+		return nil, errors.Errorf("unsupported faker locale: %s", name)
+	}
+	return l, nil
+}
+
+func (g *generator) pick(items []string) string {
+	return items[g.r.Intn(len(items))]
+}
+
+// numerify replaces each # in format with random digit
+func (g *generator) numerify(format string) string {
+	var b strings.Builder
+	for _, r := range format {
+		if r == '#' {
+			b.WriteByte(byte('0' + g.r.Intn(10)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (g *generator) fakeFirstName(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return g.pick(l.firstNames), nil
+}
+
+func (g *generator) fakeLastName(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return g.pick(l.lastNames), nil
+}
+
+func (g *generator) fakeName(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(l.nameFormat, g.pick(l.firstNames), g.pick(l.lastNames)), nil
+}
+
+// fakeEmail returns email address with random letters and digits in user name to make it unique
+func (g *generator) fakeEmail(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	firstName := strings.ToLower(g.pick(fakerLocales[defaultLocale].firstNames))
+	return fmt.Sprintf("%s.%s%s@%s",
+		firstName, strings.ToLower(g.genRandomString(4)), g.numerify("####"), g.pick(l.emailDomains)), nil
+}
+
+func (g *generator) fakePhoneNumber(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return g.numerify(g.pick(l.phoneFormats)), nil
+}
+
+func (g *generator) fakeAddress(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	building := strconv.Itoa(1 + g.r.Intn(999))
+	return fmt.Sprintf(l.addressFormat,
+		building, g.pick(l.streets), g.pick(l.cities), g.pick(l.provinces), g.numerify(l.postcode)), nil
+}
+
+func (g *generator) fakeCity(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return g.pick(l.cities), nil
+}
+
+func (g *generator) fakeCompany(locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(l.companyFormat, g.pick(l.companyNames), g.pick(l.companyTypes)), nil
+}
+
+// fakeLorem returns sentence with n random words
+func (g *generator) fakeLorem(n int, locale ...string) (string, error) {
+	l, err := getLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	if n <= 0 {
+		return "", errors.Errorf("lorem words count should be positive, got %d", n)
+	}
+	words := make([]string, n)
+	for i := range words {
+		words[i] = g.pick(l.words)
+	}
+	if l.wordSeparator == " " {
+		words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	}
+	return strings.Join(words, l.wordSeparator) + l.sentenceEnd, nil
+}
+
+func (g *generator) fakeIPv4() string {
+	return fmt.Sprintf("%d.%d.%d.%d", 1+g.r.Intn(223), g.r.Intn(256), g.r.Intn(256), 1+g.r.Intn(254))
+}
+
+func (g *generator) fakeIPv6() string {
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = strconv.FormatInt(int64(g.r.Intn(0x10000)), 16)
+	}
+	return strings.Join(groups, ":")
+}
+
+// fakeCreditCard returns 16-digit card number starting with 4 which passes Luhn check,
+// it is only for testing and not a valid card.
+func (g *generator) fakeCreditCard() string {
+	number := "4" + g.numerify(strings.Repeat("#", 14))
+	return number + strconv.Itoa(luhnCheckDigit(number))
+}
+
+// luhnCheckDigit calculates check digit to be appended to number with Luhn algorithm
+func luhnCheckDigit(number string) int {
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if (len(number)-1-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,157 @@
+package builtin
+
+import (
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/httprunner/funplugin/shared"
+)
+
+var gen = &generator{r: random}
+
+// luhnValid checks if number passes Luhn check
+func luhnValid(number string) bool {
+	return luhnCheckDigit(number[:len(number)-1]) == int(number[len(number)-1]-'0')
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	if !assert.Equal(t, 3, luhnCheckDigit("7992739871")) {
+		t.Fail()
+	}
+	if !assert.True(t, luhnValid("4111111111111111")) {
+		t.Fail()
+	}
+	if !assert.False(t, luhnValid("4111111111111112")) {
+		t.Fail()
+	}
+	for i := 0; i < 100; i++ {
+		number := gen.fakeCreditCard()
+		if !assert.Regexp(t, `^4\d{15}$`, number) || !assert.True(t, luhnValid(number), number) {
+			t.Fail()
+		}
+	}
+}
+
+func TestFakeWithLocale(t *testing.T) {
+	name, err := gen.fakeName()
+	if !assert.NoError(t, err) || !assert.Regexp(t, `^[A-Z][a-z]+ [A-Z][a-z]+$`, name) {
+		t.Fail()
+	}
+	name, err = gen.fakeName("zh_CN")
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.True(t, utf8.RuneCountInString(name) >= 2 && utf8.RuneCountInString(name) <= 3, name) {
+		t.Fail()
+	}
+
+	phone, err := gen.fakePhoneNumber("zh-CN")
+	if !assert.NoError(t, err) || !assert.Regexp(t, `^1[3-9]\d{9}$`, phone) {
+		t.Fail()
+	}
+	phone, err = gen.fakePhoneNumber("en_US")
+	if !assert.NoError(t, err) || !assert.Regexp(t, `\d{3}-\d{4}$`, phone) {
+		t.Fail()
+	}
+
+	address, err := gen.fakeAddress("zh_CN")
+	if !assert.NoError(t, err) || !assert.Regexp(t, `^\p{Han}+\d+号 \d{6}$`, address) {
+		t.Fail()
+	}
+	address, err = gen.fakeAddress()
+	if !assert.NoError(t, err) || !assert.Regexp(t, `^\d+ [A-Za-z ]+, [A-Za-z]+, [A-Za-z ]+ \d{5}$`, address) {
+		t.Fail()
+	}
+
+	company, err := gen.fakeCompany("zh_CN")
+	if !assert.NoError(t, err) || !assert.True(t, strings.HasSuffix(company, "公司"), company) {
+		t.Fail()
+	}
+
+	lorem, err := gen.fakeLorem(5)
+	if !assert.NoError(t, err) || !assert.Regexp(t, `^[A-Z][a-z]*( [a-z]+){4}\.$`, lorem) {
+		t.Fail()
+	}
+	lorem, err = gen.fakeLorem(3, "zh_CN")
+	if !assert.NoError(t, err) || !assert.Equal(t, 7, utf8.RuneCountInString(lorem)) {
+		t.Fail()
+	}
+
+	if _, err = gen.fakeName("fr_FR"); !assert.Error(t, err) {
+		t.Fail()
+	}
+	if _, err = gen.fakeLorem(0); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestFakeEmailAndIP(t *testing.T) {
+	emails := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		email, err := gen.fakeEmail("zh_CN")
+		if !assert.NoError(t, err) || !assert.Regexp(t, regexp.MustCompile(`^[a-z]+\.[a-z0-9]{4}\d{4}@[a-z.]+$`), email) {
+			t.Fail()
+		}
+		emails[email] = true
+
+		if !assert.NotNil(t, net.ParseIP(gen.fakeIPv4()).To4()) {
+			t.Fail()
+		}
+		ip := net.ParseIP(gen.fakeIPv6())
+		if !assert.NotNil(t, ip) || !assert.Nil(t, ip.To4()) {
+			t.Fail()
+		}
+	}
+	if !assert.Len(t, emails, 100) {
+		t.Fail()
+	}
+}
+
+func TestNewRandomFunctionsWithSeed(t *testing.T) {
+	generate := func(functions map[string]interface{}) []interface{} {
+		var values []interface{}
+		for _, name := range []string{"fake_name", "fake_email", "fake_credit_card", "uuid4"} {
+			value, err := shared.CallFunc(reflect.ValueOf(functions[name]))
+			if !assert.NoError(t, err) {
+				t.Fail()
+			}
+			values = append(values, value)
+		}
+		n, _ := shared.CallFunc(reflect.ValueOf(functions["random_int"]), 0, 1000000)
+		s, _ := shared.CallFunc(reflect.ValueOf(functions["gen_random_string"]), 8)
+		return append(values, n, s)
+	}
+
+	// generators with the same seed generate the same data
+	first := generate(NewRandomFunctions(NewRandom(42)))
+	if !assert.Equal(t, first, generate(NewRandomFunctions(NewRandom(42)))) {
+		t.Fail()
+	}
+	if !assert.NotEqual(t, first, generate(NewRandomFunctions(NewRandom(43)))) {
+		t.Fail()
+	}
+}
+
+func TestFakeConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := gen.fakeAddress("zh_CN"); err != nil {
+					t.Error(err)
+				}
+				gen.fakeCreditCard()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,48 +1,224 @@
 package builtin
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/hex"
+	builtinJSON "encoding/json"
+	"hash"
 	"math"
-	"math/rand"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/httprunner/httprunner/hrp/internal/json"
 )
 
+// Functions are builtin functions which could be called in testcase, random functions using
+// the shared random generator are also included, see NewRandomFunctions.
 var Functions = map[string]interface{}{
-	"get_timestamp":     getTimestamp,    // call without arguments
-	"sleep":             sleep,           // call with one argument
-	"gen_random_string": genRandomString, // call with one argument
-	"max":               math.Max,        // call with two arguments
-	"md5":               MD5,             // call with one argument
-	"parameterize":      loadFromCSV,
-	"P":                 loadFromCSV,
+	"get_timestamp": getTimestamp,      // call without arguments
+	"sleep":         sleep,             // call with one argument
+	"max":           math.Max,          // call with two arguments
+	"md5":           MD5,               // call with one argument
+	"sha1":          SHA1,              // call with one argument
+	"sha256":        SHA256,            // call with one argument
+	"hmac":          HMAC,              // call with three arguments
+	"base64_encode": base64Encode,      // call with one argument
+	"base64_decode": base64Decode,      // call with one argument
+	"url_encode":    url.QueryEscape,   // call with one argument
+	"url_decode":    url.QueryUnescape, // call with one argument
+	"hex_encode":    hexEncode,         // call with one argument
+	"hex_decode":    hexDecode,         // call with one argument
+	"get_time":      getTime,           // call with layout and optional offsets
+	"json_dumps":    jsonDumps,         // call with one argument
+	"json_loads":    jsonLoads,         // call with one argument
+	"ENV":           os.Getenv,         // call with one argument, e.g. variables loaded from .env
+	"parameterize":  LoadParameters,    // call with path and optional stream=true
+	"P":             LoadParameters,    // alias of parameterize
 }
 
 func init() {
-	rand.Seed(time.Now().UnixNano())
+	for name, fn := range NewRandomFunctions(random) {
+		Functions[name] = fn
+	}
 }
 
 func getTimestamp() int64 {
-	return time.Now().UnixNano() / int64(time.Millisecond)
+	return Now().UnixNano() / int64(time.Millisecond)
 }
 
 func sleep(nSecs int) {
 	time.Sleep(time.Duration(nSecs) * time.Second)
 }
 
-const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+func MD5(str string) string {
+	hasher := md5.New()
+	hasher.Write([]byte(str))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func SHA1(str string) string {
+	return hashString(sha1.New(), str)
+}
+
+func SHA256(str string) string {
+	return hashString(sha256.New(), str)
+}
 
-func genRandomString(n int) string {
-	lettersLen := len(letters)
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = letters[rand.Intn(lettersLen)]
+// HMAC returns hex encoded HMAC of message with key, algorithm could be md5, sha1, sha256 or sha512
+func HMAC(algorithm, key, message string) (string, error) {
+	var hasher func() hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "md5":
+		hasher = md5.New
+	case "sha1":
+		hasher = sha1.New
+	case "sha256":
+		hasher = sha256.New
+	case "sha512":
+		hasher = sha512.New
+	default:
+		return "", errors.Errorf("unsupported hmac algorithm: %s", algorithm)
 	}
-	return string(b)
+	return hashString(hmac.New(hasher, []byte(key)), message), nil
 }
 
-func MD5(str string) string {
-	hasher := md5.New()
+func hashString(hasher hash.Hash, str string) string {
 	hasher.Write([]byte(str))
 	return hex.EncodeToString(hasher.Sum(nil))
 }
+
+func base64Encode(str string) string {
+	return base64.StdEncoding.EncodeToString([]byte(str))
+}
+
+func base64Decode(str string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return "", errors.Wrap(err, "base64 decode failed")
+	}
+	return string(data), nil
+}
+
+func hexEncode(str string) string {
+	return hex.EncodeToString([]byte(str))
+}
+
+func hexDecode(str string) (string, error) {
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		return "", errors.Wrap(err, "hex decode failed")
+	}
+	return string(data), nil
+}
+
+// layout aliases of get_time
+var timeLayouts = map[string]string{
+	"date":     "2006-01-02",
+	"time":     "15:04:05",
+	"datetime": "2006-01-02 15:04:05",
+	"rfc3339":  time.RFC3339,
+	"iso8601":  time.RFC3339,
+}
+
+// getTime returns current time shifted by offsets, e.g. get_time("datetime", "-1d2h"),
+// layout could be alias in timeLayouts, timestamp, timestamp_ms, or Go time layout.
+func getTime(layout string, offsets ...string) (interface{}, error) {
+	t := Now()
+	for _, offset := range offsets {
+		d, err := ParseTimeOffset(offset)
+		if err != nil {
+			return nil, err
+		}
+		t = t.Add(d)
+	}
+
+	switch layout {
+	case "timestamp":
+		return t.Unix(), nil
+	case "timestamp_ms":
+		return t.UnixNano() / int64(time.Millisecond), nil
+	}
+	if l, ok := timeLayouts[layout]; ok {
+		layout = l
+	}
+	return t.Format(layout), nil
+}
+
+// ParseTimeOffset parses time offset in Go duration format, days are also supported, e.g. 1d, -2d3h, +30m
+func ParseTimeOffset(offset string) (time.Duration, error) {
+	raw := strings.TrimSpace(offset)
+	sign := time.Duration(1)
+	if strings.HasPrefix(raw, "-") {
+		sign = -1
+		raw = raw[1:]
+	} else {
+		raw = strings.TrimPrefix(raw, "+")
+	}
+
+	var d time.Duration
+	if i := strings.Index(raw, "d"); i != -1 {
+		days, err := strconv.Atoi(raw[:i])
+		if err != nil {
+			return 0, errors.Errorf("invalid time offset: %s", offset)
+		}
+		d = time.Duration(days) * 24 * time.Hour
+		raw = raw[i+1:]
+	}
+	if raw != "" {
+		rest, err := time.ParseDuration(raw)
+		if err != nil || rest < 0 {
+			return 0, errors.Errorf("invalid time offset: %s", offset)
+		}
+		d += rest
+	}
+	return sign * d, nil
+}
+
+func jsonDumps(data interface{}) (string, error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.Wrap(err, "json dumps failed")
+	}
+	return string(content), nil
+}
+
+// jsonLoads parses JSON string, integers are converted to int and other numbers to float64
+func jsonLoads(str string) (interface{}, error) {
+	var data interface{}
+	decoder := json.NewDecoder(strings.NewReader(str))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "json loads failed")
+	}
+	return convertNumbers(data), nil
+}
+
+func convertNumbers(data interface{}) interface{} {
+	switch v := data.(type) {
+	case builtinJSON.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = convertNumbers(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = convertNumbers(v[key])
+		}
+	}
+	return data
+}
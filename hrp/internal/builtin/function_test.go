@@ -0,0 +1,225 @@
+package builtin
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashFunctions(t *testing.T) {
+	testData := []struct {
+		fn       func(string) string
+		raw      string
+		expected string
+	}{
+		{MD5, "abc", "900150983cd24fb0d6963f7d28e17f72"},
+		{SHA1, "abc", "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{SHA256, "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, data.fn(data.raw)) {
+			t.Fail()
+		}
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	value, err := HMAC("sha256", "key", "The quick brown fox jumps over the lazy dog")
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", value) {
+		t.Fail()
+	}
+	value, err = HMAC("MD5", "key", "The quick brown fox jumps over the lazy dog")
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "80070713463e7749b90c2dc24911e275", value) {
+		t.Fail()
+	}
+	_, err = HMAC("sha3", "key", "abc")
+	if !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestUUID4(t *testing.T) {
+	value, err := gen.uuid4()
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), value) {
+		t.Fail()
+	}
+	another, _ := gen.uuid4()
+	if !assert.NotEqual(t, value, another) {
+		t.Fail()
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	raw := "a b&c=中文"
+
+	encoded := base64Encode(raw)
+	if !assert.Equal(t, "YSBiJmM95Lit5paH", encoded) {
+		t.Fail()
+	}
+	decoded, err := base64Decode(encoded)
+	if !assert.NoError(t, err) || !assert.Equal(t, raw, decoded) {
+		t.Fail()
+	}
+
+	encoded = hexEncode("abc")
+	if !assert.Equal(t, "616263", encoded) {
+		t.Fail()
+	}
+	decoded, err = hexDecode(encoded)
+	if !assert.NoError(t, err) || !assert.Equal(t, "abc", decoded) {
+		t.Fail()
+	}
+
+	if _, err = base64Decode("!!"); !assert.Error(t, err) {
+		t.Fail()
+	}
+	if _, err = hexDecode("xyz"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestParseTimeOffset(t *testing.T) {
+	testData := []struct {
+		offset   string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"1h", time.Hour},
+		{"+30m", 30 * time.Minute},
+		{"-1d", -24 * time.Hour},
+		{"2d3h", 51 * time.Hour},
+		{"-1d2h30m", -(26*time.Hour + 30*time.Minute)},
+	}
+
+	for _, data := range testData {
+		d, err := ParseTimeOffset(data.offset)
+		if !assert.NoError(t, err) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expected, d, data.offset) {
+			t.Fail()
+		}
+	}
+
+	for _, offset := range []string{"abc", "1x", "d", "1d-2h"} {
+		if _, err := ParseTimeOffset(offset); !assert.Error(t, err, offset) {
+			t.Fail()
+		}
+	}
+}
+
+func TestGetTime(t *testing.T) {
+	value, err := getTime("date")
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, time.Now().Format("2006-01-02"), value) {
+		t.Fail()
+	}
+
+	value, err = getTime("timestamp", "-1d", "1h")
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.InDelta(t, time.Now().Add(-23*time.Hour).Unix(), value, 2) {
+		t.Fail()
+	}
+
+	value, err = getTime("2006/01/02 15", "24h")
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Regexp(t, `^\d{4}/\d{2}/\d{2} \d{2}$`, value) {
+		t.Fail()
+	}
+
+	if _, err = getTime("date", "1y"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestClock(t *testing.T) {
+	defer ResetClock()
+
+	frozen := time.Date(2022, 3, 8, 15, 4, 5, 0, time.UTC)
+	FreezeTime(frozen)
+	if !assert.Equal(t, frozen.UnixNano()/int64(time.Millisecond), getTimestamp()) {
+		t.Fail()
+	}
+	value, err := getTime("datetime", "-1d")
+	if !assert.NoError(t, err) || !assert.Equal(t, "2022-03-07 15:04:05", value) {
+		t.Fail()
+	}
+
+	ResetClock()
+	SetTimeOffset(-48 * time.Hour)
+	value, err = getTime("date")
+	if !assert.NoError(t, err) || !assert.Equal(t, time.Now().AddDate(0, 0, -2).Format("2006-01-02"), value) {
+		t.Fail()
+	}
+}
+
+func TestRandomFunctions(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		n, err := gen.randomInt(-2, 2)
+		if !assert.NoError(t, err) || !assert.True(t, n >= -2 && n <= 2) {
+			t.Fail()
+		}
+		f, err := gen.randomFloat(1, 1.5)
+		if !assert.NoError(t, err) || !assert.True(t, f >= 1 && f < 1.5) {
+			t.Fail()
+		}
+		item, err := gen.randomChoice([]interface{}{"a", 1})
+		if !assert.NoError(t, err) || !assert.Contains(t, []interface{}{"a", 1}, item) {
+			t.Fail()
+		}
+	}
+
+	if _, err := gen.randomInt(2, 1); !assert.Error(t, err) {
+		t.Fail()
+	}
+	if _, err := gen.randomChoice([]string{}); !assert.Error(t, err) {
+		t.Fail()
+	}
+	if _, err := gen.randomChoice("abc"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestJSONFunctions(t *testing.T) {
+	content, err := jsonDumps(map[string]interface{}{"b": []interface{}{1, "x"}, "a": nil})
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, `{"a":null,"b":[1,"x"]}`, content) {
+		t.Fail()
+	}
+
+	data, err := jsonLoads(`{"a": 1, "b": [1.5, {"c": true}]}`)
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	expected := map[string]interface{}{
+		"a": 1,
+		"b": []interface{}{1.5, map[string]interface{}{"c": true}},
+	}
+	if !assert.Equal(t, expected, data) {
+		t.Fail()
+	}
+
+	if _, err = jsonLoads("{"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
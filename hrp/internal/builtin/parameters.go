@@ -0,0 +1,313 @@
+package builtin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/httprunner/httprunner/hrp/internal/json"
+)
+
+// LoadParameters loads parameters from file or directory, which is called by parameterize and P.
+// Supported formats are detected by file extension: csv, json array, yaml list and jsonl (ndjson),
+// files in directory are loaded in name order and concatenated, unsupported files are skipped.
+//
+// If option stream is true, e.g. ${parameterize(users.jsonl, stream=true)}, a ParamsStream is returned
+// to read csv or jsonl file lazily instead of loading the whole file into memory.
+func LoadParameters(path string, options ...map[string]interface{}) (interface{}, error) {
+	stream := false
+	for _, option := range options {
+		for key, value := range option {
+			switch key {
+			case "stream":
+				v, ok := value.(bool)
+				if !ok {
+					return nil, errors.Errorf("parameters option stream should be bool, got %v", value)
+				}
+				stream = v
+			default:
+				return nil, errors.Errorf("unsupported parameters option: %s", key)
+			}
+		}
+	}
+	if stream {
+		return OpenParamsStream(path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load parameters failed")
+	}
+	if !info.IsDir() {
+		return loadParametersFile(path)
+	}
+
+	log.Info().Str("path", path).Msg("load parameters directory")
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read parameters directory failed")
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && parametersFormat(entry.Name()) != "" {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	rows := []interface{}{}
+	for _, name := range names {
+		fileRows, err := loadParametersFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, fileRows...)
+	}
+	return rows, nil
+}
+
+// parametersFormat returns format of parameters file by extension, empty if unsupported
+func parametersFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv"
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	}
+	return ""
+}
+
+func loadParametersFile(path string) ([]interface{}, error) {
+	format := parametersFormat(path)
+	if format == "" {
+		return nil, errors.Errorf("unsupported parameters file: %s, should be csv, json, yaml or jsonl", path)
+	}
+	log.Info().Str("path", path).Str("format", format).Msg("load parameters file")
+	content, err := readFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read parameters file failed")
+	}
+
+	var rows []interface{}
+	switch format {
+	case "json":
+		var data interface{}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return nil, errors.Wrapf(err, "parse json parameters file %s failed", path)
+		}
+		list, ok := convertNumbers(data).([]interface{})
+		if !ok {
+			return nil, errors.Errorf("json parameters file %s should be an array", path)
+		}
+		rows = list
+	case "yaml":
+		if err := yaml.Unmarshal(content, &rows); err != nil {
+			return nil, errors.Wrapf(err, "yaml parameters file %s should be a list", path)
+		}
+	default: // csv, jsonl
+		stream := newParamsStream(io.NopCloser(bytes.NewReader(content)), path, format)
+		for {
+			row, err := stream.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+	}
+	if rows == nil {
+		rows = []interface{}{}
+	}
+	return rows, nil
+}
+
+// ParamsStream reads rows of csv or jsonl parameters file lazily
+type ParamsStream struct {
+	path   string
+	format string
+	file   io.ReadCloser
+	reader *bufio.Reader
+	csv    *csv.Reader
+	header []csvColumn
+	line   int // line number of jsonl file, or record number of csv file including header
+}
+
+// OpenParamsStream opens csv or jsonl parameters file to read rows lazily
+func OpenParamsStream(path string) (*ParamsStream, error) {
+	format := parametersFormat(path)
+	if format != "csv" && format != "jsonl" {
+		return nil, errors.Errorf("stream is only supported for csv and jsonl parameters file, got %s", path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open parameters file failed")
+	}
+	log.Info().Str("path", path).Str("format", format).Msg("open parameters stream")
+	return newParamsStream(file, path, format), nil
+}
+
+func newParamsStream(file io.ReadCloser, path, format string) *ParamsStream {
+	s := &ParamsStream{path: path, format: format, file: file}
+	s.reset()
+	return s
+}
+
+func (s *ParamsStream) reset() {
+	s.reader = bufio.NewReader(s.file)
+	s.csv = nil
+	s.header = nil
+	s.line = 0
+}
+
+// Path returns path of parameters file
+func (s *ParamsStream) Path() string {
+	return s.path
+}
+
+// Next returns next row, which is map for csv and any json value for jsonl, io.EOF is returned at the end.
+func (s *ParamsStream) Next() (interface{}, error) {
+	if s.format == "csv" {
+		return s.nextCSV()
+	}
+	return s.nextJSONL()
+}
+
+// Reset rewinds stream to the first row
+func (s *ParamsStream) Reset() error {
+	seeker, ok := s.file.(io.Seeker)
+	if !ok {
+		return errors.New("parameters stream could not be reset")
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "reset parameters stream failed")
+	}
+	s.reset()
+	return nil
+}
+
+// Close closes parameters file
+func (s *ParamsStream) Close() error {
+	return s.file.Close()
+}
+
+func (s *ParamsStream) nextJSONL() (interface{}, error) {
+	for {
+		line, err := s.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "read jsonl parameters file failed")
+		}
+		if len(line) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		s.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var row interface{}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&row); err != nil {
+			return nil, errors.Wrapf(err, "parse line %d of %s failed", s.line, s.path)
+		}
+		return convertNumbers(row), nil
+	}
+}
+
+func (s *ParamsStream) nextCSV() (interface{}, error) {
+	if s.csv == nil {
+		s.csv = csv.NewReader(s.reader)
+		record, err := s.csv.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse csv header of %s failed", s.path)
+		}
+		s.header = parseCSVHeader(record)
+		s.line = 1
+	}
+
+	record, err := s.csv.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse csv file %s failed", s.path)
+	}
+	s.line++
+	row := make(map[string]interface{}, len(s.header))
+	for i, column := range s.header {
+		value, err := column.convert(record[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse record %d of %s failed", s.line, s.path)
+		}
+		row[column.name] = value
+	}
+	return row, nil
+}
+
+// csvColumn is csv header column, type could be specified with suffix, e.g. age:int
+type csvColumn struct {
+	name string
+	typ  string // string(default), int, float, bool
+}
+
+// parseCSVHeader parses column names and types, header is split only with int, float or bool suffix,
+// other headers containing colon are kept as column names, e.g. host:port
+func parseCSVHeader(record []string) []csvColumn {
+	columns := make([]csvColumn, len(record))
+	for i, field := range record {
+		column := csvColumn{name: strings.TrimSpace(field), typ: "string"}
+		if j := strings.LastIndexByte(column.name, ':'); j >= 0 {
+			switch typ := strings.ToLower(column.name[j+1:]); typ {
+			case "int", "float", "bool":
+				column.name, column.typ = column.name[:j], typ
+			}
+		}
+		columns[i] = column
+	}
+	return columns
+}
+
+func (c csvColumn) convert(value string) (interface{}, error) {
+	switch c.typ {
+	case "int":
+		v, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.Errorf("column %s should be int, got %q", c.name, value)
+		}
+		return v, nil
+	case "float":
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, errors.Errorf("column %s should be float, got %q", c.name, value)
+		}
+		return v, nil
+	case "bool":
+		v, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.Errorf("column %s should be bool, got %q", c.name, value)
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,159 @@
+package builtin
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeParametersFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadParameters(t *testing.T) {
+	dir := writeParametersFiles(t, map[string]string{
+		"1.csv":      "username,age:int,score:float,vip:bool\nu1,18,9.5,true\n",
+		"2.json":     `[{"username": "u2", "age": 20}, ["u3", 1.5]]`,
+		"3.yaml":     "- username: u4\n  age: 22\n- u5\n",
+		"4.jsonl":    "{\"username\": \"u6\", \"age\": 24}\n\n\"u7\"\n",
+		"5.csv":      "host:port,age:integer,score:float\nlocalhost:80,18,1\n",
+		"readme.txt": "ignored",
+	})
+
+	testData := []struct {
+		path     string
+		expected interface{}
+	}{
+		{
+			"1.csv",
+			[]interface{}{map[string]interface{}{"username": "u1", "age": 18, "score": 9.5, "vip": true}},
+		},
+		{
+			"2.json",
+			[]interface{}{map[string]interface{}{"username": "u2", "age": 20}, []interface{}{"u3", 1.5}},
+		},
+		{
+			"3.yaml",
+			[]interface{}{map[string]interface{}{"username": "u4", "age": 22}, "u5"},
+		},
+		{
+			"4.jsonl",
+			[]interface{}{map[string]interface{}{"username": "u6", "age": 24}, "u7"},
+		},
+		{
+			// only int, float and bool suffixes are column types
+			"5.csv",
+			[]interface{}{map[string]interface{}{"host:port": "localhost:80", "age:integer": "18", "score": 1.0}},
+		},
+	}
+	var all []interface{}
+	for _, data := range testData {
+		value, err := LoadParameters(filepath.Join(dir, data.path))
+		if !assert.NoError(t, err) {
+			t.Fatal()
+		}
+		if !assert.Equal(t, data.expected, value) {
+			t.Fail()
+		}
+		all = append(all, data.expected.([]interface{})...)
+	}
+
+	// files in directory are loaded in name order
+	value, err := LoadParameters(dir)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, all, value) {
+		t.Fail()
+	}
+}
+
+func TestLoadParametersError(t *testing.T) {
+	dir := writeParametersFiles(t, map[string]string{
+		"object.json":  `{"username": "u1"}`,
+		"invalid.json": `[{"username": "u1"`,
+		"map.yaml":     "username: u1\n",
+		"type.csv":     "username,age:int\nu1,18\nu2,unknown\n",
+		"fields.csv":   "username,age\nu1\n",
+		"line.jsonl":   "{\"username\": \"u1\"}\n{username: u2}\n",
+		"data.txt":     "u1",
+	})
+
+	testData := []struct {
+		path    string
+		options []map[string]interface{}
+	}{
+		{"not_found.csv", nil},
+		{"object.json", nil},
+		{"invalid.json", nil},
+		{"map.yaml", nil},
+		{"type.csv", nil},
+		{"fields.csv", nil},
+		{"line.jsonl", nil},
+		{"data.txt", nil},
+		{"object.json", []map[string]interface{}{{"stream": true}}},
+		{"type.csv", []map[string]interface{}{{"stream": "true"}}},
+		{"type.csv", []map[string]interface{}{{"lazy": true}}},
+	}
+	for _, data := range testData {
+		_, err := LoadParameters(filepath.Join(dir, data.path), data.options...)
+		if !assert.Error(t, err, data.path) {
+			t.Fail()
+		}
+	}
+}
+
+func TestParamsStream(t *testing.T) {
+	dir := writeParametersFiles(t, map[string]string{
+		"users.csv":   "username,age:int\nu1,18\nu2,20\n",
+		"users.jsonl": "{\"username\": \"u1\", \"age\": 18}\n{\"username\": \"u2\", \"age\": 20}",
+	})
+	expected := []interface{}{
+		map[string]interface{}{"username": "u1", "age": 18},
+		map[string]interface{}{"username": "u2", "age": 20},
+	}
+
+	for _, name := range []string{"users.csv", "users.jsonl"} {
+		value, err := LoadParameters(filepath.Join(dir, name), map[string]interface{}{"stream": true})
+		if !assert.NoError(t, err) {
+			t.Fatal()
+		}
+		stream, ok := value.(*ParamsStream)
+		if !assert.True(t, ok) {
+			t.Fatal()
+		}
+
+		// read twice to ensure stream could be reset
+		for i := 0; i < 2; i++ {
+			var rows []interface{}
+			for {
+				row, err := stream.Next()
+				if err == io.EOF {
+					break
+				}
+				if !assert.NoError(t, err) {
+					t.Fatal()
+				}
+				rows = append(rows, row)
+			}
+			if !assert.Equal(t, expected, rows) {
+				t.Fail()
+			}
+			if !assert.NoError(t, stream.Reset()) {
+				t.Fail()
+			}
+		}
+		if !assert.NoError(t, stream.Close()) {
+			t.Fail()
+		}
+	}
+}
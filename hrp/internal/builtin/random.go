@@ -0,0 +1,125 @@
+package builtin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// random is the shared random generator of builtin functions, which could be seeded to generate reproducible data.
+var random = NewRandom(time.Now().UnixNano())
+
+// NewRandom returns random generator with seed, which is safe for concurrent use
+func NewRandom(seed int64) *rand.Rand {
+	return rand.New(&lockedSource{src: rand.NewSource(seed).(rand.Source64)})
+}
+
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// generator generates random data with its own random generator
+type generator struct {
+	r *rand.Rand
+}
+
+// NewRandomFunctions returns builtin functions generating random data with random generator r,
+// e.g. each virtual user could have its own seeded generator to reproduce the data.
+func NewRandomFunctions(r *rand.Rand) map[string]interface{} {
+	g := &generator{r: r}
+	return map[string]interface{}{
+		"gen_random_string": g.genRandomString, // call with one argument
+		"uuid4":             g.uuid4,           // call without arguments
+		"random_int":        g.randomInt,       // call with two arguments
+		"random_float":      g.randomFloat,     // call with two arguments
+		"random_choice":     g.randomChoice,    // call with one argument
+		"fake_name":         g.fakeName,        // call with optional locale, e.g. zh_CN
+		"fake_first_name":   g.fakeFirstName,   // call with optional locale
+		"fake_last_name":    g.fakeLastName,    // call with optional locale
+		"fake_email":        g.fakeEmail,       // call with optional locale
+		"fake_phone_number": g.fakePhoneNumber, // call with optional locale
+		"fake_address":      g.fakeAddress,     // call with optional locale
+		"fake_city":         g.fakeCity,        // call with optional locale
+		"fake_company":      g.fakeCompany,     // call with optional locale
+		"fake_lorem":        g.fakeLorem,       // call with words count and optional locale
+		"fake_ipv4":         g.fakeIPv4,        // call without arguments
+		"fake_ipv6":         g.fakeIPv6,        // call without arguments
+		"fake_credit_card":  g.fakeCreditCard,  // call without arguments
+	}
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+
+func (g *generator) genRandomString(n int) string {
+	lettersLen := len(letters)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.r.Intn(lettersLen)]
+	}
+	return string(b)
+}
+
+// uuid4 returns version 4 UUID generated from random generator, thus it is reproducible with seed
+func (g *generator) uuid4() (string, error) {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[:8], g.r.Uint64())
+	binary.BigEndian.PutUint64(b[8:], g.r.Uint64())
+	u, err := uuid.NewRandomFromReader(bytes.NewReader(b))
+	if err != nil {
+		return "", errors.Wrap(err, "generate uuid failed")
+	}
+	return u.String(), nil
+}
+
+// randomInt returns random integer in [min, max]
+func (g *generator) randomInt(min, max int) (int, error) {
+	if min > max {
+		return 0, errors.Errorf("min %d is greater than max %d", min, max)
+	}
+	return min + g.r.Intn(max-min+1), nil
+}
+
+// randomFloat returns random float in [min, max)
+func (g *generator) randomFloat(min, max float64) (float64, error) {
+	if min > max {
+		return 0, errors.Errorf("min %v is greater than max %v", min, max)
+	}
+	return min + g.r.Float64()*(max-min), nil
+}
+
+// randomChoice returns random element of list
+func (g *generator) randomChoice(items interface{}) (interface{}, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errors.Errorf("random_choice expects list, got %T", items)
+	}
+	if v.Len() == 0 {
+		return nil, errors.New("random_choice from empty list")
+	}
+	return v.Index(g.r.Intn(v.Len())).Interface(), nil
+}
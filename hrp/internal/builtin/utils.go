@@ -2,10 +2,8 @@ package builtin
 
 import (
 	"bytes"
-	"encoding/csv"
 	builtinJSON "encoding/json"
 	"fmt"
-	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -163,14 +161,6 @@ func Contains(s []string, e string) bool {
 	return false
 }
 
-func GetRandomNumber(min, max int) int {
-	if min > max {
-		return 0
-	}
-	r := rand.Intn(max - min + 1)
-	return min + r
-}
-
 func Interface2Float64(i interface{}) (float64, error) {
 	switch i.(type) {
 	case int:
@@ -222,31 +212,6 @@ func LoadFile(path string, structObj interface{}) (err error) {
 	return err
 }
 
-func loadFromCSV(path string) []map[string]interface{} {
-	log.Info().Str("path", path).Msg("load csv file")
-	file, err := readFile(path)
-	if err != nil {
-		log.Error().Err(err).Msg("read csv file failed")
-		panic(err)
-	}
-
-	r := csv.NewReader(strings.NewReader(string(file)))
-	content, err := r.ReadAll()
-	if err != nil {
-		log.Error().Err(err).Msg("parse csv file failed")
-		panic(err)
-	}
-	var result []map[string]interface{}
-	for i := 1; i < len(content); i++ {
-		row := make(map[string]interface{})
-		for j := 0; j < len(content[i]); j++ {
-			row[content[0][j]] = content[i][j]
-		}
-		result = append(result, row)
-	}
-	return result
-}
-
 func readFile(path string) ([]byte, error) {
 	var err error
 	path, err = filepath.Abs(path)
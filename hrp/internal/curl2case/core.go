@@ -0,0 +1,284 @@
+package curl2case
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp"
+	"github.com/httprunner/httprunner/hrp/internal/har2case"
+	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/sdk"
+)
+
+func NewCurl(path string) *curl {
+	return &curl{
+		path: path,
+	}
+}
+
+// curl converts text file with one or more curl commands to testcase
+type curl struct {
+	path      string
+	outputDir string
+}
+
+func (c *curl) SetOutputDir(dir string) {
+	log.Info().Str("dir", dir).Msg("set output directory")
+	c.outputDir = dir
+}
+
+func (c *curl) GenJSON() (jsonPath string, err error) {
+	event := sdk.EventTracking{
+		Category: "ConvertTests",
+		Action:   "hrp curl2case --to-json",
+	}
+	// report start event
+	go sdk.SendEvent(event)
+	// report running timing event
+	defer sdk.SendEvent(event.StartTiming("execution"))
+
+	tCase, err := c.makeTestCase()
+	if err != nil {
+		return "", err
+	}
+	jsonPath = har2case.GenOutputPath(c.path, c.outputDir, har2case.SuffixJSON)
+	err = har2case.DumpTestCase(tCase, jsonPath)
+	return
+}
+
+func (c *curl) GenYAML() (yamlPath string, err error) {
+	event := sdk.EventTracking{
+		Category: "ConvertTests",
+		Action:   "hrp curl2case --to-yaml",
+	}
+	// report start event
+	go sdk.SendEvent(event)
+	// report running timing event
+	defer sdk.SendEvent(event.StartTiming("execution"))
+
+	tCase, err := c.makeTestCase()
+	if err != nil {
+		return "", err
+	}
+	yamlPath = har2case.GenOutputPath(c.path, c.outputDir, har2case.SuffixYAML)
+	err = har2case.DumpTestCase(tCase, yamlPath)
+	return
+}
+
+func (c *curl) makeTestCase() (*hrp.TCase, error) {
+	content, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read curl file failed")
+	}
+	commands := splitCommands(string(content))
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("no curl command found in %s", c.path)
+	}
+
+	var teststeps []*hrp.TStep
+	for _, command := range commands {
+		step, err := makeTestStep(command, filepath.Dir(c.path))
+		if err != nil {
+			return nil, err
+		}
+		teststeps = append(teststeps, step)
+	}
+
+	tCase := &hrp.TCase{
+		Config:    hrp.NewConfig("testcase description").SetVerifySSL(false),
+		TestSteps: teststeps,
+	}
+	return tCase, nil
+}
+
+// makeTestStep converts one curl command to teststep,
+// file references in data and form fields are located relative to baseDir.
+func makeTestStep(command string, baseDir string) (*hrp.TStep, error) {
+	req, err := parseCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Str("url", req.url).Msg("convert teststep")
+
+	if !strings.Contains(req.url, "://") {
+		req.url = "http://" + req.url // curl uses http by default
+	}
+	u, err := url.Parse(req.url)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse url failed")
+	}
+
+	request := &hrp.Request{
+		Method:  strings.ToUpper(req.method),
+		Params:  make(map[string]interface{}),
+		Headers: make(map[string]string),
+		Cookies: make(map[string]string),
+		Verify:  !req.insecure,
+	}
+	setParams(request.Params, u.Query())
+	u.RawQuery = ""
+	request.URL = u.String()
+
+	for _, header := range req.headers {
+		if strings.EqualFold(header[0], "Cookie") {
+			parseCookies(header[1], request.Cookies)
+			continue
+		}
+		request.Headers[header[0]] = header[1]
+	}
+	if req.cookie != "" {
+		parseCookies(req.cookie, request.Cookies)
+	}
+	if req.user != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(req.user))
+		request.Headers["Authorization"] = "Basic " + auth
+	}
+	if req.compressed {
+		request.Headers["Accept-Encoding"] = "deflate, gzip, br"
+	}
+
+	// prepare request body
+	if len(req.forms) > 0 {
+		contentType, body, err := har2case.MakeMultipartBody(req.forms, baseDir)
+		if err != nil {
+			return nil, err
+		}
+		request.Headers["Content-Type"] = contentType
+		request.Body = body
+	} else if len(req.data) > 0 {
+		data, err := loadData(req, baseDir)
+		if err != nil {
+			return nil, err
+		}
+		if req.get {
+			// -G sends data as query params
+			values, err := url.ParseQuery(data)
+			if err != nil {
+				return nil, errors.Wrap(err, "parse data as query failed")
+			}
+			setParams(request.Params, values)
+		} else {
+			request.Body = makeBody(data, request.Headers)
+		}
+	}
+
+	// curl sends POST if data specified
+	if request.Method == "" {
+		if req.head {
+			request.Method = http.MethodHead
+		} else if request.Body != nil {
+			request.Method = http.MethodPost
+		} else {
+			request.Method = http.MethodGet
+		}
+	}
+
+	return &hrp.TStep{
+		Name:       fmt.Sprintf("%s %s", request.Method, u.Path),
+		Request:    request,
+		Validators: make([]interface{}, 0),
+	}, nil
+}
+
+// loadData joins all data with & like curl, data starting with @ is read from file
+func loadData(req *curlRequest, baseDir string) (string, error) {
+	var dataList []string
+	for _, item := range req.data {
+		data, name := item.value, ""
+		isFile := strings.HasPrefix(data, "@")
+		if item.urlencode {
+			// --data-urlencode supports content, =content, name=content, @file and name@file
+			isFile = false
+			if index := strings.IndexAny(data, "=@"); index >= 0 {
+				name, data, isFile = data[:index], data[index+1:], data[index] == '@'
+			}
+		}
+		if isFile {
+			path := strings.TrimPrefix(data, "@")
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", errors.Wrap(err, "read data file failed")
+			}
+			data = string(content)
+			if !req.dataBinary && !item.urlencode {
+				// curl strips newlines for -d @file
+				data = strings.NewReplacer("\r", "", "\n", "").Replace(data)
+			}
+		}
+		if item.urlencode {
+			// curl encodes space as %20 instead of +
+			data = strings.ReplaceAll(url.QueryEscape(data), "+", "%20")
+			if name != "" {
+				data = name + "=" + data
+			}
+		}
+		dataList = append(dataList, data)
+	}
+	return strings.Join(dataList, "&"), nil
+}
+
+// setParams sets query values as request params, all values of repeated param are kept in list, e.g. a=1&a=2
+func setParams(params map[string]interface{}, values url.Values) {
+	for key, list := range values {
+		if len(list) == 1 {
+			params[key] = list[0]
+			continue
+		}
+		items := make([]interface{}, len(list))
+		for i, value := range list {
+			items[i] = value
+		}
+		params[key] = items
+	}
+}
+
+// makeBody converts data to json body if possible, and set default Content-Type like curl
+func makeBody(data string, headers map[string]string) interface{} {
+	contentType := ""
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			contentType = value
+		}
+	}
+
+	var body interface{}
+	if err := json.Unmarshal([]byte(data), &body); err == nil {
+		switch body.(type) {
+		case map[string]interface{}, []interface{}:
+			if contentType == "" {
+				headers["Content-Type"] = "application/json; charset=utf-8"
+			}
+			if contentType == "" || strings.HasPrefix(contentType, "application/json") {
+				return body
+			}
+		}
+	}
+
+	if contentType == "" {
+		headers["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	return data
+}
+
+// parseCookies parses cookies in format "a=1; b=2"
+func parseCookies(raw string, cookies map[string]string) {
+	for _, pair := range strings.Split(raw, ";") {
+		pair = strings.TrimSpace(pair)
+		index := strings.Index(pair, "=")
+		if index == -1 {
+			continue
+		}
+		cookies[pair[:index]] = pair[index+1:]
+	}
+}
@@ -0,0 +1,179 @@
+package curl2case
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/httprunner/httprunner/hrp/internal/har2case"
+)
+
+var curlPath = "../../../examples/data/curl/demo.curl"
+
+func TestSplitArgs(t *testing.T) {
+	testData := []struct {
+		raw      string
+		expected []string
+	}{
+		{`curl https://postman-echo.com/get`, []string{"curl", "https://postman-echo.com/get"}},
+		{`curl -H 'a: b c' -d "x=\"1\""`, []string{"curl", "-H", "a: b c", "-d", `x="1"`}},
+		{`curl --data-raw $'{"a":\n1}'`, []string{"curl", "--data-raw", "{\"a\":\n1}"}},
+		{`curl -d it\'s`, []string{"curl", "-d", "it's"}},
+	}
+	for _, data := range testData {
+		args, err := splitArgs(data.raw)
+		if !assert.NoError(t, err) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expected, args) {
+			t.Fail()
+		}
+	}
+
+	if _, err := splitArgs(`curl -d 'abc`); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestMakeTestStep(t *testing.T) {
+	step, err := makeTestStep(`curl -XPUT 'https://postman-echo.com/put?a=1' -d 'foo=bar' -b 'sid=123' -A hrp`, ".")
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, "PUT", step.Request.Method) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "https://postman-echo.com/put", step.Request.URL) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "1", step.Request.Params["a"]) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "foo=bar", step.Request.Body) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "application/x-www-form-urlencoded", step.Request.Headers["Content-Type"]) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "123", step.Request.Cookies["sid"]) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "hrp", step.Request.Headers["User-Agent"]) {
+		t.Fail()
+	}
+
+	// -G sends data as query params
+	step, err = makeTestStep(`curl -G https://postman-echo.com/get -d foo=bar -sS`, ".")
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, "GET", step.Request.Method) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "bar", step.Request.Params["foo"]) {
+		t.Fail()
+	}
+
+	// value of option in combined short options is not parsed as flags, repeated params are kept
+	step, err = makeTestStep(`curl -okfile -sSXPOST 'https://postman-echo.com/post?a=1&a=2&b=3' `+
+		`--data-urlencode 'msg=a b&c' --data-urlencode '=x+y'`, ".")
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.True(t, step.Request.Verify) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "POST", step.Request.Method) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{"a": []interface{}{"1", "2"}, "b": "3"}, step.Request.Params) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "msg=a%20b%26c&x%2By", step.Request.Body) {
+		t.Fail()
+	}
+
+	step, err = makeTestStep(`curl -kG https://postman-echo.com/get --data-urlencode 'q=a b'`, ".")
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.False(t, step.Request.Verify) || !assert.Equal(t, "a b", step.Request.Params["q"]) {
+		t.Fail()
+	}
+
+	// url missed
+	_, err = makeTestStep(`curl -X POST`, ".")
+	if !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestMakeTestCase(t *testing.T) {
+	tCase, err := NewCurl(curlPath).makeTestCase()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Len(t, tCase.TestSteps, 5) {
+		t.Fatal()
+	}
+
+	// get with params, cookies and compressed
+	step := tCase.TestSteps[0]
+	if !assert.Equal(t, "GET", step.Request.Method) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "bar2", step.Request.Params["foo2"]) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]string{"UserName": "debugtalk", "lang": "en"}, step.Request.Cookies) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "deflate, gzip, br", step.Request.Headers["Accept-Encoding"]) {
+		t.Fail()
+	}
+
+	// post json
+	step = tCase.TestSteps[1]
+	if !assert.Equal(t, "POST", step.Request.Method) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{"foo1": "bar1", "foo2": 12.3}, step.Request.Body) {
+		t.Fail()
+	}
+
+	// basic auth and insecure
+	step = tCase.TestSteps[2]
+	if !assert.Equal(t, "Basic YWRtaW46c2VjcmV0", step.Request.Headers["Authorization"]) {
+		t.Fail()
+	}
+	if !assert.False(t, step.Request.Verify) {
+		t.Fail()
+	}
+
+	// data binary from file keeps newlines
+	step = tCase.TestSteps[3]
+	if !assert.Equal(t, "hello\nworld\n", step.Request.Body) {
+		t.Fail()
+	}
+
+	// multipart form
+	step = tCase.TestSteps[4]
+	if !assert.Equal(t, "multipart/form-data; boundary="+har2case.MultipartBoundary, step.Request.Headers["Content-Type"]) {
+		t.Fail()
+	}
+	if !assert.Contains(t, step.Request.Body, `filename="body.txt"`) {
+		t.Fail()
+	}
+}
+
+func TestGenYAML(t *testing.T) {
+	c := NewCurl(curlPath)
+	c.SetOutputDir(t.TempDir())
+	yamlPath, err := c.GenYAML()
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.FileExists(t, yamlPath) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,335 @@
+package curl2case
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/har2case"
+)
+
+// splitCommands splits text content into curl commands,
+// each command starts with curl and may span multiple lines with trailing backslash.
+func splitCommands(content string) []string {
+	var commands []string
+	var current []string
+	// join continuation lines, e.g. "curl -X POST \
+	//   -H 'Content-Type: application/json' \
+	//   https://postman-echo.com/post"
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\\\n", " ")
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "curl ") || trimmed == "curl" {
+			if len(current) > 0 {
+				commands = append(commands, strings.Join(current, " "))
+			}
+			current = []string{trimmed}
+			continue
+		}
+		if len(current) == 0 {
+			log.Warn().Str("line", trimmed).Msg("ignore line not belonging to curl command")
+			continue
+		}
+		current = append(current, trimmed)
+	}
+	if len(current) > 0 {
+		commands = append(commands, strings.Join(current, " "))
+	}
+	return commands
+}
+
+// ansiCEscapes maps escape sequences in ANSI-C quoting, e.g. $'{"a":\n1}'
+var ansiCEscapes = map[rune]rune{
+	'n': '\n', 't': '\t', 'r': '\r', '\\': '\\', '\'': '\'', '"': '"',
+}
+
+// splitArgs splits command line into arguments like POSIX shell,
+// supporting single quotes, double quotes, ANSI-C quotes and backslash escapes.
+func splitArgs(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune // ', " or $ for ANSI-C quoting
+	escaped := false
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if escaped {
+			if quote == '$' {
+				if r, ok := ansiCEscapes[c]; ok {
+					c = r
+				} else {
+					current.WriteRune('\\')
+				}
+			}
+			current.WriteRune(c)
+			escaped = false
+			continue
+		}
+		switch {
+		case quote == '$':
+			if c == '\'' {
+				quote = 0
+			} else if c == '\\' {
+				escaped = true
+			} else {
+				current.WriteRune(c)
+			}
+		case quote == '\'':
+			// no escape in single quotes
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else if c == '\\' {
+				escaped = true
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\\':
+			escaped = true
+			inArg = true
+		case c == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			quote = '$'
+			inArg = true
+			i++
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command: %s", command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// curlData is data of -d and its variants, value of --data-urlencode is encoded when loaded
+type curlData struct {
+	value     string
+	urlencode bool
+}
+
+// curlRequest holds the request options parsed from one curl command
+type curlRequest struct {
+	method     string
+	url        string
+	headers    [][2]string
+	data       []curlData
+	dataBinary bool // keep data as is, e.g. --data-binary
+	forms      []har2case.FormField
+	user       string
+	cookie     string
+	compressed bool
+	insecure   bool
+	get        bool // send data as query params, e.g. -G
+	head       bool
+}
+
+func parseCommand(command string) (*curlRequest, error) {
+	args, err := splitArgs(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, fmt.Errorf("not a curl command: %s", command)
+	}
+
+	req := &curlRequest{}
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) > 2 && arg[0] == '-' && arg[1] != '-' {
+			// combined short options, e.g. -sSLk, -XPOST
+			expanded := splitShortOptions(arg)
+			args = append(args[:i], append(expanded, args[i+1:]...)...)
+			arg = args[i]
+		}
+
+		// options with value, e.g. -X POST, --request=POST
+		name, value, hasValue := arg, "", false
+		if strings.HasPrefix(arg, "--") && strings.Contains(arg, "=") {
+			index := strings.Index(arg, "=")
+			name, value, hasValue = arg[:index], arg[index+1:], true
+		}
+		nextValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("option %s requires a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "-X", "--request":
+			if req.method, err = nextValue(); err != nil {
+				return nil, err
+			}
+		case "-H", "--header":
+			header, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+			index := strings.Index(header, ":")
+			if index == -1 {
+				log.Warn().Str("header", header).Msg("ignore invalid header")
+				continue
+			}
+			req.headers = append(req.headers, [2]string{
+				strings.TrimSpace(header[:index]), strings.TrimSpace(header[index+1:]),
+			})
+		case "-d", "--data", "--data-raw", "--data-ascii":
+			data, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+			req.data = append(req.data, curlData{value: data})
+		case "--data-urlencode":
+			data, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+			req.data = append(req.data, curlData{value: data, urlencode: true})
+		case "--data-binary":
+			data, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+			req.data = append(req.data, curlData{value: data})
+			req.dataBinary = true
+		case "-F", "--form":
+			form, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+			index := strings.Index(form, "=")
+			if index == -1 {
+				return nil, fmt.Errorf("invalid form field: %s", form)
+			}
+			field := har2case.FormField{Name: form[:index], Value: form[index+1:]}
+			if strings.HasPrefix(field.Value, "@") {
+				// e.g. -F "file=@data/a.txt;type=text/plain"
+				field.Value = strings.Split(strings.TrimPrefix(field.Value, "@"), ";")[0]
+				field.IsFile = true
+			}
+			req.forms = append(req.forms, field)
+		case "-u", "--user":
+			if req.user, err = nextValue(); err != nil {
+				return nil, err
+			}
+		case "-b", "--cookie":
+			if req.cookie, err = nextValue(); err != nil {
+				return nil, err
+			}
+		case "-A", "--user-agent":
+			userAgent, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+			req.headers = append(req.headers, [2]string{"User-Agent", userAgent})
+		case "-e", "--referer":
+			referer, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+			req.headers = append(req.headers, [2]string{"Referer", referer})
+		case "--url":
+			if req.url, err = nextValue(); err != nil {
+				return nil, err
+			}
+		case "--compressed":
+			req.compressed = true
+		case "-k", "--insecure":
+			req.insecure = true
+		case "-G", "--get":
+			req.get = true
+		case "-I", "--head":
+			req.head = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				if optionTakesValue(name) && !hasValue {
+					i++ // skip option value
+				}
+				log.Warn().Str("option", arg).Msg("ignore unsupported curl option")
+				continue
+			}
+			req.url = arg
+		}
+	}
+
+	if req.url == "" {
+		return nil, errors.Errorf("url not found in curl command: %s", command)
+	}
+	return req, nil
+}
+
+// booleanShortOptions lists curl short options without value, other short options take a value
+const booleanShortOptions = "012346BGIJLMNORSVZafgijklnpqsv"
+
+// splitShortOptions splits combined short options like curl, e.g. -sSLk => -s -S -L -k.
+// The rest of the argument after the first option taking value is its value, e.g. -sXPOST => -s -X POST,
+// -okfile => -o kfile.
+func splitShortOptions(arg string) []string {
+	var args []string
+	for i := 1; i < len(arg); i++ {
+		option := "-" + arg[i:i+1]
+		args = append(args, option)
+		if optionTakesValue(option) {
+			if i+1 < len(arg) {
+				args = append(args, arg[i+1:])
+			}
+			break
+		}
+	}
+	return args
+}
+
+func optionTakesValue(option string) bool {
+	if len(option) == 2 && option[0] == '-' && option[1] != '-' {
+		return !strings.ContainsRune(booleanShortOptions, rune(option[1]))
+	}
+	return optionsWithValue[option]
+}
+
+// optionsWithValue lists curl options which take a value, used to skip unsupported options correctly
+var optionsWithValue = map[string]bool{
+	"-X": true, "--request": true,
+	"-H": true, "--header": true,
+	"-d": true, "--data": true, "--data-raw": true, "--data-ascii": true, "--data-urlencode": true, "--data-binary": true,
+	"-F": true, "--form": true,
+	"-u": true, "--user": true,
+	"-b": true, "--cookie": true,
+	"-A": true, "--user-agent": true,
+	"-e": true, "--referer": true,
+	"-o": true, "--output": true,
+	"-m": true, "--max-time": true,
+	"-x": true, "--proxy": true,
+	"-w": true, "--write-out": true,
+	"-c": true, "--cookie-jar": true,
+	"--url": true, "--connect-timeout": true, "--retry": true, "--resolve": true, "--cacert": true, "--cert": true, "--key": true,
+}
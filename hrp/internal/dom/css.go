@@ -0,0 +1,138 @@
+package dom
+
+import (
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/xmlquery"
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+)
+
+// CSS selects elements on document with CSS selector, and returns string value of each selected element.
+// Trimmed text of element is returned by default, ::text selects own text of elements and ::attr(name)
+// selects attribute value. Tag names of XML document are matched case-insensitively as in HTML.
+func CSS(doc *Document, selector string) ([]string, error) {
+	selector, pseudo, attr, err := splitPseudoElement(selector)
+	if err != nil {
+		return nil, err
+	}
+	group, err := cascadia.ParseGroup(selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid css selector %q", selector)
+	}
+
+	var values []string
+	for _, element := range cascadia.QueryAll(doc.selectorRoot(), group) {
+		switch pseudo {
+		case "text":
+			values = append(values, strings.TrimSpace(ownText(element)))
+		case "attr":
+			if value, ok := attrValue(element, attr); ok {
+				values = append(values, value)
+			}
+		default:
+			values = append(values, strings.TrimSpace(text(element)))
+		}
+	}
+	return values, nil
+}
+
+// splitPseudoElement splits trailing ::text or ::attr(name) from selector
+func splitPseudoElement(selector string) (string, string, string, error) {
+	selector = strings.TrimSpace(selector)
+	index := strings.LastIndex(selector, "::")
+	if index == -1 {
+		return selector, "", "", nil
+	}
+	pseudo := selector[index+2:]
+	selector = strings.TrimSpace(selector[:index])
+	switch {
+	case pseudo == "text":
+		return selector, "text", "", nil
+	case strings.HasPrefix(pseudo, "attr(") && strings.HasSuffix(pseudo, ")"):
+		attr := strings.TrimSpace(pseudo[len("attr(") : len(pseudo)-1])
+		if attr == "" {
+			return "", "", "", errors.New("invalid css selector: attribute name is empty in ::attr()")
+		}
+		return selector, "attr", attr, nil
+	}
+	return "", "", "", errors.Errorf("invalid css selector: unsupported pseudo element ::%s", pseudo)
+}
+
+// selectorRoot returns html root node to be matched with CSS selector,
+// XML document is converted to html nodes with lower-cased tag names.
+func (doc *Document) selectorRoot() *html.Node {
+	if doc.html != nil {
+		return doc.html
+	}
+	if doc.cssRoot == nil {
+		doc.cssRoot = convertXMLNode(doc.xml)
+	}
+	return doc.cssRoot
+}
+
+func convertXMLNode(node *xmlquery.Node) *html.Node {
+	converted := &html.Node{}
+	switch node.Type {
+	case xmlquery.DocumentNode:
+		converted.Type = html.DocumentNode
+	case xmlquery.ElementNode:
+		converted.Type = html.ElementNode
+		converted.Data = strings.ToLower(xmlName(node.Prefix, node.Data))
+		for _, attr := range node.Attr {
+			converted.Attr = append(converted.Attr, html.Attribute{
+				Key: xmlName(attr.Name.Space, attr.Name.Local),
+				Val: attr.Value,
+			})
+		}
+	case xmlquery.TextNode, xmlquery.CharDataNode:
+		converted.Type = html.TextNode
+		converted.Data = node.Data
+	default:
+		return nil
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if c := convertXMLNode(child); c != nil {
+			converted.AppendChild(c)
+		}
+	}
+	return converted
+}
+
+func attrValue(node *html.Node, name string) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// text returns concatenated text of all descendant text nodes
+func text(node *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode {
+				b.WriteString(child.Data)
+			} else {
+				walk(child)
+			}
+		}
+	}
+	walk(node)
+	return b.String()
+}
+
+// ownText returns concatenated text of child text nodes
+func ownText(node *html.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			b.WriteString(child.Data)
+		}
+	}
+	return b.String()
+}
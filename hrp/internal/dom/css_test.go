@@ -0,0 +1,105 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testHTML = `<html><head><title>Shop</title></head><body>
+<div id="main" class="container">
+  <h1>Products <small>new</small></h1>
+  <ul class="products">
+    <li class="product" data-id="1"><a href="/p/1">Apple</a></li>
+    <li class="product sale" data-id="2"><a href="/p/2">Banana</a></li>
+    <li class="product" data-id="3" lang="en-US"><a href="/p/3">Cherry</a></li>
+  </ul>
+  <p>footer</p>
+</div></body></html>`
+
+func TestCSS(t *testing.T) {
+	doc, err := ParseHTML([]byte(testHTML))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	testData := []struct {
+		selector string
+		expected []string
+	}{
+		{"title", []string{"Shop"}},
+		{"#main h1", []string{"Products new"}},
+		{"#main h1::text", []string{"Products"}},
+		{"li.product a", []string{"Apple", "Banana", "Cherry"}},
+		{"li.sale", []string{"Banana"}},
+		{".products > li > a::attr(href)", []string{"/p/1", "/p/2", "/p/3"}},
+		{"li[data-id='3']", []string{"Cherry"}},
+		{"li[data-id]::attr(data-id)", []string{"1", "2", "3"}},
+		{"li[class~=sale]", []string{"Banana"}},
+		{"li[lang|=en]", []string{"Cherry"}},
+		{"a[href^='/p/']", []string{"Apple", "Banana", "Cherry"}},
+		{"a[href$='2']", []string{"Banana"}},
+		{"a[href*='p/3']", []string{"Cherry"}},
+		{"li:first-child", []string{"Apple"}},
+		{"li:last-child", []string{"Cherry"}},
+		{"li:nth-child(2)", []string{"Banana"}},
+		{"li:nth-child(odd)", []string{"Apple", "Cherry"}},
+		{"li:nth-child(2n)", []string{"Banana"}},
+		{"li:nth-child(-n+2)", []string{"Apple", "Banana"}},
+		{"li:not(.sale)", []string{"Apple", "Cherry"}},
+		{"li.sale + li", []string{"Cherry"}},
+		{"h1 ~ p", []string{"footer"}},
+		{"title, p", []string{"Shop", "footer"}},
+		{"div > a", nil},
+		{"LI.sale", []string{"Banana"}},
+		{"li:nth-of-type(3)", []string{"Cherry"}},
+		{"li:nth-last-child(1)", []string{"Cherry"}},
+		{"#main > ul:first-of-type li:nth-of-type(2)", []string{"Banana"}},
+		{"li:has(a[href$='2'])", []string{"Banana"}},
+	}
+	for _, data := range testData {
+		values, err := CSS(doc, data.selector)
+		if !assert.Nil(t, err, data.selector) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expected, values, data.selector) {
+			t.Fail()
+		}
+	}
+}
+
+func TestCSSInvalid(t *testing.T) {
+	doc, err := ParseHTML([]byte(testHTML))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	for _, selector := range []string{"", "li[", "li[data-id='1]", "li:unknown", "li::before", "li::attr()", "li:nth-child(x)", "li >"} {
+		if _, err := CSS(doc, selector); !assert.Error(t, err, selector) {
+			t.Fail()
+		}
+	}
+}
+
+func TestCSSOnXML(t *testing.T) {
+	doc, err := ParseXML([]byte(testXML))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	testData := []struct {
+		selector string
+		expected []string
+	}{
+		{"book[lang=zh] title", []string{"Python"}},
+		{"library > book:nth-of-type(1)::attr(id)", []string{"1"}},
+		{"book:last-of-type price", []string{"45.5"}},
+		{"Owner", []string{"Tom"}},
+	}
+	for _, data := range testData {
+		values, err := CSS(doc, data.selector)
+		if !assert.Nil(t, err, data.selector) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expected, values, data.selector) {
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package dom
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xmlquery"
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+)
+
+/*
+dom parses XML and HTML documents, which could be queried with XPath 1.0 expression
+evaluated by github.com/antchfx/xpath, or CSS selector matched by github.com/andybalholm/cascadia.
+*/
+
+// Document is parsed XML or HTML document, only one of xml and html is set
+type Document struct {
+	xml  *xmlquery.Node
+	html *html.Node
+
+	cssRoot *html.Node // XML document converted to html nodes for CSS selector, converted lazily
+}
+
+// ParseXML parses XML document, the declared charset is respected
+func ParseXML(data []byte) (*Document, error) {
+	root, err := xmlquery.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse xml failed")
+	}
+	var elements int
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case xmlquery.ElementNode:
+			elements++
+		case xmlquery.TextNode, xmlquery.CharDataNode:
+			if strings.TrimSpace(child.Data) != "" {
+				return nil, errors.New("parse xml failed: text is not allowed outside root element")
+			}
+		}
+	}
+	if elements != 1 {
+		return nil, errors.New("parse xml failed: document should have exactly one root element")
+	}
+	return &Document{xml: root}, nil
+}
+
+// ParseHTML parses HTML document
+func ParseHTML(data []byte) (*Document, error) {
+	root, err := htmlquery.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse html failed")
+	}
+	return &Document{html: root}, nil
+}
+
+// ToMap converts XML document to map, thus it could be searched with jmespath.
+// Attributes are prefixed with @, text of element with attributes or child elements is keyed by #text,
+// and repeated child elements are converted to list, e.g.
+// <books><book id="1">Go</book><book id="2">Python</book></books> =>
+// {"books": {"book": [{"@id": "1", "#text": "Go"}, {"@id": "2", "#text": "Python"}]}}
+// Nil is returned for HTML document.
+func ToMap(doc *Document) map[string]interface{} {
+	if doc.xml == nil {
+		return nil
+	}
+	result := make(map[string]interface{})
+	for child := doc.xml.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == xmlquery.ElementNode {
+			addValue(result, xmlName(child.Prefix, child.Data), elementValue(child))
+		}
+	}
+	return result
+}
+
+func elementValue(element *xmlquery.Node) interface{} {
+	var children []*xmlquery.Node
+	var text strings.Builder
+	for child := element.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case xmlquery.ElementNode:
+			children = append(children, child)
+		case xmlquery.TextNode, xmlquery.CharDataNode:
+			text.WriteString(child.Data)
+		}
+	}
+	ownText := strings.TrimSpace(text.String())
+	if len(element.Attr) == 0 && len(children) == 0 {
+		return ownText
+	}
+	value := make(map[string]interface{})
+	for _, attr := range element.Attr {
+		value["@"+xmlName(attr.Name.Space, attr.Name.Local)] = attr.Value
+	}
+	for _, child := range children {
+		addValue(value, xmlName(child.Prefix, child.Data), elementValue(child))
+	}
+	if ownText != "" {
+		value["#text"] = ownText
+	}
+	return value
+}
+
+// xmlName returns qualified name with namespace prefix, e.g. soap:Body
+func xmlName(prefix, local string) string {
+	if prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+func addValue(m map[string]interface{}, key string, value interface{}) {
+	existing, ok := m[key]
+	if !ok {
+		m[key] = value
+		return
+	}
+	if list, ok := existing.([]interface{}); ok {
+		m[key] = append(list, value)
+		return
+	}
+	m[key] = []interface{}{existing, value}
+}
@@ -0,0 +1,109 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testXML = `<?xml version="1.0" encoding="UTF-8"?>
+<library name="city">
+    <!-- books on shelf -->
+    <book id="1" lang="en">
+        <title>Go in Action</title>
+        <price>30</price>
+    </book>
+    <book id="2" lang="zh">
+        <title>Python</title>
+        <price>45.5</price>
+    </book>
+    <owner>Tom</owner>
+</library>`
+
+func TestParseXML(t *testing.T) {
+	if _, err := ParseXML([]byte(testXML)); !assert.Nil(t, err) {
+		t.Fail()
+	}
+
+	invalid := []string{
+		`<a><b></a>`,
+		`<a>`,
+		`<a></a><b></b>`,
+		`plain text`,
+		`<m:a>undeclared namespace</m:a>`,
+	}
+	for _, data := range invalid {
+		if _, err := ParseXML([]byte(data)); !assert.Error(t, err, data) {
+			t.Fail()
+		}
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	doc, err := ParseHTML([]byte(`<!DOCTYPE html><html><head><title>Demo</title></head>
+<body><!-- comment --><p class="intro">Hello <b>world</b></p></body></html>`))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	if !assert.Nil(t, ToMap(doc)) {
+		t.Fail()
+	}
+	values, err := CSS(doc, "p.intro")
+	if !assert.Nil(t, err) || !assert.Equal(t, []string{"Hello world"}, values) {
+		t.Fail()
+	}
+	values, err = CSS(doc, "p.intro::text")
+	if !assert.Nil(t, err) || !assert.Equal(t, []string{"Hello"}, values) {
+		t.Fail()
+	}
+}
+
+func TestToMap(t *testing.T) {
+	doc, err := ParseXML([]byte(testXML))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	expected := map[string]interface{}{
+		"library": map[string]interface{}{
+			"@name": "city",
+			"book": []interface{}{
+				map[string]interface{}{
+					"@id": "1", "@lang": "en", "title": "Go in Action", "price": "30",
+				},
+				map[string]interface{}{
+					"@id": "2", "@lang": "zh", "title": "Python", "price": "45.5",
+				},
+			},
+			"owner": "Tom",
+		},
+	}
+	if !assert.Equal(t, expected, ToMap(doc)) {
+		t.Fail()
+	}
+
+	doc, err = ParseXML([]byte(`<item type="a">text</item>`))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{
+		"item": map[string]interface{}{"@type": "a", "#text": "text"},
+	}, ToMap(doc)) {
+		t.Fail()
+	}
+
+	doc, err = ParseXML([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body><m:Result xmlns:m="urn:demo">ok</m:Result></soap:Body></soap:Envelope>`))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{
+		"soap:Envelope": map[string]interface{}{
+			"@xmlns:soap": "http://schemas.xmlsoap.org/soap/envelope/",
+			"soap:Body": map[string]interface{}{
+				"m:Result": map[string]interface{}{"@xmlns:m": "urn:demo", "#text": "ok"},
+			},
+		},
+	}, ToMap(doc)) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,68 @@
+package dom
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/pkg/errors"
+)
+
+// XPath evaluates XPath 1.0 expression on document, result is either node set (*xpath.NodeIterator),
+// string, float64 or bool. Name test with namespace prefix matches prefix of element, e.g. soap:Body,
+// and local-name() could be used to match element regardless of its prefix.
+func XPath(doc *Document, expr string) (result interface{}, err error) {
+	compiled, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid xpath %q", expr)
+	}
+	// xpath panics on some runtime errors, e.g. argument of wrong type
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, errors.Errorf("evaluate xpath %q failed: %v", expr, r)
+		}
+	}()
+	return compiled.Evaluate(navigator(doc)), nil
+}
+
+func navigator(doc *Document) xpath.NodeNavigator {
+	if doc.xml != nil {
+		return xmlquery.CreateXPathNavigator(doc.xml)
+	}
+	return htmlquery.CreateXPathNavigator(doc.html)
+}
+
+// XPathValue evaluates XPath expression and converts result to value used in extraction and validation:
+// node set with one node is converted to its trimmed string value, node set with multiple nodes is converted
+// to []interface{} of string values, empty node set is converted to nil, and integral number is converted to int.
+func XPathValue(doc *Document, expr string) (interface{}, error) {
+	result, err := XPath(doc, expr)
+	if err != nil {
+		return nil, err
+	}
+	switch v := result.(type) {
+	case *xpath.NodeIterator:
+		var values []interface{}
+		for v.MoveNext() {
+			values = append(values, strings.TrimSpace(v.Current().Value()))
+		}
+		switch len(values) {
+		case 0:
+			return nil, nil
+		case 1:
+			return values[0], nil
+		}
+		return values, nil
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return int(v), nil
+		}
+	case string, bool:
+	default:
+		return nil, errors.Errorf("evaluate xpath %q failed: unexpected result type %s", expr, fmt.Sprintf("%T", v))
+	}
+	return result, nil
+}
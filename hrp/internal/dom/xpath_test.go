@@ -0,0 +1,107 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXPathValue(t *testing.T) {
+	doc, err := ParseXML([]byte(testXML))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	testData := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"/library/owner", "Tom"},
+		{"/library/@name", "city"},
+		{"//title", []interface{}{"Go in Action", "Python"}},
+		{"//book[1]/title", "Go in Action"},
+		{"//book[last()]/title/text()", "Python"},
+		{"//book[@id='2']/@lang", "zh"},
+		{"//book[price>40]/title", "Python"},
+		{"//book[price<=30 and @lang='en']/@id", "1"},
+		{"//book[title='Python' or @id='1']/@id", []interface{}{"1", "2"}},
+		{"//book[contains(title, 'Action')]/@id", "1"},
+		{"//book[starts-with(title, 'Py')]/@id", "2"},
+		{"//book[not(@lang='en')]/title", "Python"},
+		{"//book[position()=2]/price", "45.5"},
+		{"//title[.='Python']/../@id", "2"},
+		{"//price/parent::book/@id", []interface{}{"1", "2"}},
+		{"//book[1]/following-sibling::book/@id", "2"},
+		{"//owner/preceding-sibling::*[1]/@id", "2"},
+		{"//owner/ancestor::library/@name", "city"},
+		{"/library/book/title | /library/owner", []interface{}{"Go in Action", "Python", "Tom"}},
+		{"//book/@*", []interface{}{"1", "en", "2", "zh"}},
+		{"count(//book)", 2},
+		{"string-length(//owner)", 3},
+		{"normalize-space(/library/owner)", "Tom"},
+		{"//book[@id='3']", nil},
+		{"count(//book) = 2", true},
+		{"//book/price > 40", true},
+		{"(//book)[1]/title", "Go in Action"},
+		{"(//book/title)[last()]", "Python"},
+		{"sum(//price)", 75.5},
+		{"sum(//book[1]/price)", 30},
+		{"name(/library/*[last()])", "owner"},
+		{"local-name(//book[2])", "book"},
+		{"concat(//book[1]/@id, '-', //book[1]/@lang)", "1-en"},
+		{"substring-before(//book[1]/title, ' ')", "Go"},
+		{"//book[string-length(title) > 6]/@id", "1"},
+		{"boolean(//book[@lang='fr'])", false},
+	}
+	for _, data := range testData {
+		value, err := XPathValue(doc, data.expr)
+		if !assert.Nil(t, err, data.expr) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expected, value, data.expr) {
+			t.Fail()
+		}
+	}
+}
+
+func TestXPathNamespace(t *testing.T) {
+	doc, err := ParseXML([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body><m:Result xmlns:m="urn:demo">ok</m:Result></soap:Body></soap:Envelope>`))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	for _, expr := range []string{"/soap:Envelope/soap:Body/m:Result", "//*[local-name()='Result']", "//m:Result"} {
+		value, err := XPathValue(doc, expr)
+		if !assert.Nil(t, err, expr) {
+			t.Fail()
+		}
+		if !assert.Equal(t, "ok", value, expr) {
+			t.Fail()
+		}
+	}
+}
+
+func TestXPathHTML(t *testing.T) {
+	doc, err := ParseHTML([]byte(`<html><body><ul id="list"><li class="item">a</li><li class="item active">b</li></ul></body></html>`))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	value, err := XPathValue(doc, "(//ul[@id='list']/li)[contains(@class, 'active')]")
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "b", value) {
+		t.Fail()
+	}
+}
+
+func TestXPathInvalid(t *testing.T) {
+	doc, err := ParseXML([]byte(testXML))
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+	for _, expr := range []string{"", "//book[", "//book[@id='1]", "//unknown-axis::a", "//book[foo(1)]", "sum(("} {
+		if _, err := XPath(doc, expr); !assert.Error(t, err, expr) {
+			t.Fail()
+		}
+	}
+}
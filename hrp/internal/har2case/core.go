@@ -18,8 +18,8 @@ import (
 )
 
 const (
-	suffixJSON = ".json"
-	suffixYAML = ".yaml"
+	SuffixJSON = ".json"
+	SuffixYAML = ".yaml"
 )
 
 func NewHAR(path string) *har {
@@ -65,8 +65,8 @@ func (h *har) GenJSON() (jsonPath string, err error) {
 	if err != nil {
 		return "", err
 	}
-	jsonPath = h.genOutputPath(suffixJSON)
-	err = builtin.Dump2JSON(tCase, jsonPath)
+	jsonPath = GenOutputPath(h.path, h.outputDir, SuffixJSON)
+	err = DumpTestCase(tCase, jsonPath)
 	return
 }
 
@@ -84,8 +84,8 @@ func (h *har) GenYAML() (yamlPath string, err error) {
 	if err != nil {
 		return "", err
 	}
-	yamlPath = h.genOutputPath(suffixYAML)
-	err = builtin.Dump2YAML(tCase, yamlPath)
+	yamlPath = GenOutputPath(h.path, h.outputDir, SuffixYAML)
+	err = DumpTestCase(tCase, yamlPath)
 	return
 }
 
@@ -369,12 +369,26 @@ func (s *tStep) makeValidate(entry *Entry) error {
 	return nil
 }
 
-func (h *har) genOutputPath(suffix string) string {
-	file := getFilenameWithoutExtension(h.path) + suffix
-	if h.outputDir != "" {
-		return filepath.Join(h.outputDir, file)
+// GenOutputPath generates testcase output path for converted source file,
+// testcase is located in the same dir with source file if outputDir not specified.
+func GenOutputPath(srcPath, outputDir, suffix string) string {
+	file := getFilenameWithoutExtension(srcPath) + suffix
+	if outputDir != "" {
+		return filepath.Join(outputDir, file)
 	} else {
-		return filepath.Join(filepath.Dir(h.path), file)
+		return filepath.Join(filepath.Dir(srcPath), file)
+	}
+}
+
+// DumpTestCase dumps converted testcase to json/yaml file according to file suffix.
+func DumpTestCase(tCase *hrp.TCase, path string) error {
+	switch filepath.Ext(path) {
+	case SuffixJSON:
+		return builtin.Dump2JSON(tCase, path)
+	case SuffixYAML, ".yml":
+		return builtin.Dump2YAML(tCase, path)
+	default:
+		return builtin.ErrUnsupportedFileExt
 	}
 }
 
@@ -0,0 +1,57 @@
+package har2case
+
+import (
+	"bytes"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// MultipartBoundary is fixed to keep converted testcases stable
+const MultipartBoundary = "----HttpRunnerFormBoundary"
+
+// FormField represents one field of multipart form, which is used by converters of other formats.
+type FormField struct {
+	Name   string
+	Value  string // file path if IsFile
+	IsFile bool
+}
+
+// MakeMultipartBody builds multipart/form-data body with fixed boundary, content of file field is
+// read from file and relative path is located in baseDir. Content-Type header and body are returned.
+func MakeMultipartBody(fields []FormField, baseDir string) (contentType string, body string, err error) {
+	buffer := new(bytes.Buffer)
+	writer := multipart.NewWriter(buffer)
+	if err := writer.SetBoundary(MultipartBoundary); err != nil {
+		return "", "", err
+	}
+	for _, field := range fields {
+		if !field.IsFile {
+			if err := writer.WriteField(field.Name, field.Value); err != nil {
+				return "", "", err
+			}
+			continue
+		}
+		path := field.Value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", errors.Wrap(err, "read form file failed")
+		}
+		part, err := writer.CreateFormFile(field.Name, filepath.Base(path))
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := part.Write(content); err != nil {
+			return "", "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", "", err
+	}
+	return writer.FormDataContentType(), buffer.String(), nil
+}
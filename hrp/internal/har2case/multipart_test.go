@@ -0,0 +1,41 @@
+package har2case
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	contentType, body, err := MakeMultipartBody([]FormField{
+		{Name: "user", Value: "leo"},
+		{Name: "file", Value: "a.txt", IsFile: true},
+	}, dir)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, "multipart/form-data; boundary="+MultipartBoundary, contentType) {
+		t.Fail()
+	}
+	expected := "--" + MultipartBoundary + "\r\n" +
+		"Content-Disposition: form-data; name=\"user\"\r\n\r\nleo\r\n" +
+		"--" + MultipartBoundary + "\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"a.txt\"\r\n" +
+		"Content-Type: application/octet-stream\r\n\r\nhello\r\n" +
+		"--" + MultipartBoundary + "--\r\n"
+	if !assert.Equal(t, expected, body) {
+		t.Fail()
+	}
+
+	_, _, err = MakeMultipartBody([]FormField{{Name: "file", Value: "not_found.txt", IsFile: true}}, dir)
+	if !assert.Error(t, err) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,902 @@
+package jsonpath
+
+import (
+	builtinJSON "encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+/*
+JSONPath subset (https://goessner.net/articles/JsonPath/):
+- root and current node: $, @
+- child: $.store.book, $['store']['book'], $.*, $[*]
+- recursive descent: $..author, $..*, $..['author']
+- array index and slice: $.book[0], $.book[-1], $.book[0,1], $.book[:2], $.book[1:], $.book[::2]
+- filter: $.book[?(@.price < 10)], $.book[?(@.isbn)], $.book[?(@.author =~ /^Nigel/ && !@.sold)]
+
+Filter operators: ==, !=, <, <=, >, >=, =~ (regexp), && , || and !.
+*/
+
+// Path is compiled JSONPath expression
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+// Compile parses JSONPath expression
+func Compile(expr string) (*Path, error) {
+	p := &parser{input: []rune(strings.TrimSpace(expr))}
+	if p.peek() != '$' {
+		return nil, errors.Errorf("invalid jsonpath %q: should start with $", expr)
+	}
+	p.pos++
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid jsonpath %q", expr)
+	}
+	if !p.done() {
+		return nil, errors.Errorf("invalid jsonpath %q: unexpected character %q", expr, p.peek())
+	}
+	return &Path{expr: expr, segments: segments}, nil
+}
+
+// Definite reports whether path selects at most one value, i.e. path has no wildcard, recursive descent,
+// slice, union or filter, thus the result could be used as a single value instead of a list.
+func (p *Path) Definite() bool {
+	for _, s := range p.segments {
+		if !s.definite() {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Search returns all values matched by path in data, data should be decoded from json,
+// e.g. map[string]interface{}, []interface{}, json.Number, float64, string, bool and nil.
+func (p *Path) Search(data interface{}) []interface{} {
+	return evaluate(p.segments, data, data)
+}
+
+// Replace replaces values matched by path in data with the result of replace in place,
+// data should be decoded from json as in Search, and root of data is never replaced.
+func (p *Path) Replace(data interface{}, replace func(value interface{}) interface{}) {
+	for _, r := range locate(p.segments, data, data) {
+		r.set(replace(r.value()))
+	}
+}
+
+// Search compiles JSONPath expression and searches it in data
+func Search(expr string, data interface{}) ([]interface{}, error) {
+	path, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return path.Search(data), nil
+}
+
+func evaluate(segments []segment, root, current interface{}) []interface{} {
+	if len(segments) == 0 {
+		return []interface{}{current}
+	}
+	var values []interface{}
+	for _, r := range locate(segments, root, current) {
+		values = append(values, r.value())
+	}
+	return values
+}
+
+// locate returns references of values selected by the last segment
+func locate(segments []segment, root, current interface{}) []ref {
+	values := []interface{}{current}
+	var refs []ref
+	for _, s := range segments {
+		refs = nil
+		for _, value := range values {
+			refs = append(refs, s.apply(root, value)...)
+		}
+		values = make([]interface{}, len(refs))
+		for i, r := range refs {
+			values[i] = r.value()
+		}
+	}
+	return refs
+}
+
+// ref refers to child of object or array, thus the child could be replaced in place
+type ref struct {
+	parent interface{} // map[string]interface{} or []interface{}
+	key    interface{} // string key of object or int index of array
+}
+
+func (r ref) value() interface{} {
+	switch parent := r.parent.(type) {
+	case map[string]interface{}:
+		return parent[r.key.(string)]
+	case []interface{}:
+		return parent[r.key.(int)]
+	}
+	return nil
+}
+
+func (r ref) set(value interface{}) {
+	switch parent := r.parent.(type) {
+	case map[string]interface{}:
+		parent[r.key.(string)] = value
+	case []interface{}:
+		parent[r.key.(int)] = value
+	}
+}
+
+/* segments */
+
+type segment interface {
+	apply(root, value interface{}) []ref
+	definite() bool
+}
+
+// childSegment selects children with selectors, e.g. .name, [0], ['a','b'], [*], [1:3], [?(@.a)]
+type childSegment struct {
+	selectors []selector
+}
+
+func (s *childSegment) apply(root, value interface{}) []ref {
+	var result []ref
+	for _, sel := range s.selectors {
+		result = append(result, sel.selectFrom(root, value)...)
+	}
+	return result
+}
+
+func (s *childSegment) definite() bool {
+	return len(s.selectors) == 1 && s.selectors[0].definite()
+}
+
+// descendantSegment applies selectors on value and all its descendants, e.g. ..name, ..*
+type descendantSegment struct {
+	child *childSegment
+}
+
+func (s *descendantSegment) apply(root, value interface{}) []ref {
+	var result []ref
+	for _, node := range descendants(value) {
+		result = append(result, s.child.apply(root, node)...)
+	}
+	return result
+}
+
+func (s *descendantSegment) definite() bool {
+	return false
+}
+
+// descendants returns value and all its descendants in document order, keys of object are sorted
+func descendants(value interface{}) []interface{} {
+	result := []interface{}{value}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			result = append(result, descendants(v[key])...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			result = append(result, descendants(item)...)
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/* selectors */
+
+type selector interface {
+	selectFrom(root, value interface{}) []ref
+	definite() bool
+}
+
+type nameSelector string
+
+func (s nameSelector) selectFrom(root, value interface{}) []ref {
+	if m, ok := value.(map[string]interface{}); ok {
+		if _, ok := m[string(s)]; ok {
+			return []ref{{parent: m, key: string(s)}}
+		}
+	}
+	return nil
+}
+
+func (s nameSelector) definite() bool {
+	return true
+}
+
+type wildcardSelector struct{}
+
+func (s wildcardSelector) selectFrom(root, value interface{}) []ref {
+	var result []ref
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			result = append(result, ref{parent: v, key: key})
+		}
+	case []interface{}:
+		for i := range v {
+			result = append(result, ref{parent: v, key: i})
+		}
+	}
+	return result
+}
+
+func (s wildcardSelector) definite() bool {
+	return false
+}
+
+type indexSelector int
+
+func (s indexSelector) selectFrom(root, value interface{}) []ref {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	index := int(s)
+	if index < 0 {
+		index += len(list)
+	}
+	if index < 0 || index >= len(list) {
+		return nil
+	}
+	return []ref{{parent: list, key: index}}
+}
+
+func (s indexSelector) definite() bool {
+	return true
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s *sliceSelector) selectFrom(root, value interface{}) []ref {
+	list, ok := value.([]interface{})
+	if !ok || s.step == 0 {
+		return nil
+	}
+	length := len(list)
+	normalize := func(index *int, defaultValue int) int {
+		if index == nil {
+			return defaultValue
+		}
+		i := *index
+		if i < 0 {
+			i += length
+		}
+		if i < 0 {
+			i = 0
+			if s.step < 0 {
+				i = -1
+			}
+		}
+		if i > length {
+			i = length
+		}
+		return i
+	}
+
+	var result []ref
+	if s.step > 0 {
+		start, end := normalize(s.start, 0), normalize(s.end, length)
+		for i := start; i < end; i += s.step {
+			result = append(result, ref{parent: list, key: i})
+		}
+	} else {
+		start, end := normalize(s.start, length-1), normalize(s.end, -1)
+		if start >= length {
+			start = length - 1
+		}
+		for i := start; i > end; i += s.step {
+			result = append(result, ref{parent: list, key: i})
+		}
+	}
+	return result
+}
+
+func (s *sliceSelector) definite() bool {
+	return false
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s *filterSelector) selectFrom(root, value interface{}) []ref {
+	var result []ref
+	for _, child := range (wildcardSelector{}).selectFrom(root, value) {
+		if toBool(s.expr.eval(root, child.value())) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+func (s *filterSelector) definite() bool {
+	return false
+}
+
+/* filter expressions */
+
+type filterExpr interface {
+	eval(root, current interface{}) interface{}
+}
+
+// pathOperand is relative path from @ or absolute path from $ in filter,
+// it is evaluated to the first matched value, or nothing if not matched.
+type pathOperand struct {
+	absolute bool
+	segments []segment
+}
+
+type nothing struct{}
+
+func (e *pathOperand) eval(root, current interface{}) interface{} {
+	start := current
+	if e.absolute {
+		start = root
+	}
+	values := evaluate(e.segments, root, start)
+	if len(values) == 0 {
+		return nothing{}
+	}
+	return values[0]
+}
+
+type literalOperand struct {
+	value interface{}
+}
+
+func (e *literalOperand) eval(root, current interface{}) interface{} {
+	return e.value
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e *notExpr) eval(root, current interface{}) interface{} {
+	return !toBool(e.expr.eval(root, current))
+}
+
+type logicalExpr struct {
+	op          string // && or ||
+	left, right filterExpr
+}
+
+func (e *logicalExpr) eval(root, current interface{}) interface{} {
+	left := toBool(e.left.eval(root, current))
+	if e.op == "&&" {
+		return left && toBool(e.right.eval(root, current))
+	}
+	return left || toBool(e.right.eval(root, current))
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right filterExpr
+	regexp      *regexp.Regexp // compiled pattern of =~
+}
+
+func (e *comparisonExpr) eval(root, current interface{}) interface{} {
+	left := e.left.eval(root, current)
+	if e.op == "=~" {
+		s, ok := left.(string)
+		return ok && e.regexp.MatchString(s)
+	}
+	right := e.right.eval(root, current)
+	if _, ok := left.(nothing); ok {
+		return false
+	}
+	if _, ok := right.(nothing); ok {
+		return false
+	}
+
+	switch e.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+	l, ok1 := toNumber(left)
+	r, ok2 := toNumber(right)
+	if ok1 && ok2 {
+		switch e.op {
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		default:
+			return l >= r
+		}
+	}
+	ls, ok1 := left.(string)
+	rs, ok2 := right.(string)
+	if !ok1 || !ok2 {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return ls < rs
+	case "<=":
+		return ls <= rs
+	case ">":
+		return ls > rs
+	default:
+		return ls >= rs
+	}
+}
+
+func equal(left, right interface{}) bool {
+	l, ok1 := toNumber(left)
+	r, ok2 := toNumber(right)
+	if ok1 && ok2 {
+		return l == r
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case builtinJSON.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func toBool(value interface{}) bool {
+	switch v := value.(type) {
+	case nothing:
+		return false
+	case bool:
+		return v
+	}
+	// existence test, e.g. [?(@.isbn)]
+	return true
+}
+
+/* parser */
+
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() rune {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.input[p.pos:]), s)
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) expect(r rune) error {
+	p.skipSpaces()
+	if p.peek() != r {
+		if p.done() {
+			return errors.Errorf("expect %q, got end of expression", r)
+		}
+		return errors.Errorf("expect %q, got %q", r, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// parseSegments parses segments until the end of path, which is end of input or an operator in filter
+func (p *parser) parseSegments() ([]segment, error) {
+	var segments []segment
+	for !p.done() {
+		switch {
+		case p.hasPrefix(".."):
+			p.pos += 2
+			var child *childSegment
+			var err error
+			if p.peek() == '[' {
+				child, err = p.parseBracket()
+			} else {
+				child, err = p.parseDotChild()
+			}
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, &descendantSegment{child: child})
+		case p.peek() == '.':
+			p.pos++
+			child, err := p.parseDotChild()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, child)
+		case p.peek() == '[':
+			child, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, child)
+		default:
+			return segments, nil
+		}
+	}
+	return segments, nil
+}
+
+func (p *parser) parseDotChild() (*childSegment, error) {
+	if p.peek() == '*' {
+		p.pos++
+		return &childSegment{selectors: []selector{wildcardSelector{}}}, nil
+	}
+	name := p.parseName()
+	if name == "" {
+		return nil, errors.Errorf("unexpected character %q after dot", p.peek())
+	}
+	return &childSegment{selectors: []selector{nameSelector(name)}}, nil
+}
+
+func (p *parser) parseName() string {
+	start := p.pos
+	for !p.done() {
+		r := p.peek()
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+			break
+		}
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+func (p *parser) parseBracket() (*childSegment, error) {
+	p.pos++ // [
+	segment := &childSegment{}
+	for {
+		p.skipSpaces()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		segment.selectors = append(segment.selectors, sel)
+		p.skipSpaces()
+		if p.peek() == ',' {
+			p.pos++
+			continue
+		}
+		if err := p.expect(']'); err != nil {
+			return nil, err
+		}
+		return segment, nil
+	}
+}
+
+func (p *parser) parseSelector() (selector, error) {
+	switch r := p.peek(); {
+	case r == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case r == '\'' || r == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector(s), nil
+	case r == '?':
+		p.pos++
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return &filterSelector{expr: expr}, nil
+	case r == '-' || r == ':' || unicode.IsDigit(r):
+		return p.parseIndexOrSlice()
+	}
+	if p.done() {
+		return nil, errors.New("bracket is not closed")
+	}
+	return nil, errors.Errorf("unexpected character %q in bracket", p.peek())
+}
+
+func (p *parser) parseIndexOrSlice() (selector, error) {
+	var numbers [3]*int
+	part := 0
+	for {
+		p.skipSpaces()
+		if r := p.peek(); r == '-' || unicode.IsDigit(r) {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			numbers[part] = &n
+			p.skipSpaces()
+		}
+		if p.peek() != ':' {
+			break
+		}
+		if part == 2 {
+			return nil, errors.New("too many colons in slice")
+		}
+		p.pos++
+		part++
+	}
+	if part == 0 {
+		if numbers[0] == nil {
+			return nil, errors.New("index is empty")
+		}
+		return indexSelector(*numbers[0]), nil
+	}
+	step := 1
+	if numbers[2] != nil {
+		step = *numbers[2]
+	}
+	if step == 0 {
+		return nil, errors.New("slice step should not be 0")
+	}
+	return &sliceSelector{start: numbers[0], end: numbers[1], step: step}, nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for !p.done() && unicode.IsDigit(p.peek()) {
+		p.pos++
+	}
+	n, err := strconv.Atoi(string(p.input[start:p.pos]))
+	if err != nil {
+		return 0, errors.Errorf("invalid integer %q", string(p.input[start:p.pos]))
+	}
+	return n, nil
+}
+
+func (p *parser) parseString() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for !p.done() {
+		r := p.peek()
+		p.pos++
+		switch r {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.done() {
+				return "", errors.New("unclosed string")
+			}
+			b.WriteRune(p.peek())
+			p.pos++
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", errors.New("unclosed string")
+}
+
+func (p *parser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.hasPrefix("||") {
+			return left, nil
+		}
+		p.pos += 2
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.hasPrefix("&&") {
+			return left, nil
+		}
+		p.pos += 2
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (filterExpr, error) {
+	p.skipSpaces()
+	switch {
+	case p.peek() == '!' && !p.hasPrefix("!="):
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	case p.peek() == '(':
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+var comparisonOperators = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+func (p *parser) parseComparison() (filterExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range comparisonOperators {
+		if !p.hasPrefix(op) {
+			continue
+		}
+		p.pos += len(op)
+		p.skipSpaces()
+		if op == "=~" {
+			pattern, err := p.parseRegexp()
+			if err != nil {
+				return nil, err
+			}
+			return &comparisonExpr{op: op, left: left, regexp: pattern}, nil
+		}
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonExpr{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// parseRegexp parses /pattern/flags or quoted pattern, only i flag is supported
+func (p *parser) parseRegexp() (*regexp.Regexp, error) {
+	var pattern string
+	switch p.peek() {
+	case '\'', '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		pattern = s
+	case '/':
+		p.pos++
+		var b strings.Builder
+		for {
+			if p.done() {
+				return nil, errors.New("unclosed regexp")
+			}
+			r := p.peek()
+			p.pos++
+			if r == '/' {
+				break
+			}
+			if r == '\\' && p.peek() == '/' {
+				r = '/'
+				p.pos++
+			}
+			b.WriteRune(r)
+		}
+		pattern = b.String()
+		if p.peek() == 'i' {
+			p.pos++
+			pattern = "(?i)" + pattern
+		}
+	default:
+		return nil, errors.New("regexp should be enclosed in slashes or quotes")
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid regexp")
+	}
+	return compiled, nil
+}
+
+func (p *parser) parseOperand() (filterExpr, error) {
+	p.skipSpaces()
+	r := p.peek()
+	switch {
+	case r == '@' || r == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &pathOperand{absolute: r == '$', segments: segments}, nil
+	case r == '\'' || r == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &literalOperand{value: s}, nil
+	case r == '-' || unicode.IsDigit(r):
+		start := p.pos
+		p.pos++
+		for !p.done() && (unicode.IsDigit(p.peek()) || strings.ContainsRune(".eE+-", p.peek())) {
+			p.pos++
+		}
+		number, err := strconv.ParseFloat(string(p.input[start:p.pos]), 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid number %q", string(p.input[start:p.pos]))
+		}
+		return &literalOperand{value: number}, nil
+	}
+	for _, literal := range []struct {
+		name  string
+		value interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if p.hasPrefix(literal.name) {
+			p.pos += len(literal.name)
+			return &literalOperand{value: literal.value}, nil
+		}
+	}
+	if p.done() {
+		return nil, errors.New("filter is not closed")
+	}
+	return nil, errors.New(fmt.Sprintf("unexpected character %q in filter", r))
+}
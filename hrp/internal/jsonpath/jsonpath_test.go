@@ -0,0 +1,136 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testStore = `{
+    "store": {
+        "book": [
+            {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+            {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+            {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+            {"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+        ],
+        "bicycle": {"color": "red", "price": 19.95, "sold-out": false}
+    },
+    "expensive": 10
+}`
+
+func loadTestStore(t *testing.T) interface{} {
+	var data interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(testStore)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSearch(t *testing.T) {
+	data := loadTestStore(t)
+	testData := []struct {
+		expr     string
+		expected []interface{}
+	}{
+		{"$.store.book[0].author", []interface{}{"Nigel Rees"}},
+		{"$['store']['bicycle']['color']", []interface{}{"red"}},
+		{"$.store.bicycle.sold-out", []interface{}{false}},
+		{"$.store.book[-1].title", []interface{}{"The Lord of the Rings"}},
+		{"$.store.book[*].author", []interface{}{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"$..author", []interface{}{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"$.store.*.color", []interface{}{"red"}},
+		{"$..book[0,1].title", []interface{}{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[:2].price", []interface{}{json.Number("8.95"), json.Number("12.99")}},
+		{"$..book[2:].author", []interface{}{"Herman Melville", "J. R. R. Tolkien"}},
+		{"$..book[::-2].author", []interface{}{"J. R. R. Tolkien", "Evelyn Waugh"}},
+		{"$..book[?(@.isbn)].title", []interface{}{"Moby Dick", "The Lord of the Rings"}},
+		{"$..book[?(!@.isbn)].title", []interface{}{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[?(@.price < 10)].title", []interface{}{"Sayings of the Century", "Moby Dick"}},
+		{"$..book[?(@.price > $.expensive && @.category == 'fiction')].title", []interface{}{"Sword of Honour", "The Lord of the Rings"}},
+		{"$..book[?(@.author =~ /^h/i || @.price >= 22.99)].price", []interface{}{json.Number("8.99"), json.Number("22.99")}},
+		{"$..book[?(@.category != 'fiction')].author", []interface{}{"Nigel Rees"}},
+		{"$.store.book[10]", nil},
+		{"$.store.unknown", nil},
+		{"$", []interface{}{data}},
+	}
+	for _, d := range testData {
+		values, err := Search(d.expr, data)
+		if !assert.Nil(t, err, d.expr) {
+			t.Fail()
+		}
+		if !assert.Equal(t, d.expected, values, d.expr) {
+			t.Fail()
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	data := loadTestStore(t)
+	for _, expr := range []string{"$.store.book[?(@.isbn)].author", "$..color", "$.store.book[0:1]", "$"} {
+		path, err := Compile(expr)
+		if !assert.NoError(t, err, expr) {
+			t.Fatal()
+		}
+		path.Replace(data, func(value interface{}) interface{} {
+			return "***"
+		})
+	}
+	// the first book is replaced entirely
+	if !assert.Equal(t, []interface{}{"Evelyn Waugh", "***", "***"}, mustSearch(t, "$.store.book[*].author", data)) {
+		t.Fail()
+	}
+	if !assert.Equal(t, []interface{}{"***"}, mustSearch(t, "$.store.book[0]", data)) {
+		t.Fail()
+	}
+	if !assert.Equal(t, []interface{}{"***"}, mustSearch(t, "$.store.bicycle.color", data)) {
+		t.Fail()
+	}
+}
+
+func mustSearch(t *testing.T, expr string, data interface{}) []interface{} {
+	values, err := Search(expr, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func TestDefinite(t *testing.T) {
+	testData := []struct {
+		expr     string
+		definite bool
+	}{
+		{"$.store.book[0].author", true},
+		{"$['store'].bicycle", true},
+		{"$.store.book[*]", false},
+		{"$..author", false},
+		{"$.store.book[0,1]", false},
+		{"$.store.book[:1]", false},
+		{"$.store.book[?(@.isbn)]", false},
+	}
+	for _, d := range testData {
+		path, err := Compile(d.expr)
+		if !assert.Nil(t, err, d.expr) {
+			t.Fail()
+		}
+		if !assert.Equal(t, d.definite, path.Definite(), d.expr) {
+			t.Fail()
+		}
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"store.book", "$.", "$[", "$.store[0", "$['store]", "$[1:2:3:4]", "$[::0]",
+		"$[?(@.price <)]", "$[?(@.a =~ abc)]", "$[?(@.a =~ /[/)]", "$.a b",
+	} {
+		if _, err := Compile(expr); !assert.Error(t, err, expr) {
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,544 @@
+package jsonschema
+
+import (
+	builtinJSON "encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+JSON Schema validation for draft-07 and OpenAPI 3.0 schema object,
+only local $ref is supported, which is resolved against root schema with JSON pointer.
+All violations are collected instead of failing fast.
+*/
+
+// maxRefDepth limits nested $ref resolving, avoid infinite loop for invalid recursive schemas
+const maxRefDepth = 64
+
+// Violation represents one schema violation of instance
+type Violation struct {
+	Path    string      `json:"path"`    // JSON pointer of invalid value in instance, e.g. /body/items/0/id
+	Keyword string      `json:"keyword"` // failed schema keyword, e.g. type, required
+	Message string      `json:"message"`
+	Value   interface{} `json:"value"`
+}
+
+func (v *Violation) Error() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// Validate validates instance against schema, $ref is resolved against schema itself
+func Validate(schema interface{}, instance interface{}) []*Violation {
+	return ValidateWithRoot(schema, instance, schema, "")
+}
+
+// ValidateWithRoot validates instance against schema, $ref is resolved against root document,
+// violation paths are prefixed with basePath, e.g. /body
+func ValidateWithRoot(schema interface{}, instance interface{}, root interface{}, basePath string) []*Violation {
+	v := &validator{root: root}
+	v.validate(schema, instance, basePath, 0)
+	return v.violations
+}
+
+type validator struct {
+	root       interface{}
+	violations []*Violation
+}
+
+func (v *validator) addViolation(path, keyword string, value interface{}, format string, args ...interface{}) {
+	v.violations = append(v.violations, &Violation{
+		Path:    path,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+		Value:   value,
+	})
+}
+
+// sub validates instance with a new validator, used for anyOf/oneOf/not which should not report directly
+func (v *validator) sub(schema interface{}, instance interface{}, path string, depth int) []*Violation {
+	s := &validator{root: v.root}
+	s.validate(schema, instance, path, depth)
+	return s.violations
+}
+
+func (v *validator) validate(schema interface{}, instance interface{}, path string, depth int) {
+	// boolean schema
+	if b, ok := schema.(bool); ok {
+		if !b {
+			v.addViolation(path, "false", instance, "no value is allowed")
+		}
+		return
+	}
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		if depth > maxRefDepth {
+			v.addViolation(path, "$ref", instance, "too many nested $ref: %s", ref)
+			return
+		}
+		resolved, err := ResolvePointer(v.root, ref)
+		if err != nil {
+			v.addViolation(path, "$ref", instance, "%v", err)
+			return
+		}
+		// siblings of $ref are ignored like draft-07
+		v.validate(resolved, instance, path, depth+1)
+		return
+	}
+
+	if instance == nil && s["nullable"] == true {
+		return
+	}
+	if !v.validateType(s, instance, path) {
+		// skip other keywords if type mismatched, avoid confusing violations
+		return
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		matched := false
+		for _, e := range enum {
+			if equal(e, instance) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addViolation(path, "enum", instance, "value %v is not one of %v", format(instance), enum)
+		}
+	}
+	if c, ok := s["const"]; ok && !equal(c, instance) {
+		v.addViolation(path, "const", instance, "value %v does not equal to const %v", format(instance), c)
+	}
+
+	switch value := instance.(type) {
+	case string:
+		v.validateString(s, value, path)
+	case map[string]interface{}:
+		v.validateObject(s, value, path, depth)
+	case []interface{}:
+		v.validateArray(s, value, path, depth)
+	default:
+		if number, ok := toFloat(instance); ok {
+			v.validateNumber(s, number, instance, path)
+		}
+	}
+
+	v.validateCombinations(s, instance, path, depth)
+}
+
+func (v *validator) validateType(s map[string]interface{}, instance interface{}, path string) bool {
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			types = append(types, fmt.Sprint(item))
+		}
+	default:
+		return true
+	}
+	actual := typeOf(instance)
+	for _, expected := range types {
+		if expected == actual || (expected == "number" && actual == "integer") {
+			return true
+		}
+	}
+	v.addViolation(path, "type", instance, "expected type %s, got %s", strings.Join(types, " or "), actual)
+	return false
+}
+
+func (v *validator) validateString(s map[string]interface{}, value string, path string) {
+	length := utf8.RuneCountInString(value)
+	if min, ok := toInt(s["minLength"]); ok && length < min {
+		v.addViolation(path, "minLength", value, "string length %d is less than minLength %d", length, min)
+	}
+	if max, ok := toInt(s["maxLength"]); ok && length > max {
+		v.addViolation(path, "maxLength", value, "string length %d is greater than maxLength %d", length, max)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.addViolation(path, "pattern", value, "invalid pattern %q: %v", pattern, err)
+		} else if !re.MatchString(value) {
+			v.addViolation(path, "pattern", value, "string %q does not match pattern %q", value, pattern)
+		}
+	}
+	if f, ok := s["format"].(string); ok {
+		if check, ok := formatCheckers[f]; ok && !check(value) {
+			v.addViolation(path, "format", value, "string %q is not valid %s", value, f)
+		}
+	}
+}
+
+func (v *validator) validateNumber(s map[string]interface{}, number float64, instance interface{}, path string) {
+	if min, ok := toFloat(s["minimum"]); ok {
+		// exclusiveMinimum is boolean in OpenAPI 3.0 and draft-04
+		if s["exclusiveMinimum"] == true && number <= min {
+			v.addViolation(path, "exclusiveMinimum", instance, "value %v is not greater than %v", format(instance), min)
+		} else if number < min {
+			v.addViolation(path, "minimum", instance, "value %v is less than minimum %v", format(instance), min)
+		}
+	}
+	if max, ok := toFloat(s["maximum"]); ok {
+		if s["exclusiveMaximum"] == true && number >= max {
+			v.addViolation(path, "exclusiveMaximum", instance, "value %v is not less than %v", format(instance), max)
+		} else if number > max {
+			v.addViolation(path, "maximum", instance, "value %v is greater than maximum %v", format(instance), max)
+		}
+	}
+	// exclusiveMinimum/exclusiveMaximum are numbers since draft-06
+	if min, ok := toFloat(s["exclusiveMinimum"]); ok && number <= min {
+		v.addViolation(path, "exclusiveMinimum", instance, "value %v is not greater than %v", format(instance), min)
+	}
+	if max, ok := toFloat(s["exclusiveMaximum"]); ok && number >= max {
+		v.addViolation(path, "exclusiveMaximum", instance, "value %v is not less than %v", format(instance), max)
+	}
+	if multiple, ok := toFloat(s["multipleOf"]); ok && multiple > 0 {
+		quotient := number / multiple
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			v.addViolation(path, "multipleOf", instance, "value %v is not multiple of %v", format(instance), multiple)
+		}
+	}
+}
+
+func (v *validator) validateObject(s map[string]interface{}, value map[string]interface{}, path string, depth int) {
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := value[fmt.Sprint(name)]; !ok {
+				v.addViolation(joinPointer(path, fmt.Sprint(name)), "required", nil,
+					"required property %q is missing", name)
+			}
+		}
+	}
+	if min, ok := toInt(s["minProperties"]); ok && len(value) < min {
+		v.addViolation(path, "minProperties", value, "object has %d properties, less than minProperties %d", len(value), min)
+	}
+	if max, ok := toInt(s["maxProperties"]); ok && len(value) > max {
+		v.addViolation(path, "maxProperties", value, "object has %d properties, greater than maxProperties %d", len(value), max)
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	patternProperties, _ := s["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := s["additionalProperties"]
+
+	// sort keys to keep violations stable
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		propPath := joinPointer(path, key)
+		matched := false
+		if propSchema, ok := properties[key]; ok {
+			v.validate(propSchema, value[key], propPath, depth)
+			matched = true
+		}
+		for pattern, propSchema := range patternProperties {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+				v.validate(propSchema, value[key], propPath, depth)
+				matched = true
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if additional == false {
+			v.addViolation(propPath, "additionalProperties", value[key], "additional property %q is not allowed", key)
+		} else {
+			v.validate(additional, value[key], propPath, depth)
+		}
+	}
+}
+
+func (v *validator) validateArray(s map[string]interface{}, value []interface{}, path string, depth int) {
+	if min, ok := toInt(s["minItems"]); ok && len(value) < min {
+		v.addViolation(path, "minItems", value, "array has %d items, less than minItems %d", len(value), min)
+	}
+	if max, ok := toInt(s["maxItems"]); ok && len(value) > max {
+		v.addViolation(path, "maxItems", value, "array has %d items, greater than maxItems %d", len(value), max)
+	}
+	if s["uniqueItems"] == true {
+	outer:
+		for i := 0; i < len(value); i++ {
+			for j := i + 1; j < len(value); j++ {
+				if equal(value[i], value[j]) {
+					v.addViolation(path, "uniqueItems", value, "items at index %d and %d are equal", i, j)
+					break outer
+				}
+			}
+		}
+	}
+
+	switch items := s["items"].(type) {
+	case []interface{}:
+		// tuple validation
+		for i, item := range value {
+			itemPath := joinPointer(path, fmt.Sprint(i))
+			if i < len(items) {
+				v.validate(items[i], item, itemPath, depth)
+			} else if additional, ok := s["additionalItems"]; ok {
+				if additional == false {
+					v.addViolation(itemPath, "additionalItems", item, "additional item is not allowed")
+				} else {
+					v.validate(additional, item, itemPath, depth)
+				}
+			}
+		}
+	case nil:
+	default:
+		for i, item := range value {
+			v.validate(items, item, joinPointer(path, fmt.Sprint(i)), depth)
+		}
+	}
+
+	if contains, ok := s["contains"]; ok {
+		found := false
+		for _, item := range value {
+			if len(v.sub(contains, item, path, depth)) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.addViolation(path, "contains", value, "no item matches contains schema")
+		}
+	}
+}
+
+func (v *validator) validateCombinations(s map[string]interface{}, instance interface{}, path string, depth int) {
+	if allOf, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			v.validate(sub, instance, path, depth)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		matched := false
+		var first []*Violation
+		for i, sub := range anyOf {
+			violations := v.sub(sub, instance, path, depth)
+			if len(violations) == 0 {
+				matched = true
+				break
+			}
+			if i == 0 {
+				first = violations
+			}
+		}
+		if !matched {
+			v.addViolation(path, "anyOf", instance, "value does not match any schema of anyOf%s", hint(first))
+		}
+	}
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		count := 0
+		var first []*Violation
+		for i, sub := range oneOf {
+			violations := v.sub(sub, instance, path, depth)
+			if len(violations) == 0 {
+				count++
+			} else if i == 0 {
+				first = violations
+			}
+		}
+		if count == 0 {
+			v.addViolation(path, "oneOf", instance, "value does not match any schema of oneOf%s", hint(first))
+		} else if count > 1 {
+			v.addViolation(path, "oneOf", instance, "value matches %d schemas of oneOf, expect exactly one", count)
+		}
+	}
+	if not, ok := s["not"]; ok {
+		if len(v.sub(not, instance, path, depth)) == 0 {
+			v.addViolation(path, "not", instance, "value should not match schema of not")
+		}
+	}
+}
+
+// hint returns the first violation of sub schema, help locating the mismatch
+func hint(violations []*Violation) string {
+	if len(violations) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", e.g. %s", violations[0].Error())
+}
+
+// formatCheckers validates well-known string formats, unknown formats are ignored
+var formatCheckers = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	},
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uuid": regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && strings.Contains(s, ".")
+	},
+	"ipv6": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+	"hostname": regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*$`).MatchString,
+}
+
+// typeOf returns json type of value, integer is returned for numbers without fraction
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if number, ok := toFloat(value); ok {
+		if number == math.Trunc(number) && !math.IsInf(number, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return reflect.TypeOf(value).String()
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case builtinJSON.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func toInt(value interface{}) (int, bool) {
+	f, ok := toFloat(value)
+	return int(f), ok
+}
+
+// equal compares json values, numbers are compared by value regardless of their go types
+func equal(a, b interface{}) bool {
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	if okA && okB {
+		return fa == fb
+	}
+	switch va := a.(type) {
+	case map[string]interface{}:
+		vb, ok := b.(map[string]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for key, value := range va {
+			if other, ok := vb[key]; !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for i := range va {
+			if !equal(va[i], vb[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// format formats value for message, strings are quoted
+func format(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(value)
+}
+
+// joinPointer appends escaped token to JSON pointer
+func joinPointer(path, token string) string {
+	token = strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+	return path + "/" + token
+}
+
+// ResolvePointer resolves local reference with JSON pointer in root document, e.g. #/definitions/item
+func ResolvePointer(root interface{}, ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only local $ref is supported, got %q", ref)
+	}
+	current := root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return current, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token, _ = url.PathUnescape(token)
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid $ref %q", ref)
+		}
+		if current, ok = m[token]; !ok {
+			return nil, fmt.Errorf("$ref %q not found", ref)
+		}
+	}
+	return current, nil
+}
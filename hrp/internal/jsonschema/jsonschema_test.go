@@ -0,0 +1,137 @@
+package jsonschema
+
+import (
+	builtinJSON "encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"id", "name", "tags"},
+		"properties": map[string]interface{}{
+			"id":    map[string]interface{}{"type": "integer", "minimum": 1},
+			"name":  map[string]interface{}{"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"email": map[string]interface{}{"type": "string", "format": "email"},
+			"tags": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"$ref": "#/definitions/tag"},
+				"uniqueItems": true,
+			},
+			"status": map[string]interface{}{"enum": []interface{}{"on", "off"}},
+			"parent": map[string]interface{}{"type": "integer", "nullable": true},
+		},
+		"additionalProperties": false,
+		"definitions": map[string]interface{}{
+			"tag": map[string]interface{}{"type": "string", "maxLength": 3},
+		},
+	}
+
+	valid := map[string]interface{}{
+		"id":     builtinJSON.Number("12"),
+		"name":   "abc",
+		"email":  "a@b.com",
+		"tags":   []interface{}{"a", "b"},
+		"status": "on",
+		"parent": nil,
+	}
+	if violations := Validate(schema, valid); !assert.Empty(t, violations) {
+		t.Fail()
+	}
+
+	invalid := map[string]interface{}{
+		"id":     1.5,
+		"name":   "A",
+		"email":  "not-an-email",
+		"tags":   []interface{}{"abcd", "x", "x"},
+		"status": "unknown",
+		"extra":  true,
+	}
+	violations := Validate(schema, invalid)
+	var paths []string
+	for _, violation := range violations {
+		paths = append(paths, violation.Path+" "+violation.Keyword)
+	}
+	expected := []string{
+		"/email format",
+		"/extra additionalProperties",
+		"/id type",
+		"/name minLength",
+		"/name pattern",
+		"/status enum",
+		"/tags uniqueItems",
+		"/tags/0 maxLength",
+	}
+	if !assert.Equal(t, expected, paths) {
+		t.Fail()
+	}
+}
+
+func TestValidateCombinations(t *testing.T) {
+	schema := map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "integer"},
+		},
+		"not": map[string]interface{}{"const": "forbidden"},
+	}
+	if violations := Validate(schema, "ok"); !assert.Empty(t, violations) {
+		t.Fail()
+	}
+	if violations := Validate(schema, 3); !assert.Empty(t, violations) {
+		t.Fail()
+	}
+	violations := Validate(schema, true)
+	if !assert.Len(t, violations, 1) || !assert.Equal(t, "anyOf", violations[0].Keyword) {
+		t.Fail()
+	}
+	violations = Validate(schema, "forbidden")
+	if !assert.Len(t, violations, 1) || !assert.Equal(t, "not", violations[0].Keyword) {
+		t.Fail()
+	}
+
+	oneOf := map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "number"},
+			map[string]interface{}{"type": "integer"},
+		},
+	}
+	if violations := Validate(oneOf, 1.5); !assert.Empty(t, violations) {
+		t.Fail()
+	}
+	violations = Validate(oneOf, 1)
+	if !assert.Len(t, violations, 1) || !assert.Equal(t, "oneOf", violations[0].Keyword) {
+		t.Fail()
+	}
+}
+
+func TestResolvePointer(t *testing.T) {
+	root := map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"a/b": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+	value, err := ResolvePointer(root, "#/components/schemas/a~1b")
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{"type": "string"}, value) {
+		t.Fail()
+	}
+	if _, err := ResolvePointer(root, "#/components/schemas/c"); !assert.Error(t, err) {
+		t.Fail()
+	}
+	if _, err := ResolvePointer(root, "other.yaml#/components"); !assert.Error(t, err) {
+		t.Fail()
+	}
+
+	// unresolved $ref is reported as violation
+	violations := ValidateWithRoot(map[string]interface{}{"$ref": "#/missing"}, 1, root, "/body")
+	if !assert.Len(t, violations, 1) || !assert.Equal(t, "/body", violations[0].Path) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"sort"
+
+	"github.com/httprunner/httprunner/hrp/internal/jsonschema"
+)
+
+// maxExampleDepth limits nested levels of generated example, avoid infinite loop for recursive schemas
+const maxExampleDepth = 8
+
+// GenExample generates example value from schema,
+// explicit example, default and enum values are preferred.
+func (d *Document) GenExample(schema map[string]interface{}) interface{} {
+	return d.genExample(schema, 0)
+}
+
+func (d *Document) genExample(schema map[string]interface{}, depth int) interface{} {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := jsonschema.ResolvePointer(d.raw, ref)
+		if err != nil {
+			return nil
+		}
+		s, _ := resolved.(map[string]interface{})
+		return d.genExample(s, depth+1)
+	}
+	for _, key := range []string{"example", "default"} {
+		if value, ok := schema[key]; ok {
+			return value
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		// merge examples of all sub schemas
+		merged := make(map[string]interface{})
+		for _, sub := range allOf {
+			s, _ := sub.(map[string]interface{})
+			if example, ok := d.genExample(s, depth+1).(map[string]interface{}); ok {
+				for key, value := range example {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if subs, ok := schema[key].([]interface{}); ok && len(subs) > 0 {
+			s, _ := subs[0].(map[string]interface{})
+			return d.genExample(s, depth+1)
+		}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	if schemaType == "" {
+		if _, ok := schema["properties"]; ok {
+			schemaType = "object"
+		} else if _, ok := schema["items"]; ok {
+			schemaType = "array"
+		}
+	}
+	switch schemaType {
+	case "object":
+		example := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		var names []string
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			s, _ := properties[name].(map[string]interface{})
+			if s != nil && (s["readOnly"] == true) {
+				continue
+			}
+			example[name] = d.genExample(s, depth+1)
+		}
+		return example
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		if item := d.genExample(items, depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "integer":
+		if minimum, ok := schema["minimum"]; ok {
+			return minimum
+		}
+		return 0
+	case "number":
+		if minimum, ok := schema["minimum"]; ok {
+			return minimum
+		}
+		return 0.0
+	case "boolean":
+		return false
+	case "string":
+		return stringExample(schema)
+	default:
+		return nil
+	}
+}
+
+// formatExamples holds example values for well-known string formats
+var formatExamples = map[string]string{
+	"date":      "2022-01-01",
+	"date-time": "2022-01-01T00:00:00Z",
+	"email":     "user@example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"uri":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "127.0.0.1",
+	"ipv6":      "::1",
+	"byte":      "c3RyaW5n",
+}
+
+func stringExample(schema map[string]interface{}) string {
+	format, _ := schema["format"].(string)
+	if example, ok := formatExamples[format]; ok {
+		return example
+	}
+	return "string"
+}
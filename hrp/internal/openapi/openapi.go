@@ -0,0 +1,292 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/jsonschema"
+)
+
+/*
+OpenAPI Specification 3.0
+https://spec.openapis.org/oas/v3.0.3
+only the fields used in conversion and validation are defined here,
+schemas are kept as raw maps and $ref is resolved against the whole document.
+*/
+
+// Document is the root of OpenAPI 3 document
+type Document struct {
+	OpenAPI string               `json:"openapi" yaml:"openapi"`
+	Info    Info                 `json:"info" yaml:"info"`
+	Servers []Server             `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths   map[string]*PathItem `json:"paths" yaml:"paths"`
+	Tags    []Tag                `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	raw map[string]interface{} // whole document for resolving $ref
+}
+
+type Info struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+type Server struct {
+	URL string `json:"url" yaml:"url"`
+}
+
+type Tag struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// PathItem holds operations for one path
+type PathItem struct {
+	Ref        string       `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Get        *Operation   `json:"get,omitempty" yaml:"get,omitempty"`
+	Put        *Operation   `json:"put,omitempty" yaml:"put,omitempty"`
+	Post       *Operation   `json:"post,omitempty" yaml:"post,omitempty"`
+	Delete     *Operation   `json:"delete,omitempty" yaml:"delete,omitempty"`
+	Options    *Operation   `json:"options,omitempty" yaml:"options,omitempty"`
+	Head       *Operation   `json:"head,omitempty" yaml:"head,omitempty"`
+	Patch      *Operation   `json:"patch,omitempty" yaml:"patch,omitempty"`
+	Trace      *Operation   `json:"trace,omitempty" yaml:"trace,omitempty"`
+	Parameters []*Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses,omitempty" yaml:"responses,omitempty"`
+}
+
+type Parameter struct {
+	Ref      string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name     string                 `json:"name" yaml:"name"`
+	In       string                 `json:"in" yaml:"in"` // path, query, header or cookie
+	Required bool                   `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   map[string]interface{} `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example  interface{}            `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+type RequestBody struct {
+	Ref      string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Required bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type Header struct {
+	Ref      string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Required bool                   `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   map[string]interface{} `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+type MediaType struct {
+	Schema   map[string]interface{} `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example  interface{}            `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples map[string]*Example    `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+type Example struct {
+	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// MethodOperation is operation with its http method and path
+type MethodOperation struct {
+	Method    string
+	Path      string
+	Operation *Operation
+	PathItem  *PathItem
+}
+
+// Load loads OpenAPI 3 document in json or yaml format
+func Load(path string) (*Document, error) {
+	log.Info().Str("path", path).Msg("load openapi document")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read openapi document failed")
+	}
+
+	doc := &Document{}
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(content, doc)
+		if err == nil {
+			err = json.Unmarshal(content, &doc.raw)
+		}
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, doc)
+		if err == nil {
+			err = yaml.Unmarshal(content, &doc.raw)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported openapi document format: %s", path)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal openapi document failed")
+	}
+	normalize(doc.raw)
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("only openapi 3.x is supported, got %q", doc.OpenAPI)
+	}
+	return doc, nil
+}
+
+// Root returns the whole document, used for resolving $ref in schemas
+func (d *Document) Root() map[string]interface{} {
+	return d.raw
+}
+
+// Operations returns all operations sorted by path and method
+func (d *Document) Operations() []*MethodOperation {
+	var paths []string
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var operations []*MethodOperation
+	for _, path := range paths {
+		item := d.Paths[path]
+		if item.Ref != "" {
+			resolved := &PathItem{}
+			if err := d.Resolve(item.Ref, resolved); err != nil {
+				log.Warn().Err(err).Str("path", path).Msg("resolve path item failed")
+				continue
+			}
+			item = resolved
+		}
+		for _, mo := range []struct {
+			method    string
+			operation *Operation
+		}{
+			{"GET", item.Get}, {"POST", item.Post}, {"PUT", item.Put}, {"PATCH", item.Patch},
+			{"DELETE", item.Delete}, {"HEAD", item.Head}, {"OPTIONS", item.Options}, {"TRACE", item.Trace},
+		} {
+			if mo.operation == nil {
+				continue
+			}
+			operations = append(operations, &MethodOperation{
+				Method:    mo.method,
+				Path:      path,
+				Operation: mo.operation,
+				PathItem:  item,
+			})
+		}
+	}
+	return operations
+}
+
+// Parameters returns resolved parameters of operation, including parameters of path item
+func (d *Document) Parameters(mo *MethodOperation) []*Parameter {
+	var parameters []*Parameter
+	index := make(map[string]int)
+	for _, param := range append(append([]*Parameter{}, mo.PathItem.Parameters...), mo.Operation.Parameters...) {
+		if param.Ref != "" {
+			resolved := &Parameter{}
+			if err := d.Resolve(param.Ref, resolved); err != nil {
+				log.Warn().Err(err).Str("ref", param.Ref).Msg("resolve parameter failed")
+				continue
+			}
+			param = resolved
+		}
+		// operation parameters override path item parameters with the same name and location
+		key := param.In + ":" + param.Name
+		if i, ok := index[key]; ok {
+			parameters[i] = param
+			continue
+		}
+		index[key] = len(parameters)
+		parameters = append(parameters, param)
+	}
+	return parameters
+}
+
+// RequestBody returns resolved request body of operation, nil if not defined
+func (d *Document) RequestBody(mo *MethodOperation) *RequestBody {
+	body := mo.Operation.RequestBody
+	if body != nil && body.Ref != "" {
+		resolved := &RequestBody{}
+		if err := d.Resolve(body.Ref, resolved); err != nil {
+			log.Warn().Err(err).Str("ref", body.Ref).Msg("resolve request body failed")
+			return nil
+		}
+		body = resolved
+	}
+	return body
+}
+
+// Response returns resolved response of operation by status code,
+// fallback to range code (e.g. 2XX) and default response.
+func (d *Document) Response(mo *MethodOperation, statusCode int) (*Response, bool) {
+	code := fmt.Sprint(statusCode)
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		resp, ok := mo.Operation.Responses[key]
+		if !ok {
+			continue
+		}
+		if resp != nil && resp.Ref != "" {
+			resolved := &Response{}
+			if err := d.Resolve(resp.Ref, resolved); err != nil {
+				log.Warn().Err(err).Str("ref", resp.Ref).Msg("resolve response failed")
+				return nil, false
+			}
+			resp = resolved
+		}
+		return resp, true
+	}
+	return nil, false
+}
+
+// Resolve resolves local $ref and decodes referenced object into out, e.g. #/components/parameters/id
+func (d *Document) Resolve(ref string, out interface{}) error {
+	value, err := jsonschema.ResolvePointer(d.raw, ref)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(normalize(value))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, out)
+}
+
+// normalize converts map[interface{}]interface{} decoded from yaml to map[string]interface{}
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = normalize(val)
+		}
+		return m
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = normalize(val)
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var petstorePath = "../../../examples/data/openapi/petstore.yaml"
+
+func TestLoad(t *testing.T) {
+	doc, err := Load(petstorePath)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, "https://petstore.example.com/v1", doc.Servers[0].URL) {
+		t.Fail()
+	}
+
+	operations := doc.Operations()
+	var names []string
+	for _, mo := range operations {
+		names = append(names, mo.Method+" "+mo.Path)
+	}
+	expected := []string{
+		"GET /health", "GET /pets", "POST /pets", "GET /pets/{petId}",
+		"GET /store/inventory", "POST /store/order",
+	}
+	if !assert.Equal(t, expected, names) {
+		t.Fail()
+	}
+
+	// parameters of path item and $ref parameters are resolved
+	params := doc.Parameters(operations[1])
+	if !assert.Len(t, params, 2) {
+		t.FailNow()
+	}
+	if !assert.Equal(t, "X-Request-ID", params[1].Name) || !assert.Equal(t, "header", params[1].In) {
+		t.Fail()
+	}
+	params = doc.Parameters(operations[3])
+	if !assert.Len(t, params, 1) || !assert.Equal(t, "petId", params[0].Name) {
+		t.Fail()
+	}
+
+	// response is matched by status code, then default response
+	resp, ok := doc.Response(operations[1], 200)
+	if !assert.True(t, ok) || !assert.Contains(t, resp.Headers, "x-next") {
+		t.Fail()
+	}
+	resp, ok = doc.Response(operations[1], 500)
+	if !assert.True(t, ok) || !assert.Equal(t, "unexpected error", resp.Description) {
+		t.Fail()
+	}
+	if _, ok = doc.Response(operations[0], 500); !assert.False(t, ok) {
+		t.Fail()
+	}
+
+	if _, err := Load("../../../examples/data/curl/demo.curl"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestGenExample(t *testing.T) {
+	doc, err := Load(petstorePath)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	testData := []struct {
+		schema   map[string]interface{}
+		expected interface{}
+	}{
+		{map[string]interface{}{"type": "string"}, "string"},
+		{map[string]interface{}{"type": "string", "format": "date-time"}, "2022-01-01T00:00:00Z"},
+		{map[string]interface{}{"type": "integer", "minimum": 3}, 3},
+		{map[string]interface{}{"type": "boolean", "default": true}, true},
+		{map[string]interface{}{"enum": []interface{}{"a", "b"}}, "a"},
+		{
+			map[string]interface{}{"$ref": "#/components/schemas/Pets"},
+			[]interface{}{map[string]interface{}{"name": "doggie", "tag": "string", "status": "available"}},
+		},
+		{
+			map[string]interface{}{"allOf": []interface{}{
+				map[string]interface{}{"$ref": "#/components/schemas/Error"},
+				map[string]interface{}{"properties": map[string]interface{}{"detail": map[string]interface{}{"type": "string"}}},
+			}},
+			map[string]interface{}{"code": 0, "message": "string", "detail": "string"},
+		},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, doc.GenExample(data.schema)) {
+			t.Fail()
+		}
+	}
+}
+
+func TestFindOperation(t *testing.T) {
+	doc, err := Load(petstorePath)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	testData := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"GET", "/v1/pets", "/pets"},
+		{"get", "/pets/123", "/pets/{petId}"},
+		{"POST", "/v1/store/order", "/store/order"},
+		{"DELETE", "/v1/pets/123", ""},
+		{"GET", "/v1/pets/123/photos", ""},
+	}
+	for _, data := range testData {
+		mo := doc.FindOperation(data.method, data.path)
+		if data.expected == "" {
+			if !assert.Nil(t, mo) {
+				t.Fail()
+			}
+			continue
+		}
+		if !assert.NotNil(t, mo) || !assert.Equal(t, data.expected, mo.Path) {
+			t.Fail()
+		}
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc, err := Load(petstorePath)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	mo := doc.FindOperation("GET", "/v1/pets")
+	headers := map[string]string{"Content-Type": "application/json; charset=utf-8"}
+
+	body := []interface{}{
+		map[string]interface{}{"id": "00000000-0000-0000-0000-000000000001", "name": "doggie"},
+	}
+	if violations := doc.ValidateResponse(mo, 200, headers, body); !assert.Empty(t, violations) {
+		t.Fail()
+	}
+
+	body = []interface{}{
+		map[string]interface{}{"id": 1, "status": "lost"},
+	}
+	violations := doc.ValidateResponse(mo, 200, headers, body)
+	var paths []string
+	for _, violation := range violations {
+		paths = append(paths, violation.Path)
+	}
+	if !assert.Equal(t, []string{"/body/0/name", "/body/0/id", "/body/0/status"}, paths) {
+		t.Fail()
+	}
+
+	// default response
+	violations = doc.ValidateResponse(mo, 500, headers, map[string]interface{}{"code": 500})
+	if !assert.Len(t, violations, 1) || !assert.Equal(t, "/body/message", violations[0].Path) {
+		t.Fail()
+	}
+
+	// undeclared status code
+	mo = doc.FindOperation("GET", "/v1/health")
+	violations = doc.ValidateResponse(mo, 503, headers, nil)
+	if !assert.Len(t, violations, 1) || !assert.Equal(t, "/status_code", violations[0].Path) {
+		t.Fail()
+	}
+
+	// undeclared content type
+	violations = doc.ValidateResponse(mo, 200, headers, "ok")
+	if !assert.Len(t, violations, 1) || !assert.Equal(t, "/headers/Content-Type", violations[0].Path) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,201 @@
+package openapi
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/httprunner/httprunner/hrp/internal/jsonschema"
+)
+
+// FindOperation finds operation by http method and request path,
+// base path of servers is trimmed, and literal path segments are preferred to templated segments.
+func (d *Document) FindOperation(method, path string) *MethodOperation {
+	method = strings.ToUpper(method)
+	candidates := []string{path}
+	for _, server := range d.Servers {
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			continue
+		}
+		basePath := strings.TrimSuffix(u.Path, "/")
+		if basePath != "" && strings.HasPrefix(path, basePath) {
+			candidates = append(candidates, strings.TrimPrefix(path, basePath))
+		}
+	}
+
+	var matched []*MethodOperation
+	for _, mo := range d.Operations() {
+		if mo.Method != method {
+			continue
+		}
+		for _, candidate := range candidates {
+			if matchPath(mo.Path, candidate) {
+				matched = append(matched, mo)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	// e.g. /pets/mine is preferred to /pets/{petId}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return strings.Count(matched[i].Path, "{") < strings.Count(matched[j].Path, "{")
+	})
+	return matched[0]
+}
+
+// matchPath checks if request path matches path template, e.g. /pets/123 matches /pets/{petId}
+func matchPath(template, path string) bool {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateResponse validates response against operation, violation paths are JSON pointers
+// in response object, e.g. /status_code, /headers/X-Rate-Limit, /body/items/0/id
+func (d *Document) ValidateResponse(mo *MethodOperation, statusCode int,
+	headers map[string]string, body interface{}) []*jsonschema.Violation {
+
+	resp, ok := d.Response(mo, statusCode)
+	if !ok {
+		return []*jsonschema.Violation{{
+			Path:    "/status_code",
+			Keyword: "responses",
+			Message: fmt.Sprintf("status code %d is not declared in %s %s", statusCode, mo.Method, mo.Path),
+			Value:   statusCode,
+		}}
+	}
+	if resp == nil {
+		return nil
+	}
+
+	var violations []*jsonschema.Violation
+
+	// validate response headers
+	var names []string
+	for name := range resp.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header := resp.Headers[name]
+		if header != nil && header.Ref != "" {
+			resolved := &Header{}
+			if err := d.Resolve(header.Ref, resolved); err != nil {
+				continue
+			}
+			header = resolved
+		}
+		// content type is validated with response content
+		if header == nil || strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		path := "/headers/" + name
+		value, exists := getHeader(headers, name)
+		if !exists {
+			if header.Required {
+				violations = append(violations, &jsonschema.Violation{
+					Path:    path,
+					Keyword: "required",
+					Message: fmt.Sprintf("required header %q is missing", name),
+				})
+			}
+			continue
+		}
+		if header.Schema != nil {
+			violations = append(violations,
+				jsonschema.ValidateWithRoot(header.Schema, convertHeaderValue(value, header.Schema), d.raw, path)...)
+		}
+	}
+
+	// validate response body
+	if len(resp.Content) == 0 {
+		return violations
+	}
+	contentType, _ := getHeader(headers, "Content-Type")
+	media, ok := matchMediaType(resp.Content, contentType)
+	if !ok {
+		var declared []string
+		for key := range resp.Content {
+			declared = append(declared, key)
+		}
+		sort.Strings(declared)
+		violations = append(violations, &jsonschema.Violation{
+			Path:    "/headers/Content-Type",
+			Keyword: "content",
+			Message: fmt.Sprintf("content type %q is not declared, expect one of %v", contentType, declared),
+			Value:   contentType,
+		})
+		return violations
+	}
+	if media != nil && media.Schema != nil {
+		violations = append(violations, jsonschema.ValidateWithRoot(media.Schema, body, d.raw, "/body")...)
+	}
+	return violations
+}
+
+// matchMediaType matches response content type with declared media types, including wildcards
+func matchMediaType(content map[string]*MediaType, contentType string) (*MediaType, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.TrimSpace(strings.Split(contentType, ";")[0])
+	}
+	mediaType = strings.ToLower(mediaType)
+	if media, ok := content[mediaType]; ok {
+		return media, true
+	}
+	if index := strings.Index(mediaType, "/"); index != -1 {
+		if media, ok := content[mediaType[:index]+"/*"]; ok {
+			return media, true
+		}
+	}
+	media, ok := content["*/*"]
+	return media, ok
+}
+
+func getHeader(headers map[string]string, name string) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// convertHeaderValue converts header string to schema type, e.g. "100" => 100 for integer schema
+func convertHeaderValue(value string, schema map[string]interface{}) interface{} {
+	switch schema["type"] {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
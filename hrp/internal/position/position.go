@@ -0,0 +1,211 @@
+// Package position locates fields in JSON and YAML documents, which is used to report problems
+// of testcase files with line and column. Fields are identified by paths like teststeps[0].request.url.
+package position
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Position is line and column of field in document, both starting from 1
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Positions maps field paths to positions of keys for map entries, or positions of elements for list items
+type Positions map[string]Position
+
+// Join joins field path with map key or list index, e.g. teststeps + 0 => teststeps[0]
+func Join(path string, key interface{}) string {
+	if index, ok := key.(int); ok {
+		return fmt.Sprintf("%s[%d]", path, index)
+	}
+	if path == "" {
+		return fmt.Sprint(key)
+	}
+	return fmt.Sprintf("%s.%v", path, key)
+}
+
+// Lookup returns position of field path, position of the closest parent field is returned if not found
+func (p Positions) Lookup(path string) Position {
+	for {
+		if pos, ok := p[path]; ok {
+			return pos
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	if pos, ok := p[""]; ok {
+		return pos
+	}
+	return Position{Line: 1, Column: 1}
+}
+
+// Offset converts byte offset in content to position, e.g. offset of json.SyntaxError
+func Offset(content []byte, offset int) Position {
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(content) {
+		offset = len(content)
+	}
+	lineStart := bytes.LastIndexByte(content[:offset], '\n') + 1
+	return Position{
+		Line:   bytes.Count(content[:offset], []byte{'\n'}) + 1,
+		Column: utf8.RuneCount(content[lineStart:offset]) + 1,
+	}
+}
+
+// Parse parses positions of all fields in document with format detected by file extension
+func Parse(content []byte, ext string) (Positions, error) {
+	switch strings.ToLower(ext) {
+	case ".json", ".har":
+		return ParseJSON(content)
+	case ".yaml", ".yml":
+		return ParseYAML(content)
+	}
+	return nil, errors.Errorf("unsupported file extension: %s", ext)
+}
+
+// ParseYAML parses positions of all fields in YAML document
+func ParseYAML(content []byte) (Positions, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	positions := Positions{"": {Line: 1, Column: 1}}
+	if len(doc.Content) > 0 {
+		walkYAML(doc.Content[0], "", positions)
+	}
+	return positions, nil
+}
+
+func walkYAML(node *yaml.Node, path string, positions Positions) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				// merge key <<: *anchor, fields of anchor are located at the anchor
+				walkYAML(value, path, positions)
+				continue
+			}
+			keyPath := Join(path, key.Value)
+			positions[keyPath] = Position{Line: key.Line, Column: key.Column}
+			walkYAML(value, keyPath, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := Join(path, i)
+			positions[itemPath] = Position{Line: item.Line, Column: item.Column}
+			walkYAML(item, itemPath, positions)
+		}
+	}
+}
+
+// ParseJSON parses positions of all fields in JSON document
+func ParseJSON(content []byte) (Positions, error) {
+	p := &jsonParser{
+		content:   content,
+		decoder:   json.NewDecoder(bytes.NewReader(content)),
+		positions: Positions{},
+	}
+	for i, c := range content {
+		if c == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+	p.positions[""] = p.position(p.next())
+	if err := p.parseValue(""); err != nil {
+		return nil, err
+	}
+	return p.positions, nil
+}
+
+type jsonParser struct {
+	content    []byte
+	decoder    *json.Decoder
+	lineStarts []int // offsets of lines except the first line
+	positions  Positions
+}
+
+// next returns offset of the next token, skipping whitespaces and delimiters
+func (p *jsonParser) next() int {
+	offset := int(p.decoder.InputOffset())
+	for offset < len(p.content) {
+		switch p.content[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+			continue
+		}
+		break
+	}
+	return offset
+}
+
+// position converts byte offset to line and column
+func (p *jsonParser) position(offset int) Position {
+	line := sort.SearchInts(p.lineStarts, offset+1)
+	lineStart := 0
+	if line > 0 {
+		lineStart = p.lineStarts[line-1]
+	}
+	if offset > len(p.content) {
+		offset = len(p.content)
+	}
+	return Position{Line: line + 1, Column: utf8.RuneCount(p.content[lineStart:offset]) + 1}
+}
+
+func (p *jsonParser) parseValue(path string) error {
+	token, err := p.decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		for p.decoder.More() {
+			offset := p.next()
+			key, err := p.decoder.Token()
+			if err != nil {
+				return err
+			}
+			keyPath := Join(path, fmt.Sprint(key))
+			p.positions[keyPath] = p.position(offset)
+			if err := p.parseValue(keyPath); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; p.decoder.More(); i++ {
+			itemPath := Join(path, i)
+			p.positions[itemPath] = p.position(p.next())
+			if err := p.parseValue(itemPath); err != nil {
+				return err
+			}
+		}
+	}
+	// consume closing delimiter
+	_, err = p.decoder.Token()
+	return err
+}
@@ -0,0 +1,101 @@
+package position
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseYAML(t *testing.T) {
+	content := `config:
+    name: demo
+teststeps:
+-
+    name: get
+    request:
+        url: /get
+-   name: post
+    valdiate:
+        - eq: [status_code, 200]
+`
+	positions, err := ParseYAML([]byte(content))
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	testData := []struct {
+		path     string
+		expected Position
+	}{
+		{"config.name", Position{2, 5}},
+		{"teststeps[0].request.url", Position{7, 9}},
+		{"teststeps[1]", Position{8, 5}},
+		{"teststeps[1].valdiate", Position{9, 5}},
+		{"teststeps[1].valdiate[0]", Position{10, 11}},
+		{"teststeps[1].valdiate[0].eq[1]", Position{10, 29}},
+		// parent position is returned if not found
+		{"teststeps[1].request.url", Position{8, 5}},
+		{"unknown", Position{1, 1}},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, positions.Lookup(data.path), data.path) {
+			t.Fail()
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	content := `{
+  "config": {"name": "demo"},
+  "teststeps": [
+    {
+      "name": "get",
+      "request": {"url": "/get", "params": [1, "是", {"a": null}]}
+    }
+  ]
+}`
+	positions, err := ParseJSON([]byte(content))
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	testData := []struct {
+		path     string
+		expected Position
+	}{
+		{"", Position{1, 1}},
+		{"config", Position{2, 3}},
+		{"config.name", Position{2, 14}},
+		{"teststeps[0]", Position{4, 5}},
+		{"teststeps[0].name", Position{5, 7}},
+		{"teststeps[0].request.url", Position{6, 19}},
+		{"teststeps[0].request.params[1]", Position{6, 48}},
+		{"teststeps[0].request.params[2].a", Position{6, 54}},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, positions.Lookup(data.path), data.path) {
+			t.Fail()
+		}
+	}
+
+	if _, err := ParseJSON([]byte(`{"config": {`)); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestOffset(t *testing.T) {
+	content := []byte("{\n  \"名\": 1,\n  x\n}")
+	testData := []struct {
+		offset   int
+		expected Position
+	}{
+		{0, Position{1, 1}},
+		{2, Position{2, 1}},
+		{12, Position{2, 9}},
+		{16, Position{3, 3}},
+		{100, Position{4, 2}},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, Offset(content, data.offset)) {
+			t.Fail()
+		}
+	}
+}
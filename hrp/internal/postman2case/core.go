@@ -0,0 +1,475 @@
+package postman2case
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp"
+	"github.com/httprunner/httprunner/hrp/internal/har2case"
+	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/sdk"
+)
+
+var (
+	regexPostmanVariable = regexp.MustCompile(`\{\{\s*([^{}\s$][^{}]*?)\s*\}\}`)
+	regexDynamicVariable = regexp.MustCompile(`\{\{\s*\$\w+\s*\}\}`)
+	regexInvalidVarChar  = regexp.MustCompile(`\W`)
+)
+
+func NewCollection(path string) *postman {
+	return &postman{
+		path: path,
+	}
+}
+
+// postman converts postman collection v2.1 to testcases, each folder is converted to one testcase file
+type postman struct {
+	path        string
+	envPath     string
+	outputDir   string
+	unconverted []string // contents which could not be converted
+}
+
+// testCase is converted testcase with output path relative to output directory, without file suffix
+type testCase struct {
+	path  string
+	tCase *hrp.TCase
+}
+
+// itemContext holds settings inherited from collection and parent folders
+type itemContext struct {
+	dir    []string // folder path for output file
+	names  []string // folder names for report
+	auth   *Auth
+	events []Event
+}
+
+func (p *postman) SetOutputDir(dir string) {
+	log.Info().Str("dir", dir).Msg("set output directory")
+	p.outputDir = dir
+}
+
+// SetEnvironment sets postman environment file, environment values are converted to config variables
+func (p *postman) SetEnvironment(path string) {
+	log.Info().Str("path", path).Msg("set postman environment")
+	p.envPath = path
+}
+
+// Unconverted returns contents which could not be converted in last generation
+func (p *postman) Unconverted() []string {
+	return p.unconverted
+}
+
+func (p *postman) GenJSON() (jsonPaths []string, err error) {
+	event := sdk.EventTracking{
+		Category: "ConvertTests",
+		Action:   "hrp postman2case --to-json",
+	}
+	// report start event
+	go sdk.SendEvent(event)
+	// report running timing event
+	defer sdk.SendEvent(event.StartTiming("execution"))
+
+	return p.gen(har2case.SuffixJSON)
+}
+
+func (p *postman) GenYAML() (yamlPaths []string, err error) {
+	event := sdk.EventTracking{
+		Category: "ConvertTests",
+		Action:   "hrp postman2case --to-yaml",
+	}
+	// report start event
+	go sdk.SendEvent(event)
+	// report running timing event
+	defer sdk.SendEvent(event.StartTiming("execution"))
+
+	return p.gen(har2case.SuffixYAML)
+}
+
+func (p *postman) gen(suffix string) (outputPaths []string, err error) {
+	testCases, err := p.makeTestCases()
+	if err != nil {
+		return nil, err
+	}
+
+	// testcases are generated in directory named after collection file, e.g. demo.postman_collection.json => demo/
+	outputDir := p.outputDir
+	if outputDir == "" {
+		outputDir = filepath.Dir(p.path)
+	}
+	outputDir = filepath.Join(outputDir, collectionBaseName(p.path))
+
+	for _, tc := range testCases {
+		outputPath := filepath.Join(outputDir, tc.path+suffix)
+		if err := os.MkdirAll(filepath.Dir(outputPath), os.ModePerm); err != nil {
+			return nil, errors.Wrap(err, "create output directory failed")
+		}
+		if err := har2case.DumpTestCase(tc.tCase, outputPath); err != nil {
+			return nil, err
+		}
+		outputPaths = append(outputPaths, outputPath)
+	}
+
+	if len(p.unconverted) > 0 {
+		log.Warn().Int("count", len(p.unconverted)).
+			Msg("some contents could not be converted, please check the warnings and complete testcases manually")
+	}
+	return outputPaths, nil
+}
+
+func (p *postman) makeTestCases() ([]*testCase, error) {
+	p.unconverted = nil
+	collection, err := loadCollection(p.path)
+	if err != nil {
+		return nil, err
+	}
+	variables, err := p.makeVariables(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &itemContext{
+		auth:   collection.Auth,
+		events: collection.Event,
+	}
+	var testCases []*testCase
+	p.walkItems(collection.Item, collection.Info.Name, ctx, variables, &testCases)
+	if len(testCases) == 0 {
+		return nil, fmt.Errorf("no request found in postman collection %s", p.path)
+	}
+	return testCases, nil
+}
+
+// walkItems converts requests in the same folder to one testcase, sub folders are converted recursively
+func (p *postman) walkItems(items []*Item, name string, ctx *itemContext,
+	variables map[string]interface{}, testCases *[]*testCase) {
+
+	var teststeps []*hrp.TStep
+	for _, item := range items {
+		if !item.IsFolder() {
+			teststeps = append(teststeps, p.makeTestStep(item, ctx))
+			continue
+		}
+		subCtx := &itemContext{
+			dir:    append(append([]string{}, ctx.dir...), sanitizeFileName(item.Name)),
+			names:  append(append([]string{}, ctx.names...), item.Name),
+			auth:   ctx.auth,
+			events: append(append([]Event{}, ctx.events...), item.Event...),
+		}
+		if item.Auth != nil {
+			subCtx.auth = item.Auth
+		}
+		p.walkItems(item.Item, item.Name, subCtx, variables, testCases)
+	}
+	if len(teststeps) == 0 {
+		return
+	}
+
+	// requests in collection root are saved as file named after collection
+	path := filepath.Join(ctx.dir...)
+	if len(ctx.dir) == 0 {
+		path = sanitizeFileName(name)
+	}
+	config := hrp.NewConfig(name)
+	for key, value := range variables {
+		config.Variables[key] = value
+	}
+	*testCases = append(*testCases, &testCase{
+		path: path,
+		tCase: &hrp.TCase{
+			Config:    config,
+			TestSteps: teststeps,
+		},
+	})
+}
+
+func (p *postman) makeTestStep(item *Item, ctx *itemContext) *hrp.TStep {
+	itemPath := strings.Join(append(append([]string{}, ctx.names...), item.Name), "/")
+	log.Info().Str("item", itemPath).Msg("convert teststep")
+
+	req := item.Request
+	request := &hrp.Request{
+		Method:  strings.ToUpper(req.Method),
+		URL:     convertVariables(req.URL.String()),
+		Params:  make(map[string]interface{}),
+		Headers: make(map[string]string),
+	}
+	if request.Method == "" {
+		request.Method = "GET"
+	}
+
+	// prepare query params
+	query := req.URL.Query
+	if len(query) == 0 {
+		if index := strings.Index(req.URL.Raw, "?"); index != -1 {
+			values, err := url.ParseQuery(req.URL.Raw[index+1:])
+			if err != nil {
+				p.report(itemPath, "invalid query", req.URL.Raw)
+			}
+			for key := range values {
+				query = append(query, KeyValue{Key: key, Value: values.Get(key)})
+			}
+		}
+	}
+	for _, param := range query {
+		if !param.Disabled {
+			request.Params[param.Key] = convertVariables(param.Value)
+		}
+	}
+
+	for _, header := range req.Header {
+		if !header.Disabled {
+			request.Headers[header.Key] = convertVariables(header.Value)
+		}
+	}
+
+	// request auth overrides auth inherited from folder and collection
+	auth := ctx.auth
+	if req.Auth != nil {
+		auth = req.Auth
+	}
+	p.convertAuth(auth, request, itemPath)
+	p.convertBody(req.Body, request, itemPath)
+
+	step := &hrp.TStep{
+		Name:       item.Name,
+		Request:    request,
+		Validators: make([]interface{}, 0),
+	}
+
+	// scripts of collection and folders are run before scripts of request
+	for _, event := range append(append([]Event{}, ctx.events...), item.Event...) {
+		switch event.Listen {
+		case "test":
+			result := convertTestScript(event.Script.Exec)
+			step.Validators = append(step.Validators, result.validators...)
+			for name, check := range result.extract {
+				if step.Extract == nil {
+					step.Extract = make(map[string]string)
+				}
+				step.Extract[name] = check
+			}
+			for _, line := range result.unconverted {
+				p.report(itemPath, "unsupported test script", line)
+			}
+		default:
+			for _, line := range event.Script.Exec {
+				if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "//") {
+					p.report(itemPath, fmt.Sprintf("unsupported %s script", event.Listen), line)
+				}
+			}
+		}
+	}
+
+	// postman dynamic variables are not supported, e.g. {{$guid}}
+	if content, err := json.Marshal(request); err == nil {
+		for _, variable := range regexDynamicVariable.FindAllString(string(content), -1) {
+			p.report(itemPath, "unsupported dynamic variable", variable)
+		}
+	}
+	return step
+}
+
+func (p *postman) convertAuth(auth *Auth, request *hrp.Request, itemPath string) {
+	if auth == nil {
+		return
+	}
+	switch auth.Type {
+	case "", "noauth":
+	case "bearer":
+		token := getAttribute(auth.Bearer, "token")
+		request.Headers["Authorization"] = "Bearer " + convertVariables(token)
+	case "basic":
+		username := getAttribute(auth.Basic, "username")
+		password := getAttribute(auth.Basic, "password")
+		if strings.Contains(username+password, "{{") {
+			// base64 could not be calculated with variables
+			p.report(itemPath, "unsupported basic auth with variables", username)
+			return
+		}
+		value := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		request.Headers["Authorization"] = "Basic " + value
+	case "apikey":
+		key := convertVariables(getAttribute(auth.APIKey, "key"))
+		value := convertVariables(getAttribute(auth.APIKey, "value"))
+		if getAttribute(auth.APIKey, "in") == "query" {
+			request.Params[key] = value
+		} else {
+			request.Headers[key] = value
+		}
+	default:
+		p.report(itemPath, "unsupported auth type", auth.Type)
+	}
+}
+
+func (p *postman) convertBody(body *Body, request *hrp.Request, itemPath string) {
+	if body == nil {
+		return
+	}
+	switch body.Mode {
+	case "", "none":
+	case "raw":
+		data := convertVariables(body.Raw)
+		isJSON := body.Options != nil && body.Options.Raw.Language == "json"
+		if contentType := getHeader(request.Headers, "Content-Type"); contentType != "" {
+			isJSON = strings.HasPrefix(contentType, "application/json")
+		} else if isJSON {
+			request.Headers["Content-Type"] = "application/json"
+		}
+		var value interface{}
+		if isJSON && json.Unmarshal([]byte(data), &value) == nil {
+			request.Body = value
+		} else if data != "" {
+			request.Body = data
+		}
+	case "urlencoded":
+		form := make(map[string]interface{})
+		for _, field := range body.URLEncoded {
+			if !field.Disabled {
+				form[field.Key] = convertVariables(field.Value)
+			}
+		}
+		if getHeader(request.Headers, "Content-Type") == "" {
+			request.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+		}
+		request.Body = form
+	case "formdata":
+		var fields []har2case.FormField
+		for _, field := range body.FormData {
+			if field.Disabled {
+				continue
+			}
+			if field.Type == "file" {
+				p.report(itemPath, "unsupported form file", fmt.Sprintf("%s=%v", field.Key, field.Src))
+				continue
+			}
+			fields = append(fields, har2case.FormField{Name: field.Key, Value: convertVariables(field.Value)})
+		}
+		contentType, data, err := har2case.MakeMultipartBody(fields, "")
+		if err != nil {
+			p.report(itemPath, "make multipart body failed", err.Error())
+			return
+		}
+		request.Headers["Content-Type"] = contentType
+		request.Body = data
+	default:
+		p.report(itemPath, "unsupported body mode", body.Mode)
+	}
+}
+
+func (p *postman) makeVariables(collection *Collection) (map[string]interface{}, error) {
+	variables := make(map[string]interface{})
+	for _, variable := range collection.Variable {
+		if !variable.Disabled {
+			variables[convertVariableName(variable.Key)] = convertValue(variable.Value)
+		}
+	}
+	if p.envPath == "" {
+		return variables, nil
+	}
+
+	// environment values override collection variables like postman
+	content, err := os.ReadFile(p.envPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "read postman environment failed")
+	}
+	var env Environment
+	if err := json.Unmarshal(content, &env); err != nil {
+		return nil, errors.Wrap(err, "unmarshal postman environment failed")
+	}
+	for _, value := range env.Values {
+		if value.Enabled == nil || *value.Enabled {
+			variables[convertVariableName(value.Key)] = convertValue(value.Value)
+		}
+	}
+	return variables, nil
+}
+
+// report logs contents which could not be converted
+func (p *postman) report(itemPath, reason, content string) {
+	log.Warn().Str("item", itemPath).Str("content", content).Msg(reason)
+	p.unconverted = append(p.unconverted, fmt.Sprintf("%s: %s: %s", itemPath, reason, content))
+}
+
+func loadCollection(path string) (*Collection, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read postman collection failed")
+	}
+	collection := &Collection{}
+	if err := json.Unmarshal(content, collection); err != nil {
+		return nil, errors.Wrap(err, "unmarshal postman collection failed")
+	}
+	if !strings.Contains(collection.Info.Schema, "v2.") {
+		log.Warn().Str("schema", collection.Info.Schema).Msg("only postman collection v2.x is supported")
+	}
+	return collection, nil
+}
+
+// convertVariables converts postman variables to hrp variables, e.g. {{host}}/get => ${host}/get
+func convertVariables(raw string) string {
+	return regexPostmanVariable.ReplaceAllStringFunc(raw, func(s string) string {
+		name := regexPostmanVariable.FindStringSubmatch(s)[1]
+		return fmt.Sprintf("${%s}", convertVariableName(name))
+	})
+}
+
+// convertVariableName replaces characters not allowed in hrp variable name, e.g. base-url => base_url
+func convertVariableName(name string) string {
+	return regexInvalidVarChar.ReplaceAllString(strings.TrimSpace(name), "_")
+}
+
+func convertValue(value interface{}) interface{} {
+	if v, ok := value.(string); ok {
+		return convertVariables(v)
+	}
+	return value
+}
+
+func getAttribute(attributes []Attribute, key string) string {
+	for _, attribute := range attributes {
+		if attribute.Key == key {
+			return fmt.Sprint(attribute.Value)
+		}
+	}
+	return ""
+}
+
+func getHeader(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// collectionBaseName returns file name without collection suffix, e.g. demo.postman_collection.json => demo
+func collectionBaseName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return strings.TrimSuffix(name, ".postman_collection")
+}
+
+// sanitizeFileName replaces characters not suitable for file name, e.g. "User APIs" => "user_apis"
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
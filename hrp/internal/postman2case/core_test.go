@@ -0,0 +1,210 @@
+package postman2case
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/httprunner/httprunner/hrp"
+)
+
+var (
+	collectionPath  = "../../../examples/data/postman/demo.postman_collection.json"
+	environmentPath = "../../../examples/data/postman/demo.postman_environment.json"
+)
+
+func TestGenJSON(t *testing.T) {
+	outputDir := t.TempDir()
+	p := NewCollection(collectionPath)
+	p.SetEnvironment(environmentPath)
+	p.SetOutputDir(outputDir)
+	jsonPaths, err := p.GenJSON()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	expected := []string{
+		filepath.Join(outputDir, "demo", "request_methods", "auth.json"),
+		filepath.Join(outputDir, "demo", "request_methods.json"),
+		filepath.Join(outputDir, "demo", "postman_echo_demo.json"),
+	}
+	if !assert.Equal(t, expected, jsonPaths) {
+		t.Fail()
+	}
+
+	// converted testcase should be loaded successfully
+	for _, path := range jsonPaths {
+		testCasePath := hrp.TestCasePath(path)
+		_, err := testCasePath.ToTestCase()
+		if !assert.NoError(t, err) {
+			t.Fail()
+		}
+	}
+}
+
+func TestGenYAML(t *testing.T) {
+	outputDir := t.TempDir()
+	p := NewCollection(collectionPath)
+	p.SetOutputDir(outputDir)
+	yamlPaths, err := p.GenYAML()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	for _, path := range yamlPaths {
+		if !assert.True(t, strings.HasSuffix(path, ".yaml")) {
+			t.Fail()
+		}
+		if _, err := os.Stat(path); !assert.NoError(t, err) {
+			t.Fail()
+		}
+	}
+}
+
+func TestMakeTestCases(t *testing.T) {
+	p := NewCollection(collectionPath)
+	p.SetEnvironment(environmentPath)
+	testCases, err := p.makeTestCases()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Len(t, testCases, 3) {
+		t.FailNow()
+	}
+
+	// requests in collection root
+	root := testCases[2].tCase
+	if !assert.Equal(t, "postman echo demo", root.Config.Name) {
+		t.Fail()
+	}
+	// environment values override collection variables
+	if !assert.Equal(t, "4.1", root.Config.Variables["version"]) {
+		t.Fail()
+	}
+	if !assert.NotContains(t, root.Config.Variables, "unused") {
+		t.Fail()
+	}
+	step := root.TestSteps[0]
+	if !assert.Equal(t, "${base_url}/get", step.Request.URL) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{"foo1": "bar1", "foo2": "${foo2}"}, step.Request.Params) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "HttpRunner/${version}", step.Request.Headers["User-Agent"]) {
+		t.Fail()
+	}
+	expectedValidators := []interface{}{
+		hrp.Validator{Check: "status_code", Assert: "equals", Expect: 200, Message: "Status code is 200"},
+		hrp.Validator{Check: "body.args.foo1", Assert: "equals", Expect: "bar1", Message: "check args"},
+		hrp.Validator{Check: "body.args.foo2", Assert: "equals", Expect: "$foo2", Message: "check args"},
+		hrp.Validator{Check: `body.headers."user-agent"`, Assert: "contains", Expect: "HttpRunner", Message: "check args"},
+	}
+	if !assert.Equal(t, expectedValidators, step.Validators) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]string{"foo3": "body.args.foo2"}, step.Extract) {
+		t.Fail()
+	}
+
+	// requests in folder
+	folder := testCases[1].tCase
+	if !assert.Equal(t, "request methods", folder.Config.Name) {
+		t.Fail()
+	}
+	step = folder.TestSteps[0]
+	if !assert.Equal(t, map[string]interface{}{
+		"foo3":  "${foo3}",
+		"items": []interface{}{float64(1), float64(2)},
+		"id":    "{{$guid}}",
+	}, step.Request.Body) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "application/json", step.Request.Headers["Content-Type"]) {
+		t.Fail()
+	}
+	expectedValidators = []interface{}{
+		hrp.Validator{Check: "body.json.foo3", Assert: "equals", Expect: "${foo3}", Message: "json echoed"},
+		hrp.Validator{Check: "body.json.items", Assert: "length_equals", Expect: float64(2), Message: "items count"},
+		hrp.Validator{Check: `headers."Content-Type"`, Assert: "contains", Expect: "application/json", Message: "items count"},
+	}
+	if !assert.Equal(t, expectedValidators, step.Validators) {
+		t.Fail()
+	}
+	step = folder.TestSteps[1]
+	if !assert.Equal(t, map[string]interface{}{"foo1": "${foo1}", "foo2": "bar2"}, step.Request.Body) {
+		t.Fail()
+	}
+
+	// requests in nested folder
+	step = testCases[0].tCase.TestSteps[0]
+	if !assert.Equal(t, "Bearer ${token}", step.Request.Headers["Authorization"]) {
+		t.Fail()
+	}
+
+	// unconverted contents are reported
+	unconverted := strings.Join(p.Unconverted(), "\n")
+	for _, content := range []string{
+		"console.log(jsonData);",
+		`pm.environment.set("ts", Date.now());`,
+		"{{$guid}}",
+		"file=/Users/demo/a.txt",
+	} {
+		if !assert.Contains(t, unconverted, content) {
+			t.Fail()
+		}
+	}
+	if !assert.Len(t, p.Unconverted(), 4) {
+		t.Fail()
+	}
+}
+
+func TestConvertTestScript(t *testing.T) {
+	result := convertTestScript([]string{
+		`tests["Status code is 201"] = responseCode.code === 201;`,
+		`const data = JSON.parse(responseBody);`,
+		`pm.expect(data.items[0]["id"]).to.be.above(10);`,
+		`pm.expect(data.id).to.match(/^\d+$/);`,
+		`pm.expect(data.id).to.not.eql(1);`,
+		`pm.response.to.have.header("X-Token", 'abc');`,
+		`pm.collectionVariables.set("uid", data.user.id);`,
+		`pm.environment.set("now", new Date());`,
+	})
+	expected := []interface{}{
+		hrp.Validator{Check: "status_code", Assert: "equals", Expect: 201},
+		hrp.Validator{Check: "body.items[0].id", Assert: "greater_than", Expect: float64(10)},
+		hrp.Validator{Check: "body.id", Assert: "regex_match", Expect: `^\d+$`},
+		hrp.Validator{Check: `headers."X-Token"`, Assert: "equals", Expect: "abc"},
+	}
+	if !assert.Equal(t, expected, result.validators) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]string{"uid": "body.user.id"}, result.extract) {
+		t.Fail()
+	}
+	if !assert.Equal(t, []string{
+		`pm.expect(data.id).to.not.eql(1);`,
+		`pm.environment.set("now", new Date());`,
+	}, result.unconverted) {
+		t.Fail()
+	}
+}
+
+func TestConvertVariables(t *testing.T) {
+	testData := []struct {
+		raw      string
+		expected string
+	}{
+		{"{{host}}/get", "${host}/get"},
+		{"{{ host }}:{{port}}", "${host}:${port}"},
+		{"{{base-url}}/api", "${base_url}/api"},
+		{"{{$timestamp}}", "{{$timestamp}}"},
+		{"no variables", "no variables"},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, convertVariables(data.raw)) {
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package postman2case
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/httprunner/httprunner/hrp/internal/json"
+)
+
+/*
+Postman Collection Format v2.1
+https://schema.postman.com/collection/json/v2.1.0/draft-07/docs/index.html
+only the fields used in conversion are defined here
+*/
+
+// Collection is the root of postman collection export
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []*Item    `json:"item"`
+	Event    []Event    `json:"event,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+	Auth     *Auth      `json:"auth,omitempty"`
+}
+
+// Info contains collection name and schema
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is either a request or a folder with nested items
+type Item struct {
+	Name    string   `json:"name"`
+	Item    []*Item  `json:"item,omitempty"`    // only for folder
+	Request *Request `json:"request,omitempty"` // only for request
+	Event   []Event  `json:"event,omitempty"`
+	Auth    *Auth    `json:"auth,omitempty"`
+}
+
+// IsFolder returns true if item is a folder
+func (i *Item) IsFolder() bool {
+	return i.Request == nil
+}
+
+// Event holds prerequest or test script
+type Event struct {
+	Listen string `json:"listen"` // prerequest or test
+	Script Script `json:"script"`
+}
+
+// Script holds script lines
+type Script struct {
+	Type string `json:"type,omitempty"`
+	Exec Lines  `json:"exec,omitempty"`
+}
+
+// Lines could be exported as a string or list of strings
+type Lines []string
+
+func (l *Lines) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = strings.Split(text, "\n")
+		return nil
+	}
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return err
+	}
+	*l = lines
+	return nil
+}
+
+// Request could be exported as a url string or request object
+type Request struct {
+	Method string     `json:"method"`
+	Header []KeyValue `json:"header,omitempty"`
+	Body   *Body      `json:"body,omitempty"`
+	URL    URL        `json:"url"`
+	Auth   *Auth      `json:"auth,omitempty"`
+}
+
+func (r *Request) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		r.Method = "GET"
+		r.URL = URL{Raw: raw}
+		return nil
+	}
+	type request Request // avoid recursion
+	return json.Unmarshal(data, (*request)(r))
+}
+
+// URL could be exported as a raw string or url object
+type URL struct {
+	Raw      string     `json:"raw"`
+	Protocol string     `json:"protocol,omitempty"`
+	Host     []string   `json:"host,omitempty"`
+	Path     []string   `json:"path,omitempty"`
+	Query    []KeyValue `json:"query,omitempty"`
+}
+
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+	type url URL // avoid recursion
+	return json.Unmarshal(data, (*url)(u))
+}
+
+// String returns url without query, query is converted separately
+func (u *URL) String() string {
+	raw := u.Raw
+	if raw == "" && len(u.Host) > 0 {
+		raw = strings.Join(u.Host, ".")
+		if len(u.Path) > 0 {
+			raw += "/" + strings.Join(u.Path, "/")
+		}
+		if u.Protocol != "" {
+			raw = fmt.Sprintf("%s://%s", u.Protocol, raw)
+		}
+	}
+	if index := strings.Index(raw, "?"); index != -1 {
+		raw = raw[:index]
+	}
+	return raw
+}
+
+// KeyValue is used for headers, query, urlencoded and formdata fields
+type KeyValue struct {
+	Key      string      `json:"key"`
+	Value    string      `json:"value"`
+	Disabled bool        `json:"disabled,omitempty"`
+	Type     string      `json:"type,omitempty"` // text or file for formdata
+	Src      interface{} `json:"src,omitempty"`  // file path for formdata
+}
+
+// Body holds request body in different modes
+type Body struct {
+	Mode       string       `json:"mode"` // raw, urlencoded, formdata, file, graphql
+	Raw        string       `json:"raw,omitempty"`
+	URLEncoded []KeyValue   `json:"urlencoded,omitempty"`
+	FormData   []KeyValue   `json:"formdata,omitempty"`
+	Options    *BodyOptions `json:"options,omitempty"`
+}
+
+type BodyOptions struct {
+	Raw struct {
+		Language string `json:"language"` // json, text, xml, etc.
+	} `json:"raw"`
+}
+
+// Auth holds request authorization, attributes are stored as key/value list by auth type
+type Auth struct {
+	Type   string      `json:"type"` // noauth, basic, bearer, apikey, etc.
+	Basic  []Attribute `json:"basic,omitempty"`
+	Bearer []Attribute `json:"bearer,omitempty"`
+	APIKey []Attribute `json:"apikey,omitempty"`
+}
+
+// Attribute is auth attribute, value may be string or bool
+type Attribute struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// Variable is collection variable
+type Variable struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled,omitempty"`
+}
+
+// Environment is postman environment export
+type Environment struct {
+	Name   string             `json:"name"`
+	Values []EnvironmentValue `json:"values"`
+}
+
+type EnvironmentValue struct {
+	Key     string      `json:"key"`
+	Value   interface{} `json:"value"`
+	Enabled *bool       `json:"enabled,omitempty"` // enabled by default
+}
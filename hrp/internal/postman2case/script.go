@@ -0,0 +1,280 @@
+package postman2case
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/httprunner/httprunner/hrp"
+	"github.com/httprunner/httprunner/hrp/internal/json"
+)
+
+// scriptResult holds validators and extractors converted from test script,
+// lines which could not be converted are kept in unconverted.
+type scriptResult struct {
+	validators  []interface{}
+	extract     map[string]string
+	unconverted []string
+}
+
+var (
+	regexTestName      = regexp.MustCompile(`^pm\.test\(\s*(?:"([^"]*)"|'([^']*)')\s*,`)
+	regexTestFunc      = regexp.MustCompile(`^\s*(?:function\s*\(\s*\)|\(\s*\)\s*=>)\s*\{?`)
+	regexJSONVar       = regexp.MustCompile(`^(?:var|let|const)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:pm\.response\.json\(\)|JSON\.parse\(responseBody\))\s*;?$`)
+	regexStatus        = regexp.MustCompile(`^pm\.response\.to\.(?:have|be)\.status\((\d+)\)\s*;?$`)
+	regexLegacyStatus  = regexp.MustCompile(`^tests\[.+\]\s*=\s*responseCode\.code\s*===?\s*(\d+)\s*;?$`)
+	regexHeaderValue   = regexp.MustCompile(`^pm\.response\.to\.have\.header\(\s*(?:"([^"]+)"|'([^']+)')\s*,\s*(.+)\)\s*;?$`)
+	regexExpect        = regexp.MustCompile(`^pm\.expect\((.+?)\)\.to\.((?:(?:be|have|deep|not)\.)*)(\w+)\((.*)\)\s*;?$`)
+	regexSetVariable   = regexp.MustCompile(`^(?:pm\.(?:environment|collectionVariables|globals|variables)\.set|postman\.set(?:Environment|Global)Variable)\(\s*(?:"([^"]+)"|'([^']+)')\s*,\s*(.+)\)\s*;?$`)
+	regexGetVariable   = regexp.MustCompile(`^(?:pm\.(?:environment|collectionVariables|globals|variables)\.get|postman\.get(?:Environment|Global)Variable)\(\s*(?:"([^"]+)"|'([^']+)')\s*\)$`)
+	regexResponseJSON  = regexp.MustCompile(`^pm\.response\.json\(\)`)
+	regexResponseHead  = regexp.MustCompile(`^pm\.response\.headers\.get\(\s*(?:"([^"]+)"|'([^']+)')\s*\)$`)
+	regexPathToken     = regexp.MustCompile(`^(?:\.([A-Za-z_$][\w$]*)|\[(\d+)\]|\[\s*(?:"([^"]+)"|'([^']+)')\s*\])`)
+	regexJmesPathIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// expectAssertions maps chai assertion to hrp assertion
+var expectAssertions = map[string]string{
+	"eql":     "equals",
+	"equal":   "equals",
+	"equals":  "equals",
+	"eq":      "equals",
+	"include": "contains",
+	"contain": "contains",
+	"above":   "greater_than",
+	"below":   "less_than",
+	"least":   "greater_or_equals",
+	"most":    "less_or_equals",
+	"match":   "regex_match",
+}
+
+// convertTestScript converts simple pm.test/pm.expect assertions to validators,
+// and pm.environment.set from json response to extract.
+func convertTestScript(lines []string) *scriptResult {
+	result := &scriptResult{
+		extract: make(map[string]string),
+	}
+	jsonVars := make(map[string]bool)
+	message := ""
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if matches := regexTestName.FindStringSubmatch(line); matches != nil {
+			message = matches[1] + matches[2]
+			// one-liner test, e.g. pm.test("ok", () => pm.response.to.have.status(200));
+			line = trimTestWrapper(line[len(matches[0]):])
+		}
+		if isClosingLine(line) {
+			continue
+		}
+		if matches := regexJSONVar.FindStringSubmatch(line); matches != nil {
+			jsonVars[matches[1]] = true
+			continue
+		}
+
+		if matches := regexStatus.FindStringSubmatch(line); matches != nil {
+			result.addValidator("status_code", "equals", parseInt(matches[1]), message)
+			continue
+		}
+		if matches := regexLegacyStatus.FindStringSubmatch(line); matches != nil {
+			result.addValidator("status_code", "equals", parseInt(matches[1]), message)
+			continue
+		}
+		if matches := regexHeaderValue.FindStringSubmatch(line); matches != nil {
+			expect, ok := convertLiteral(matches[3])
+			if !ok {
+				result.unconverted = append(result.unconverted, line)
+				continue
+			}
+			header := matches[1] + matches[2]
+			result.addValidator(fmt.Sprintf(`headers."%s"`, header), "equals", expect, message)
+			continue
+		}
+		if matches := regexExpect.FindStringSubmatch(line); matches != nil {
+			if !result.convertExpect(matches, jsonVars, message) {
+				result.unconverted = append(result.unconverted, line)
+			}
+			continue
+		}
+		if matches := regexSetVariable.FindStringSubmatch(line); matches != nil {
+			name := convertVariableName(matches[1] + matches[2])
+			check, ok := convertCheckItem(strings.TrimSpace(matches[3]), jsonVars)
+			if !ok || check == "status_code" {
+				result.unconverted = append(result.unconverted, line)
+				continue
+			}
+			result.extract[name] = check
+			continue
+		}
+		result.unconverted = append(result.unconverted, line)
+	}
+	return result
+}
+
+func (s *scriptResult) addValidator(check, assert string, expect interface{}, message string) {
+	s.validators = append(s.validators, hrp.Validator{
+		Check:   check,
+		Assert:  assert,
+		Expect:  expect,
+		Message: message,
+	})
+}
+
+// convertExpect converts pm.expect(actual).to.xxx(expected) to validator
+func (s *scriptResult) convertExpect(matches []string, jsonVars map[string]bool, message string) bool {
+	actual, chain, method, arg := strings.TrimSpace(matches[1]), matches[2], matches[3], strings.TrimSpace(matches[4])
+	if strings.Contains(chain, "not.") {
+		return false
+	}
+
+	// e.g. pm.expect(jsonData.items).to.have.lengthOf(2)
+	if method == "lengthOf" {
+		check, ok := convertCheckItem(actual, jsonVars)
+		expect, ok2 := convertLiteral(arg)
+		if !ok || !ok2 {
+			return false
+		}
+		s.addValidator(check, "length_equals", expect, message)
+		return true
+	}
+
+	assert, ok := expectAssertions[method]
+	if !ok {
+		return false
+	}
+	var expect interface{}
+	if assert == "regex_match" {
+		// e.g. pm.expect(jsonData.id).to.match(/^\d+$/)
+		if len(arg) < 2 || arg[0] != '/' || arg[len(arg)-1] != '/' {
+			return false
+		}
+		expect = arg[1 : len(arg)-1]
+	} else if expect, ok = convertLiteral(arg); !ok {
+		return false
+	}
+
+	// e.g. pm.expect(jsonData.items.length).to.eql(2)
+	if strings.HasSuffix(actual, ".length") && assert == "equals" {
+		check, ok := convertCheckItem(strings.TrimSuffix(actual, ".length"), jsonVars)
+		if !ok {
+			return false
+		}
+		s.addValidator(check, "length_equals", expect, message)
+		return true
+	}
+
+	check, ok := convertCheckItem(actual, jsonVars)
+	if !ok {
+		return false
+	}
+	s.addValidator(check, assert, expect, message)
+	return true
+}
+
+// convertCheckItem converts javascript expression of response to jmespath check item
+func convertCheckItem(expr string, jsonVars map[string]bool) (string, bool) {
+	switch expr {
+	case "pm.response.code", "responseCode.code":
+		return "status_code", true
+	case "pm.response.text()", "responseBody":
+		return "body", true
+	}
+	if matches := regexResponseHead.FindStringSubmatch(expr); matches != nil {
+		return fmt.Sprintf(`headers."%s"`, matches[1]+matches[2]), true
+	}
+
+	var rest string
+	if loc := regexResponseJSON.FindStringIndex(expr); loc != nil {
+		rest = expr[loc[1]:]
+	} else {
+		index := strings.IndexAny(expr, ".[")
+		if index == -1 {
+			index = len(expr)
+		}
+		if !jsonVars[expr[:index]] {
+			return "", false
+		}
+		rest = expr[index:]
+	}
+
+	path, ok := convertJSPath(rest)
+	if !ok {
+		return "", false
+	}
+	return "body" + path, true
+}
+
+// convertJSPath converts javascript property path to jmespath,
+// e.g. .args.foo => .args.foo, .items[0]["content-type"] => .items[0]."content-type"
+func convertJSPath(path string) (string, bool) {
+	var builder strings.Builder
+	for path != "" {
+		matches := regexPathToken.FindStringSubmatch(path)
+		if matches == nil {
+			return "", false
+		}
+		path = path[len(matches[0]):]
+		switch {
+		case matches[2] != "":
+			builder.WriteString("[" + matches[2] + "]")
+		default:
+			name := matches[1] + matches[3] + matches[4]
+			if regexJmesPathIdent.MatchString(name) {
+				builder.WriteString("." + name)
+			} else {
+				builder.WriteString(fmt.Sprintf(`."%s"`, name))
+			}
+		}
+	}
+	return builder.String(), true
+}
+
+// convertLiteral converts javascript literal or variable getter to expected value
+func convertLiteral(expr string) (interface{}, bool) {
+	expr = strings.TrimSpace(expr)
+	if matches := regexGetVariable.FindStringSubmatch(expr); matches != nil {
+		return "$" + convertVariableName(matches[1]+matches[2]), true
+	}
+	// single quoted string, e.g. 'bar'
+	if len(expr) >= 2 && expr[0] == '\'' && expr[len(expr)-1] == '\'' {
+		inner := strings.ReplaceAll(expr[1:len(expr)-1], `\'`, `'`)
+		return convertVariables(inner), true
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(expr), &value); err != nil {
+		return nil, false
+	}
+	if v, ok := value.(string); ok {
+		return convertVariables(v), true
+	}
+	return value, true
+}
+
+func parseInt(s string) int {
+	value, _ := strconv.Atoi(s)
+	return value
+}
+
+// trimTestWrapper removes callback header and trailing unbalanced brackets of pm.test,
+// e.g. `() => pm.response.to.have.status(200));` => `pm.response.to.have.status(200)`
+func trimTestWrapper(line string) string {
+	line = strings.TrimSpace(regexTestFunc.ReplaceAllString(line, ""))
+	for {
+		line = strings.TrimSpace(strings.TrimSuffix(line, ";"))
+		if strings.HasSuffix(line, "}") ||
+			(strings.HasSuffix(line, ")") && strings.Count(line, ")") > strings.Count(line, "(")) {
+			line = line[:len(line)-1]
+			continue
+		}
+		return line
+	}
+}
+
+// isClosingLine checks if line only closes blocks, e.g. "});"
+func isClosingLine(line string) bool {
+	return strings.Trim(line, "{}();, ") == ""
+}
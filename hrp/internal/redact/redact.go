@@ -0,0 +1,420 @@
+/*
+Package redact masks secrets in request logs, zerolog output, summary and HTML report.
+
+Each run has its own Redactor. Values of sensitive headers and variables, and values matched by JSON paths
+in request and response bodies are masked in place of the fields, thus masking cost does not grow with traffic.
+Values loaded from explicitly declared secret sources, e.g. the encrypted secrets file, are registered as
+secrets and replaced with Mask wherever they appear, including their JSON and URL escaped forms.
+*/
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/httprunner/httprunner/hrp/internal/jsonpath"
+)
+
+// Mask replaces secrets
+const Mask = "******"
+
+const (
+	minSecretLength = 3     // shorter values are too common to be masked, e.g. 0, 1, on
+	maxSecrets      = 10000 // registering more secrets fails to bound memory and cost of replacing
+)
+
+// DefaultHeaders are header names whose values are always masked
+var DefaultHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token",
+}
+
+// SensitiveNames are case-insensitive substrings of variable names whose values are always masked
+var SensitiveNames = []string{
+	"password", "passwd", "secret", "token", "api_key", "apikey", "access_key", "private_key", "credential",
+}
+
+// Redactor masks secrets of one run, it is safe for concurrent use and nil Redactor masks nothing.
+type Redactor struct {
+	mu        sync.RWMutex
+	secrets   map[string]struct{}
+	headers   map[string]struct{} // lower-cased header names
+	variables map[string]struct{}
+	jsonPaths map[string]*jsonpath.Path
+	replacer  *strings.Replacer // built lazily, reset when secrets change
+}
+
+// New returns a Redactor masking DefaultHeaders and variables named with SensitiveNames.
+func New() *Redactor {
+	r := &Redactor{
+		secrets:   make(map[string]struct{}),
+		headers:   make(map[string]struct{}),
+		variables: make(map[string]struct{}),
+		jsonPaths: make(map[string]*jsonpath.Path),
+	}
+	for _, name := range DefaultHeaders {
+		r.headers[strings.ToLower(name)] = struct{}{}
+	}
+	return r
+}
+
+// attached redactors of running testcases, which are used by writers created by Writer
+var attached sync.Map // *Redactor => struct{}
+
+// Attach attaches r to writers created by Writer, thus secrets of r are masked in logs.
+func Attach(r *Redactor) {
+	if r != nil {
+		attached.Store(r, struct{}{})
+	}
+}
+
+// Detach detaches r from writers created by Writer.
+func Detach(r *Redactor) {
+	if r != nil {
+		attached.Delete(r)
+	}
+}
+
+// AddSecrets registers secret values to be masked, values shorter than 3 characters are ignored.
+// Error is returned if secrets registered would exceed the limit, no value is registered in that case.
+func (r *Redactor) AddSecrets(values ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	added := make(map[string]struct{})
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength {
+			continue
+		}
+		if _, ok := r.secrets[value]; !ok {
+			added[value] = struct{}{}
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	if len(r.secrets)+len(added) > maxSecrets {
+		return errors.Errorf("too many secrets, at most %d secrets could be registered", maxSecrets)
+	}
+	for value := range added {
+		r.secrets[value] = struct{}{}
+	}
+	r.replacer = nil
+	return nil
+}
+
+// AddSecretData registers all scalar values in data as secrets, e.g. variables loaded from secret sources.
+func (r *Redactor) AddSecretData(data interface{}) error {
+	return r.AddSecrets(leaves(data)...)
+}
+
+// AddHeaders adds header names whose values are masked, names are case-insensitive.
+func (r *Redactor) AddHeaders(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.headers[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
+	}
+}
+
+// AddVariables adds variable names whose values are masked.
+func (r *Redactor) AddVariables(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.variables[strings.TrimSpace(name)] = struct{}{}
+	}
+}
+
+// AddJSONPaths adds JSONPath expressions, values matched in request and response bodies are masked.
+func (r *Redactor) AddJSONPaths(exprs ...string) error {
+	paths := make(map[string]*jsonpath.Path)
+	for _, expr := range exprs {
+		path, err := jsonpath.Compile(expr)
+		if err != nil {
+			return err
+		}
+		paths[expr] = path
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for expr, path := range paths {
+		r.jsonPaths[expr] = path
+	}
+	return nil
+}
+
+// IsSensitiveHeader reports whether values of header name should be masked.
+func (r *Redactor) IsSensitiveHeader(name string) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.headers[strings.ToLower(name)]
+	return ok
+}
+
+// IsSensitiveVariable reports whether value of variable name should be masked.
+func (r *Redactor) IsSensitiveVariable(name string) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	_, ok := r.variables[name]
+	r.mu.RUnlock()
+	if ok {
+		return true
+	}
+	lower := strings.ToLower(name)
+	for _, sensitive := range SensitiveNames {
+		if strings.Contains(lower, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskHTTPHeader returns copy of header with values of sensitive headers masked.
+func (r *Redactor) MaskHTTPHeader(header http.Header) http.Header {
+	masked := header.Clone()
+	for name, values := range masked {
+		if !r.IsSensitiveHeader(name) {
+			continue
+		}
+		for i := range values {
+			values[i] = Mask
+		}
+	}
+	return masked
+}
+
+// MaskVariable returns Mask if the variable is sensitive, otherwise value is returned.
+func (r *Redactor) MaskVariable(name string, value interface{}) interface{} {
+	if r.IsSensitiveVariable(name) {
+		return Mask
+	}
+	return value
+}
+
+// MaskVariables returns copy of variables with values of sensitive variables masked.
+func (r *Redactor) MaskVariables(variables map[string]interface{}) map[string]interface{} {
+	if variables == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		masked[name] = r.MaskVariable(name, value)
+	}
+	return masked
+}
+
+// MaskJSON returns copy of data with values matched by JSON paths masked, data should be decoded from json.
+func (r *Redactor) MaskJSON(data interface{}) interface{} {
+	if r == nil {
+		return data
+	}
+	r.mu.RLock()
+	paths := make([]*jsonpath.Path, 0, len(r.jsonPaths))
+	for _, path := range r.jsonPaths {
+		paths = append(paths, path)
+	}
+	r.mu.RUnlock()
+	if len(paths) == 0 {
+		return data
+	}
+
+	masked := copyJSON(data)
+	for _, path := range paths {
+		path.Replace(masked, func(interface{}) interface{} {
+			return Mask
+		})
+	}
+	return masked
+}
+
+// copyJSON copies objects and arrays of data recursively, scalar values are shared
+func copyJSON(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = copyJSON(item)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = copyJSON(item)
+		}
+		return list
+	}
+	return data
+}
+
+// leaves converts scalar value to string, or collects scalar values of map and list recursively
+func leaves(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, leaves(item)...)
+		}
+		return values
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, leaves(item)...)
+		}
+		return values
+	case string:
+		return []string{v}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// getReplacer returns replacer of all registered secrets, nil if no secret registered
+func (r *Redactor) getReplacer() *strings.Replacer {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	replacer, count := r.replacer, len(r.secrets)
+	r.mu.RUnlock()
+	if replacer != nil || count == 0 {
+		return replacer
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.replacer != nil {
+		return r.replacer
+	}
+	forms := make(map[string]struct{})
+	for secret := range r.secrets {
+		forms[secret] = struct{}{}
+		// escaped forms in json strings and urls
+		if b, err := json.Marshal(secret); err == nil {
+			forms[string(b[1:len(b)-1])] = struct{}{}
+		}
+		forms[url.QueryEscape(secret)] = struct{}{}
+	}
+	secrets := make([]string, 0, len(forms))
+	for form := range forms {
+		secrets = append(secrets, form)
+	}
+	// replace longer secrets first, thus secrets containing others are masked entirely
+	sort.Slice(secrets, func(i, j int) bool {
+		if len(secrets[i]) != len(secrets[j]) {
+			return len(secrets[i]) > len(secrets[j])
+		}
+		return secrets[i] < secrets[j]
+	})
+	oldnew := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		oldnew = append(oldnew, secret, Mask)
+	}
+	r.replacer = strings.NewReplacer(oldnew...)
+	return r.replacer
+}
+
+// String replaces registered secrets in s with Mask.
+func (r *Redactor) String(s string) string {
+	replacer := r.getReplacer()
+	if replacer == nil {
+		return s
+	}
+	return replacer.Replace(s)
+}
+
+// Data replaces registered secrets in strings of data in place, including strings in struct fields,
+// maps, slices and interfaces. Data should be a pointer, map or slice, unexported fields are skipped.
+func (r *Redactor) Data(data interface{}) {
+	if r.getReplacer() == nil {
+		return
+	}
+	r.redactValue(reflect.ValueOf(data))
+}
+
+func (r *Redactor) redactValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(r.String(v.String()))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			r.redactValue(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		// values held by interface are not addressable, redact a copy and set it back
+		elem := v.Elem()
+		cp := reflect.New(elem.Type()).Elem()
+		cp.Set(elem)
+		r.redactValue(cp)
+		v.Set(cp)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() {
+				r.redactValue(field)
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			elem := v.MapIndex(key)
+			cp := reflect.New(elem.Type()).Elem()
+			cp.Set(elem)
+			r.redactValue(cp)
+			v.SetMapIndex(key, cp)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.CanSet() {
+				v.SetBytes([]byte(r.String(string(v.Bytes()))))
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			r.redactValue(v.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.redactValue(v.Index(i))
+		}
+	}
+}
+
+type writer struct {
+	w io.Writer
+}
+
+// Writer wraps w to mask secrets of attached redactors in written content, e.g. zerolog output.
+func Writer(w io.Writer) io.Writer {
+	return &writer{w: w}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	s := string(p)
+	attached.Range(func(key, _ interface{}) bool {
+		s = key.(*Redactor).String(s)
+		return true
+	})
+	if _, err := io.WriteString(w.w, s); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,209 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	r := New()
+	if err := r.AddSecrets("s3cr3t", "ab", `p"ss word`); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	testData := []struct {
+		raw      string
+		expected string
+	}{
+		{"token=s3cr3t", "token=******"},
+		{"ab is too short to be masked", "ab is too short to be masked"},
+		{`{"password": "p\"ss word"}`, `{"password": "******"}`},
+		{"password=p%22ss+word", "password=******"},
+		{"nothing to mask", "nothing to mask"},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, r.String(data.raw)) {
+			t.Fail()
+		}
+	}
+
+	// secrets of other redactors are not masked
+	if !assert.Equal(t, "token=s3cr3t", New().String("token=s3cr3t")) {
+		t.Fail()
+	}
+	var nilRedactor *Redactor
+	if !assert.Equal(t, "token=s3cr3t", nilRedactor.String("token=s3cr3t")) {
+		t.Fail()
+	}
+}
+
+func TestAddSecretsLimit(t *testing.T) {
+	r := New()
+	values := make([]string, maxSecrets)
+	for i := range values {
+		values[i] = fmt.Sprintf("secret-%d", i)
+	}
+	if err := r.AddSecrets(values...); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	// registered values are skipped
+	if err := r.AddSecrets("secret-0"); !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if err := r.AddSecrets("secret-new", "secret-1"); !assert.Error(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "secret-new", r.String("secret-new")) {
+		t.Fail()
+	}
+}
+
+func TestMaskHTTPHeader(t *testing.T) {
+	r := New()
+	r.AddHeaders("X-Sign")
+	header := http.Header{
+		"Content-Type":  {"application/json"},
+		"Authorization": {"Bearer abc.def.ghi"},
+		"Set-Cookie":    {"lang=en", "session=9f8e7d"},
+		"X-Sign":        {"c2lnbmF0dXJl"},
+	}
+	expected := http.Header{
+		"Content-Type":  {"application/json"},
+		"Authorization": {"******"},
+		"Set-Cookie":    {"******", "******"},
+		"X-Sign":        {"******"},
+	}
+	if !assert.Equal(t, expected, r.MaskHTTPHeader(header)) {
+		t.Fail()
+	}
+	// original header is kept
+	if !assert.Equal(t, "Bearer abc.def.ghi", header.Get("Authorization")) {
+		t.Fail()
+	}
+}
+
+func TestMaskVariablesAndJSON(t *testing.T) {
+	r := New()
+	r.AddVariables("credentials")
+	variables := map[string]interface{}{
+		"user":         "debugtalk",
+		"password":     "123456",
+		"access_token": "tk-001",
+		"credentials":  map[string]interface{}{"key": "ak-001"},
+	}
+	expected := map[string]interface{}{
+		"user":         "debugtalk",
+		"password":     Mask,
+		"access_token": Mask,
+		"credentials":  Mask,
+	}
+	if !assert.Equal(t, expected, r.MaskVariables(variables)) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "123456", variables["password"]) {
+		t.Fail()
+	}
+
+	if err := r.AddJSONPaths("$.data.token", "$..card"); !assert.NoError(t, err) {
+		t.Fail()
+	}
+	data := map[string]interface{}{
+		"data": map[string]interface{}{"token": "tk-001", "user": "debugtalk"},
+		"list": []interface{}{map[string]interface{}{"card": 4111111111111111}},
+	}
+	expectedData := map[string]interface{}{
+		"data": map[string]interface{}{"token": Mask, "user": "debugtalk"},
+		"list": []interface{}{map[string]interface{}{"card": Mask}},
+	}
+	if !assert.Equal(t, expectedData, r.MaskJSON(data)) {
+		t.Fail()
+	}
+	// original data is kept, and masked values are not registered as secrets
+	if !assert.Equal(t, "tk-001", data["data"].(map[string]interface{})["token"]) ||
+		!assert.Equal(t, "tk-001", r.String("tk-001")) {
+		t.Fail()
+	}
+	if err := r.AddJSONPaths("data.token"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestAddSecretData(t *testing.T) {
+	r := New()
+	// numbers and booleans of secret sources are masked as well
+	err := r.AddSecretData(map[string]interface{}{
+		"db":   map[string]interface{}{"password": "p@ssw0rd", "port": 5432, "ssl": true},
+		"keys": []interface{}{"ak-001", 123456},
+	})
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	masked := r.String("password=p@ssw0rd key=ak-001 port=5432 ssl=true code=123456")
+	if !assert.Equal(t, "password=****** key=****** port=****** ssl=****** code=******", masked) {
+		t.Fail()
+	}
+}
+
+func TestData(t *testing.T) {
+	type record struct {
+		Name    string
+		Data    interface{}
+		Headers map[string]string
+		private string
+	}
+	r := New()
+	if err := r.AddSecrets("s3cr3t"); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	rec := &record{
+		Name: "login with s3cr3t",
+		Data: map[string]interface{}{
+			"body":  []interface{}{"s3cr3t", 1, nil},
+			"token": "s3cr3t",
+		},
+		Headers: map[string]string{"Authorization": "Bearer s3cr3t"},
+		private: "s3cr3t",
+	}
+	r.Data(rec)
+
+	expected := &record{
+		Name: "login with ******",
+		Data: map[string]interface{}{
+			"body":  []interface{}{"******", 1, nil},
+			"token": "******",
+		},
+		Headers: map[string]string{"Authorization": "Bearer ******"},
+		private: "s3cr3t",
+	}
+	if !assert.Equal(t, expected, rec) {
+		t.Fail()
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := Writer(&buf)
+	r := New()
+	if err := r.AddSecrets("s3cr3t"); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	Attach(r)
+	n, err := w.Write([]byte(`{"level":"info","token":"s3cr3t"}`))
+	if !assert.NoError(t, err) || !assert.Equal(t, 33, n) {
+		t.Fail()
+	}
+	if !assert.Equal(t, `{"level":"info","token":"******"}`, buf.String()) {
+		t.Fail()
+	}
+
+	// secrets of detached redactor are not masked
+	Detach(r)
+	buf.Reset()
+	if _, err := w.Write([]byte("s3cr3t")); !assert.NoError(t, err) || !assert.Equal(t, "s3cr3t", buf.String()) {
+		t.Fail()
+	}
+}
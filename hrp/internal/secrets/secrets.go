@@ -0,0 +1,160 @@
+/*
+Package secrets encrypts and decrypts secrets file of testcase variables.
+
+Secrets file is a YAML or JSON mapping of variables encrypted with AES-256-GCM, the AES key is derived
+from secrets key with PBKDF2-HMAC-SHA256 and a random salt. Encrypted file is a header line followed by
+base64 encoded salt, nonce and ciphertext, thus it could be committed to version control safely.
+
+	$HRP_SECRETS;1.0;AES256-GCM
+	<base64 of salt + nonce + ciphertext>
+
+Secrets key is loaded from key file if specified, otherwise from environment variable HRP_SECRETS_KEY,
+or the key file specified by environment variable HRP_SECRETS_KEY_FILE.
+*/
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	KeyEnv     = "HRP_SECRETS_KEY"
+	KeyFileEnv = "HRP_SECRETS_KEY_FILE"
+)
+
+const (
+	header        = "$HRP_SECRETS;1.0;AES256-GCM"
+	saltSize      = 16
+	keySize       = 32 // AES-256
+	keyIterations = 100000
+	lineWidth     = 76
+)
+
+// LoadKey loads secrets key from keyFile, or environment variables if keyFile is empty.
+func LoadKey(keyFile string) ([]byte, error) {
+	if keyFile == "" {
+		if key := strings.TrimSpace(os.Getenv(KeyEnv)); key != "" {
+			return []byte(key), nil
+		}
+		keyFile = os.Getenv(KeyFileEnv)
+	}
+	if keyFile == "" {
+		return nil, errors.Errorf("secrets key not found, set %s or %s", KeyEnv, KeyFileEnv)
+	}
+	content, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "read secrets key file failed")
+	}
+	key := bytes.TrimSpace(content)
+	if len(key) == 0 {
+		return nil, errors.Errorf("secrets key file %s is empty", keyFile)
+	}
+	return key, nil
+}
+
+// IsEncrypted reports whether data is encrypted secrets
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(header))
+}
+
+// Encrypt encrypts plaintext with key, a random salt and nonce are generated for each encryption.
+func Encrypt(plaintext, key []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "generate salt failed")
+	}
+	gcm, err := newGCM(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "generate nonce failed")
+	}
+
+	payload := append(salt, nonce...)
+	payload = gcm.Seal(payload, nonce, plaintext, []byte(header))
+	encoded := base64.StdEncoding.EncodeToString(payload)
+
+	var b strings.Builder
+	b.WriteString(header + "\n")
+	for len(encoded) > lineWidth {
+		b.WriteString(encoded[:lineWidth] + "\n")
+		encoded = encoded[lineWidth:]
+	}
+	b.WriteString(encoded + "\n")
+	return []byte(b.String()), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt with key.
+func Decrypt(data, key []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, errors.New("invalid secrets: header not found")
+	}
+	encoded := strings.Join(strings.Fields(string(data[len(header):])), "")
+	payload, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid secrets: decode base64 failed")
+	}
+	if len(payload) < saltSize {
+		return nil, errors.New("invalid secrets: payload too short")
+	}
+	salt, payload := payload[:saltSize], payload[saltSize:]
+	gcm, err := newGCM(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < gcm.NonceSize() {
+		return nil, errors.New("invalid secrets: payload too short")
+	}
+	nonce, ciphertext := payload[:gcm.NonceSize()], payload[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(header))
+	if err != nil {
+		return nil, errors.New("decrypt secrets failed, wrong key or corrupted file")
+	}
+	return plaintext, nil
+}
+
+// Unmarshal parses plaintext of secrets in YAML or JSON format, which should be a mapping of variables.
+func Unmarshal(plaintext []byte) (map[string]interface{}, error) {
+	variables := make(map[string]interface{})
+	if err := yaml.Unmarshal(plaintext, &variables); err != nil {
+		return nil, errors.Wrap(err, "secrets should be a mapping of variables in YAML or JSON format")
+	}
+	return variables, nil
+}
+
+// LoadFile decrypts secrets file with key and returns variables in it.
+func LoadFile(path string, key []byte) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read secrets file failed")
+	}
+	plaintext, err := Decrypt(data, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decrypt %s failed", path)
+	}
+	return Unmarshal(plaintext)
+}
+
+func newGCM(key, salt []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, errors.New("secrets key is empty")
+	}
+	block, err := aes.NewCipher(pbkdf2.Key(key, salt, keyIterations, keySize, sha256.New))
+	if err != nil {
+		return nil, errors.Wrap(err, "init aes cipher failed")
+	}
+	return cipher.NewGCM(block)
+}
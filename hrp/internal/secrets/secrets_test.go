@@ -0,0 +1,108 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte("username: debugtalk\npassword: p@ss$word\n")
+	key := []byte("my-secrets-key")
+
+	encrypted, err := Encrypt(plaintext, key)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.True(t, IsEncrypted(encrypted)) || !assert.NotContains(t, string(encrypted), "debugtalk") {
+		t.Fail()
+	}
+	for _, line := range strings.Split(string(encrypted), "\n") {
+		if !assert.LessOrEqual(t, len(line), lineWidth) {
+			t.Fail()
+		}
+	}
+	// random salt and nonce for each encryption
+	another, _ := Encrypt(plaintext, key)
+	if !assert.NotEqual(t, encrypted, another) {
+		t.Fail()
+	}
+
+	decrypted, err := Decrypt(encrypted, key)
+	if !assert.NoError(t, err) || !assert.Equal(t, plaintext, decrypted) {
+		t.Fail()
+	}
+	if _, err = Decrypt(encrypted, []byte("wrong-key")); !assert.Error(t, err) {
+		t.Fail()
+	}
+	if _, err = Decrypt(plaintext, key); !assert.Error(t, err) {
+		t.Fail()
+	}
+	tampered := []byte(strings.Replace(string(encrypted), "\n", "\nAAAA", 1))
+	if _, err = Decrypt(tampered, key); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "secrets.key")
+	if err := os.WriteFile(keyFile, []byte("key-from-file\n"), 0o600); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	os.Setenv(KeyEnv, "key-from-env")
+	defer os.Unsetenv(KeyEnv)
+	key, err := LoadKey(keyFile)
+	if !assert.NoError(t, err) || !assert.Equal(t, "key-from-file", string(key)) {
+		t.Fail()
+	}
+	key, err = LoadKey("")
+	if !assert.NoError(t, err) || !assert.Equal(t, "key-from-env", string(key)) {
+		t.Fail()
+	}
+
+	os.Unsetenv(KeyEnv)
+	os.Setenv(KeyFileEnv, keyFile)
+	defer os.Unsetenv(KeyFileEnv)
+	key, err = LoadKey("")
+	if !assert.NoError(t, err) || !assert.Equal(t, "key-from-file", string(key)) {
+		t.Fail()
+	}
+
+	os.Unsetenv(KeyFileEnv)
+	if _, err = LoadKey(""); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	key := []byte("my-secrets-key")
+	encrypted, err := Encrypt([]byte(`{"token": "abc", "account": {"id": 1}}`), key)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	if err := os.WriteFile(path, encrypted, 0o600); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	variables, err := LoadFile(path, key)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	expected := map[string]interface{}{
+		"token":   "abc",
+		"account": map[string]interface{}{"id": 1},
+	}
+	if !assert.Equal(t, expected, variables) {
+		t.Fail()
+	}
+
+	if _, err = Unmarshal([]byte("- a\n- b\n")); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,302 @@
+package snapshot
+
+import (
+	"bytes"
+	builtinJSON "encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/json"
+)
+
+/*
+Snapshot testing compares value with golden file stored before, golden file is pretty printed json,
+thus the differences could be reviewed in version control after updating snapshots.
+*/
+
+// Options controls how value is compared with snapshot
+type Options struct {
+	// JSON pointers of volatile values to be ignored, `*` matches any object key or array index,
+	// e.g. /id, /items/*/created_at
+	IgnorePaths []string `json:"ignore_paths,omitempty" yaml:"ignore_paths,omitempty"`
+	// compare arrays regardless of element order
+	IgnoreOrder bool `json:"ignore_order,omitempty" yaml:"ignore_order,omitempty"`
+}
+
+const (
+	KindAdded   = "added"   // value exists in actual but not in snapshot
+	KindRemoved = "removed" // value exists in snapshot but not in actual
+	KindChanged = "changed"
+)
+
+// Difference represents one difference between snapshot and actual value
+type Difference struct {
+	Path     string      `json:"path"` // JSON pointer of different value, e.g. /items/0/name
+	Kind     string      `json:"kind"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+}
+
+func (d *Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "/"
+	}
+	switch d.Kind {
+	case KindAdded:
+		return fmt.Sprintf("%s: %s %v", path, d.Kind, d.Actual)
+	case KindRemoved:
+		return fmt.Sprintf("%s: %s %v", path, d.Kind, d.Expected)
+	default:
+		return fmt.Sprintf("%s: %s from %v to %v", path, d.Kind, d.Expected, d.Actual)
+	}
+}
+
+// Load loads snapshot from golden file, numbers are kept as json.Number
+func Load(path string) (interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read snapshot file failed")
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.Wrap(err, "decode snapshot file failed")
+	}
+	return value, nil
+}
+
+// Save writes value to golden file in pretty printed json with sorted keys
+func Save(path string, value interface{}) error {
+	log.Info().Str("path", path).Msg("save snapshot")
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.Wrap(err, "create snapshot directory failed")
+	}
+	content, err := builtinJSON.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "encode snapshot failed")
+	}
+	content = append(content, '\n')
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return errors.Wrap(err, "write snapshot file failed")
+	}
+	return nil
+}
+
+// Normalize converts value to json compatible types, numbers are converted to json.Number
+func Normalize(value interface{}) (interface{}, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode value failed")
+	}
+	var normalized interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, errors.Wrap(err, "decode value failed")
+	}
+	return normalized, nil
+}
+
+// Compare compares actual value with snapshot, both values should be normalized,
+// differences are sorted by path.
+func Compare(expected, actual interface{}, opts *Options) []*Difference {
+	if opts == nil {
+		opts = &Options{}
+	}
+	var patterns [][]string
+	for _, path := range opts.IgnorePaths {
+		patterns = append(patterns, splitPointer(path))
+	}
+	expected = prune(expected, nil, patterns)
+	actual = prune(actual, nil, patterns)
+	if opts.IgnoreOrder {
+		expected = sortArrays(expected)
+		actual = sortArrays(actual)
+	}
+
+	var differences []*Difference
+	diff("", expected, actual, &differences)
+	return differences
+}
+
+func diff(path string, expected, actual interface{}, differences *[]*Difference) {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool)
+		for key := range e {
+			keys[key] = true
+		}
+		for key := range a {
+			keys[key] = true
+		}
+		var sortedKeys []string
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+		for _, key := range sortedKeys {
+			childPath := path + "/" + escapeToken(key)
+			ev, inExpected := e[key]
+			av, inActual := a[key]
+			switch {
+			case !inActual:
+				*differences = append(*differences, &Difference{Path: childPath, Kind: KindRemoved, Expected: ev})
+			case !inExpected:
+				*differences = append(*differences, &Difference{Path: childPath, Kind: KindAdded, Actual: av})
+			default:
+				diff(childPath, ev, av, differences)
+			}
+		}
+		return
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(e) || i < len(a); i++ {
+			childPath := fmt.Sprintf("%s/%d", path, i)
+			switch {
+			case i >= len(a):
+				*differences = append(*differences, &Difference{Path: childPath, Kind: KindRemoved, Expected: e[i]})
+			case i >= len(e):
+				*differences = append(*differences, &Difference{Path: childPath, Kind: KindAdded, Actual: a[i]})
+			default:
+				diff(childPath, e[i], a[i], differences)
+			}
+		}
+		return
+	}
+
+	if !equal(expected, actual) {
+		*differences = append(*differences, &Difference{Path: path, Kind: KindChanged, Expected: expected, Actual: actual})
+	}
+}
+
+// equal compares scalar values, numbers are compared by value, e.g. 1 equals 1.0
+func equal(expected, actual interface{}) bool {
+	en, ok1 := expected.(builtinJSON.Number)
+	an, ok2 := actual.(builtinJSON.Number)
+	if ok1 && ok2 {
+		if en == an {
+			return true
+		}
+		ef, err1 := en.Float64()
+		af, err2 := an.Float64()
+		return err1 == nil && err2 == nil && ef == af
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+// prune removes values matching ignored path patterns
+func prune(value interface{}, tokens []string, patterns [][]string) interface{} {
+	if len(patterns) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		pruned := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			childTokens := append(append([]string{}, tokens...), key)
+			if matchAny(childTokens, patterns) {
+				continue
+			}
+			pruned[key] = prune(child, childTokens, patterns)
+		}
+		return pruned
+	case []interface{}:
+		pruned := make([]interface{}, 0, len(v))
+		for i, child := range v {
+			childTokens := append(append([]string{}, tokens...), fmt.Sprint(i))
+			if matchAny(childTokens, patterns) {
+				continue
+			}
+			pruned = append(pruned, prune(child, childTokens, patterns))
+		}
+		return pruned
+	default:
+		return value
+	}
+}
+
+func matchAny(tokens []string, patterns [][]string) bool {
+	for _, pattern := range patterns {
+		if len(pattern) != len(tokens) {
+			continue
+		}
+		matched := true
+		for i, token := range pattern {
+			if token != "*" && token != tokens[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sortArrays sorts array elements recursively by their canonical json encoding
+func sortArrays(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		sorted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			sorted[key] = sortArrays(child)
+		}
+		return sorted
+	case []interface{}:
+		sorted := make([]interface{}, len(v))
+		keys := make([]string, len(v))
+		for i, child := range v {
+			sorted[i] = sortArrays(child)
+		}
+		indexes := make([]int, len(v))
+		for i := range sorted {
+			indexes[i] = i
+			content, _ := builtinJSON.Marshal(sorted[i]) // keys of map are sorted by encoding/json
+			keys[i] = string(content)
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return keys[indexes[i]] < keys[indexes[j]]
+		})
+		result := make([]interface{}, len(v))
+		for i, index := range indexes {
+			result[i] = sorted[index]
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// splitPointer splits JSON pointer into unescaped reference tokens, e.g. /a~1b/0 => [a/b 0]
+func splitPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return []string{}
+	}
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
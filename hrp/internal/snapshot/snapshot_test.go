@@ -0,0 +1,105 @@
+package snapshot
+
+import (
+	builtinJSON "encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustNormalize(t *testing.T, value interface{}) interface{} {
+	normalized, err := Normalize(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return normalized
+}
+
+func TestCompare(t *testing.T) {
+	expected := mustNormalize(t, map[string]interface{}{
+		"id":    1,
+		"name":  "doggie",
+		"price": 1.5,
+		"tags":  []interface{}{"a", "b"},
+		"owner": map[string]interface{}{"id": 10, "a/b": "x"},
+	})
+	actual := mustNormalize(t, map[string]interface{}{
+		"id":    2,
+		"name":  "doggie",
+		"price": 1.50,
+		"tags":  []interface{}{"a", "b", "c"},
+		"owner": map[string]interface{}{"id": 10, "a/b": "y", "age": 3},
+	})
+	differences := Compare(expected, actual, nil)
+	expectedDifferences := []*Difference{
+		{Path: "/id", Kind: KindChanged, Expected: builtinJSON.Number("1"), Actual: builtinJSON.Number("2")},
+		{Path: "/owner/a~1b", Kind: KindChanged, Expected: "x", Actual: "y"},
+		{Path: "/owner/age", Kind: KindAdded, Actual: builtinJSON.Number("3")},
+		{Path: "/tags/2", Kind: KindAdded, Actual: "c"},
+	}
+	if !assert.Equal(t, expectedDifferences, differences) {
+		t.Fail()
+	}
+
+	// ignore volatile values
+	differences = Compare(expected, actual, &Options{
+		IgnorePaths: []string{"/id", "/owner/*", "/tags/2"},
+	})
+	if !assert.Empty(t, differences) {
+		t.Fail()
+	}
+}
+
+func TestCompareIgnoreOrder(t *testing.T) {
+	expected := mustNormalize(t, []interface{}{
+		map[string]interface{}{"id": 1, "tags": []interface{}{"x", "y"}, "ts": 100},
+		map[string]interface{}{"id": 2, "tags": []interface{}{}, "ts": 200},
+	})
+	actual := mustNormalize(t, []interface{}{
+		map[string]interface{}{"id": 2, "tags": []interface{}{}, "ts": 201},
+		map[string]interface{}{"id": 1, "tags": []interface{}{"y", "x"}, "ts": 101},
+	})
+	if !assert.Len(t, Compare(expected, actual, nil), 8) {
+		t.Fail()
+	}
+	// ignored paths are removed before sorting
+	differences := Compare(expected, actual, &Options{IgnorePaths: []string{"/*/ts"}, IgnoreOrder: true})
+	if !assert.Empty(t, differences) {
+		t.Fail()
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots", "pet.json")
+	value := mustNormalize(t, map[string]interface{}{"id": 12345678901234, "price": 0.1})
+	if err := Save(path, value); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	loaded, err := Load(path)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Empty(t, Compare(loaded, value, nil)) {
+		t.Fail()
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "not_exist.json")); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestDifferenceString(t *testing.T) {
+	testData := []struct {
+		difference *Difference
+		expected   string
+	}{
+		{&Difference{Path: "/a", Kind: KindAdded, Actual: 1}, "/a: added 1"},
+		{&Difference{Path: "/a", Kind: KindRemoved, Expected: "x"}, "/a: removed x"},
+		{&Difference{Path: "", Kind: KindChanged, Expected: 1, Actual: 2}, "/: changed from 1 to 2"},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, data.difference.String()) {
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package swagger2case
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp"
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+	"github.com/httprunner/httprunner/hrp/internal/har2case"
+	"github.com/httprunner/httprunner/hrp/internal/openapi"
+	"github.com/httprunner/httprunner/hrp/internal/sdk"
+)
+
+const (
+	apiDir      = "api"
+	testcaseDir = "testcases"
+	defaultTag  = "default"
+	// manifestFile records checksums of generated files, used to detect hand-edited files
+	manifestFile = ".swagger2case.json"
+)
+
+var (
+	regexPathParam      = regexp.MustCompile(`\{([^{}]+)\}`)
+	regexInvalidVarChar = regexp.MustCompile(`\W`)
+	regexUnderscores    = regexp.MustCompile(`_{2,}`)
+)
+
+func NewSwagger(path string) *swagger {
+	return &swagger{
+		path: path,
+	}
+}
+
+// swagger converts OpenAPI 3 document to api files and smoke testcases,
+// one api file per operation and one smoke testcase per tag.
+type swagger struct {
+	path      string
+	outputDir string
+	manifest  map[string]string // relative file path => sha256 of generated content
+	skipped   []string          // hand-edited files not overwritten
+}
+
+func (s *swagger) SetOutputDir(dir string) {
+	log.Info().Str("dir", dir).Msg("set output directory")
+	s.outputDir = dir
+}
+
+// Skipped returns hand-edited files which are kept unchanged in last generation
+func (s *swagger) Skipped() []string {
+	return s.skipped
+}
+
+func (s *swagger) GenJSON() (outputPaths []string, err error) {
+	event := sdk.EventTracking{
+		Category: "ConvertTests",
+		Action:   "hrp swagger2case --to-json",
+	}
+	// report start event
+	go sdk.SendEvent(event)
+	// report running timing event
+	defer sdk.SendEvent(event.StartTiming("execution"))
+
+	return s.gen(har2case.SuffixJSON)
+}
+
+func (s *swagger) GenYAML() (outputPaths []string, err error) {
+	event := sdk.EventTracking{
+		Category: "ConvertTests",
+		Action:   "hrp swagger2case --to-yaml",
+	}
+	// report start event
+	go sdk.SendEvent(event)
+	// report running timing event
+	defer sdk.SendEvent(event.StartTiming("execution"))
+
+	return s.gen(har2case.SuffixYAML)
+}
+
+func (s *swagger) gen(suffix string) (outputPaths []string, err error) {
+	doc, err := openapi.Load(s.path)
+	if err != nil {
+		return nil, err
+	}
+	operations := doc.Operations()
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("no operation found in openapi document %s", s.path)
+	}
+
+	outputDir := s.outputDir
+	if outputDir == "" {
+		outputDir = filepath.Dir(s.path)
+	}
+	if err := s.loadManifest(outputDir); err != nil {
+		return nil, err
+	}
+	s.skipped = nil
+
+	// generate api files, api paths are relative to output directory,
+	// which should be the project root directory when running smoke testcases.
+	tagSteps := make(map[string][]*hrp.TStep)
+	apiNames := make(map[string]bool)
+	for _, mo := range operations {
+		api := makeAPI(doc, mo)
+		apiPath := filepath.ToSlash(filepath.Join(apiDir, uniqueName(apiFileName(mo), apiNames)+suffix))
+		written, err := s.dumpFile(api, outputDir, apiPath)
+		if err != nil {
+			return nil, err
+		}
+		if written {
+			outputPaths = append(outputPaths, filepath.Join(outputDir, apiPath))
+		}
+
+		step := &hrp.TStep{
+			Name: api.Name,
+			API:  apiPath,
+			Validators: []interface{}{
+				hrp.Validator{Check: "status_code", Assert: "equals", Expect: expectedStatusCode(mo.Operation)},
+			},
+		}
+		tags := mo.Operation.Tags
+		if len(tags) == 0 {
+			tags = []string{defaultTag}
+		}
+		for _, tag := range tags {
+			tagSteps[tag] = append(tagSteps[tag], step)
+		}
+	}
+
+	// generate smoke testcase for each tag
+	var tags []string
+	for tag := range tagSteps {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	var baseURL string
+	if len(doc.Servers) > 0 {
+		baseURL = doc.Servers[0].URL
+	}
+	for _, tag := range tags {
+		tCase := &hrp.TCase{
+			Config:    hrp.NewConfig(fmt.Sprintf("smoke testcase for %s", tag)).SetBaseURL(baseURL),
+			TestSteps: tagSteps[tag],
+		}
+		casePath := filepath.ToSlash(filepath.Join(testcaseDir, sanitizeName(tag)+"_smoke"+suffix))
+		written, err := s.dumpFile(tCase, outputDir, casePath)
+		if err != nil {
+			return nil, err
+		}
+		if written {
+			outputPaths = append(outputPaths, filepath.Join(outputDir, casePath))
+		}
+	}
+
+	if err := builtin.Dump2JSON(s.manifest, filepath.Join(outputDir, manifestFile)); err != nil {
+		return nil, err
+	}
+	if len(s.skipped) > 0 {
+		log.Warn().Strs("files", s.skipped).Msg("hand-edited files are kept unchanged")
+	}
+	return outputPaths, nil
+}
+
+// makeAPI converts operation to api, parameters are converted to variables with example values
+func makeAPI(doc *openapi.Document, mo *openapi.MethodOperation) *hrp.API {
+	name := mo.Operation.Summary
+	if name == "" {
+		name = mo.Operation.OperationID
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s %s", mo.Method, mo.Path)
+	}
+	log.Info().Str("method", mo.Method).Str("path", mo.Path).Msg("convert api")
+
+	request := &hrp.Request{
+		Method: mo.Method,
+		URL: regexPathParam.ReplaceAllStringFunc(mo.Path, func(s string) string {
+			return fmt.Sprintf("${%s}", variableName(s[1:len(s)-1]))
+		}),
+	}
+	variables := make(map[string]interface{})
+	for _, param := range doc.Parameters(mo) {
+		name := variableName(param.Name)
+		example := param.Example
+		if example == nil {
+			example = doc.GenExample(param.Schema)
+		}
+		variables[name] = example
+
+		switch param.In {
+		case "query":
+			if request.Params == nil {
+				request.Params = make(map[string]interface{})
+			}
+			request.Params[param.Name] = "$" + name
+		case "header":
+			if request.Headers == nil {
+				request.Headers = make(map[string]string)
+			}
+			request.Headers[param.Name] = "$" + name
+		case "cookie":
+			if request.Cookies == nil {
+				request.Cookies = make(map[string]string)
+			}
+			request.Cookies[param.Name] = "$" + name
+		}
+	}
+
+	if body := doc.RequestBody(mo); body != nil {
+		contentType, media := selectMediaType(body.Content)
+		if media != nil {
+			if request.Headers == nil {
+				request.Headers = make(map[string]string)
+			}
+			request.Headers["Content-Type"] = contentType
+			request.Body = mediaExample(doc, media)
+		}
+	}
+
+	api := &hrp.API{
+		Name:    name,
+		Request: request,
+	}
+	if len(variables) > 0 {
+		api.Variables = variables
+	}
+	return api
+}
+
+// selectMediaType prefers json content, then form content
+func selectMediaType(content map[string]*openapi.MediaType) (string, *openapi.MediaType) {
+	var contentTypes []string
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	for _, prefix := range []string{"application/json", "application/x-www-form-urlencoded", ""} {
+		for _, contentType := range contentTypes {
+			if strings.HasPrefix(contentType, prefix) {
+				return contentType, content[contentType]
+			}
+		}
+	}
+	return "", nil
+}
+
+func mediaExample(doc *openapi.Document, media *openapi.MediaType) interface{} {
+	if media.Example != nil {
+		return media.Example
+	}
+	var names []string
+	for name := range media.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if example := media.Examples[name]; example != nil && example.Value != nil {
+			return example.Value
+		}
+	}
+	return doc.GenExample(media.Schema)
+}
+
+// expectedStatusCode returns the minimal 2xx status code declared, default to 200
+func expectedStatusCode(operation *openapi.Operation) int {
+	expected := 0
+	for code := range operation.Responses {
+		statusCode, err := strconv.Atoi(code)
+		if err != nil || statusCode < 200 || statusCode >= 300 {
+			continue
+		}
+		if expected == 0 || statusCode < expected {
+			expected = statusCode
+		}
+	}
+	if expected == 0 {
+		return 200
+	}
+	return expected
+}
+
+// dumpFile writes generated content to relative path in output directory,
+// existing file is overwritten only if it has not been modified since last generation.
+func (s *swagger) dumpFile(data interface{}, outputDir, relPath string) (bool, error) {
+	path := filepath.Join(outputDir, relPath)
+	if builtin.IsFilePathExists(path) {
+		checksum, err := fileChecksum(path)
+		if err != nil {
+			return false, err
+		}
+		if recorded, ok := s.manifest[relPath]; !ok || recorded != checksum {
+			log.Warn().Str("path", path).Msg("file has been modified by hand, skip overwriting")
+			s.skipped = append(s.skipped, path)
+			return false, nil
+		}
+	}
+
+	if err := builtin.EnsureFolderExists(filepath.Dir(path)); err != nil {
+		return false, err
+	}
+	var err error
+	switch filepath.Ext(path) {
+	case har2case.SuffixJSON:
+		err = builtin.Dump2JSON(data, path)
+	default:
+		err = builtin.Dump2YAML(data, path)
+	}
+	if err != nil {
+		return false, err
+	}
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return false, err
+	}
+	s.manifest[relPath] = checksum
+	return true, nil
+}
+
+func (s *swagger) loadManifest(outputDir string) error {
+	s.manifest = make(map[string]string)
+	path := filepath.Join(outputDir, manifestFile)
+	if !builtin.IsFilePathExists(path) {
+		return nil
+	}
+	if err := builtin.LoadFile(path, &s.manifest); err != nil {
+		return errors.Wrap(err, "load swagger2case manifest failed")
+	}
+	return nil
+}
+
+func fileChecksum(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "read file failed")
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// apiFileName returns operationId if specified, otherwise combines method and path,
+// e.g. GET /pets/{petId} => get_pets_petid
+func apiFileName(mo *openapi.MethodOperation) string {
+	if mo.Operation.OperationID != "" {
+		return sanitizeName(mo.Operation.OperationID)
+	}
+	return sanitizeName(strings.ToLower(mo.Method + "_" + mo.Path))
+}
+
+// uniqueName appends index suffix if name has been used
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// variableName replaces characters not allowed in variable name, e.g. X-Request-Id => X_Request_Id
+func variableName(name string) string {
+	return regexInvalidVarChar.ReplaceAllString(name, "_")
+}
+
+// sanitizeName replaces characters not suitable for file name
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	name = strings.Trim(regexUnderscores.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
@@ -0,0 +1,119 @@
+package swagger2case
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/httprunner/httprunner/hrp"
+)
+
+var petstorePath = "../../../examples/data/openapi/petstore.yaml"
+
+func TestGenJSON(t *testing.T) {
+	outputDir := t.TempDir()
+	s := NewSwagger(petstorePath)
+	s.SetOutputDir(outputDir)
+	outputPaths, err := s.GenJSON()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Len(t, outputPaths, 9) {
+		t.Fail()
+	}
+
+	// generated api could be loaded
+	apiPath := hrp.APIPath(filepath.Join(outputDir, "api", "listPets.json"))
+	api, err := apiPath.ToAPI()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, "List all pets", api.Name) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{"limit": "$limit"}, api.Request.Params) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "$X_Request_ID", api.Request.Headers["X-Request-ID"]) {
+		t.Fail()
+	}
+
+	// generated smoke testcase references api files relative to output directory
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	if err := os.Chdir(outputDir); err != nil {
+		t.Fatal(err)
+	}
+	casePath := hrp.TestCasePath(filepath.Join("testcases", "pets_smoke.json"))
+	testCase, err := casePath.ToTestCase()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Len(t, testCase.TestSteps, 3) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "https://petstore.example.com/v1", testCase.Config.BaseURL) {
+		t.Fail()
+	}
+	step := testCase.TestSteps[1].ToStruct()
+	if !assert.Equal(t, "POST", step.API.(*hrp.API).Request.Method) {
+		t.Fail()
+	}
+}
+
+func TestGenKeepHandEditedFiles(t *testing.T) {
+	outputDir := t.TempDir()
+	s := NewSwagger(petstorePath)
+	s.SetOutputDir(outputDir)
+	if _, err := s.GenYAML(); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	// modify generated api file by hand
+	editedPath := filepath.Join(outputDir, "api", "health.yaml")
+	edited := []byte("name: health check edited by hand\nrequest:\n    method: GET\n    url: /health\n")
+	if err := os.WriteFile(editedPath, edited, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// remove generated api file
+	removedPath := filepath.Join(outputDir, "api", "listPets.yaml")
+	if err := os.Remove(removedPath); err != nil {
+		t.Fatal(err)
+	}
+
+	s = NewSwagger(petstorePath)
+	s.SetOutputDir(outputDir)
+	outputPaths, err := s.GenYAML()
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, []string{editedPath}, s.Skipped()) {
+		t.Fail()
+	}
+	if !assert.Len(t, outputPaths, 8) || !assert.Contains(t, outputPaths, removedPath) {
+		t.Fail()
+	}
+	content, _ := os.ReadFile(editedPath)
+	if !assert.Equal(t, edited, content) {
+		t.Fail()
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	testData := []struct {
+		name     string
+		expected string
+	}{
+		{"listPets", "listPets"},
+		{"get_/pets/{petId}", "get_pets_petId"},
+		{"pet store", "pet_store"},
+		{"/", "unnamed"},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, sanitizeName(data.name)) {
+			t.Fail()
+		}
+	}
+}
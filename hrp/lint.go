@@ -0,0 +1,885 @@
+package hrp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/httprunner/funplugin"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+	"github.com/httprunner/httprunner/hrp/internal/position"
+)
+
+// LintIssue represents one problem of testcase file found by Lint
+type LintIssue struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`        // error or warning
+	Rule     string `json:"rule"`            // e.g. unknown-field, undefined-variable
+	Field    string `json:"field,omitempty"` // field path, e.g. teststeps[0].request.url
+	Message  string `json:"message"`
+}
+
+func (issue *LintIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]",
+		issue.Path, issue.Line, issue.Column, issue.Severity, issue.Message, issue.Rule)
+}
+
+const (
+	lintError   = "error"
+	lintWarning = "warning"
+)
+
+const (
+	ruleSyntax            = "syntax"              // invalid JSON/YAML document or field type
+	ruleUnknownField      = "unknown-field"       // field not defined in testcase format
+	ruleInvalidFormat     = "invalid-format"      // invalid teststep, validator or extractor
+	ruleReferenceNotFound = "reference-not-found" // referenced api, testcase or variables file not found
+	ruleCircularReference = "circular-reference"  // testcase references itself directly or indirectly
+	ruleUndefinedVariable = "undefined-variable"  // variable not defined or extracted before use
+	ruleUnknownAssertion  = "unknown-assertion"   // assertion not found in builtin assertions or plugin
+	ruleUnknownFunction   = "unknown-function"    // function not found in builtin functions or plugin
+	rulePlugin            = "plugin"              // plugin failed to init, plugin functions are not checked
+	ruleSecrets           = "secrets"             // secrets file failed to load, variables in it are unknown
+)
+
+// variables injected by runner when running request or socket step
+var lintStepVariables = []string{"hrp_step_name", "hrp_step_request", "hrp_step_response"}
+
+// Lint validates testcase files or folders strictly without running them. Unknown fields, referenced
+// api/testcase not found, variables not defined or extracted before use, and assertions or functions
+// not found in builtin or plugin are reported with positions, sorted by path, line and column.
+// Files in folders are linted only if they contain teststeps, and referenced api and testcase files
+// are linted recursively.
+func Lint(paths ...string) ([]*LintIssue, error) {
+	l := newLinter()
+	defer l.quit()
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "lint testcase failed")
+		}
+		if !info.IsDir() {
+			l.lintPath(path, true)
+			continue
+		}
+		err = fs.WalkDir(os.DirFS(path), ".", func(name string, dir fs.DirEntry, e error) error {
+			if e != nil {
+				return e
+			}
+			if dir.IsDir() {
+				if name != "." && strings.HasPrefix(name, ".") {
+					// skip hidden folders
+					return fs.SkipDir
+				}
+				return nil
+			}
+			ext := filepath.Ext(name)
+			if ext != ".yml" && ext != ".yaml" && ext != ".json" {
+				return nil
+			}
+			l.lintPath(filepath.Join(path, name), false)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "lint testcases folder failed")
+		}
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.issues, nil
+}
+
+type linter struct {
+	runner    *HRPRunner // loads secrets like running testcases
+	issues    []*LintIssue
+	reported  map[string]bool        // issues are deduplicated as referenced files may be linted more than once
+	files     map[string]*lintFile   // loaded files by absolute path
+	plugins   map[string]*lintPlugin // initialized plugins by plugin path
+	stack     []string               // absolute paths of testcases being linted to detect circular references
+	functions map[string]interface{} // random builtin functions of virtual user
+}
+
+func newLinter() *linter {
+	return &linter{
+		runner:    NewRunner(nil),
+		reported:  make(map[string]bool),
+		files:     make(map[string]*lintFile),
+		plugins:   make(map[string]*lintPlugin),
+		functions: builtin.NewRandomFunctions(nil),
+	}
+}
+
+func (l *linter) quit() {
+	for _, p := range l.plugins {
+		if p.plugin != nil {
+			p.plugin.Quit()
+		}
+	}
+}
+
+// lintFile is testcase or api file decoded as generic data, which is used to find unknown fields and
+// variable references, and positions of fields are used to report issues
+type lintFile struct {
+	path      string // path shown in issues, relative to current working directory if possible
+	ext       string
+	content   []byte
+	data      interface{} // nil if failed to parse
+	positions position.Positions
+}
+
+func (f *lintFile) mapping() map[string]interface{} {
+	m, _ := f.data.(map[string]interface{})
+	return m
+}
+
+// loadFile loads and parses file once, syntax errors are reported and data is nil if file could not be parsed
+func (l *linter) loadFile(path string) *lintFile {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+	if f, ok := l.files[absPath]; ok {
+		return f
+	}
+
+	f := &lintFile{
+		path:      lintDisplayPath(absPath),
+		ext:       strings.ToLower(filepath.Ext(path)),
+		positions: position.Positions{},
+	}
+	l.files[absPath] = f
+	f.content, err = os.ReadFile(path)
+	if err != nil {
+		l.report(f, "", lintError, ruleReferenceNotFound, "read file failed: %v", err)
+		return f
+	}
+
+	var data interface{}
+	switch f.ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(f.content))
+		decoder.UseNumber()
+		err = decoder.Decode(&data)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(f.content, &data)
+	default:
+		l.report(f, "", lintError, ruleSyntax, "unsupported file extension %s, should be json, yaml or yml", f.ext)
+		return f
+	}
+	if err != nil {
+		l.reportDecodeError(f, err)
+		return f
+	}
+	f.data = data
+	if positions, err := position.Parse(f.content, f.ext); err == nil {
+		f.positions = positions
+	}
+	return f
+}
+
+var (
+	regexYAMLErrorLine  = regexp.MustCompile(`line (\d+): (.*)`)
+	regexJSONFieldIndex = regexp.MustCompile(`\.(\d+)\b`)
+)
+
+// reportDecodeError reports syntax or type errors of decoding file, with positions parsed from errors
+func (l *linter) reportDecodeError(f *lintFile, err error) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var yamlTypeErr *yaml.TypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// syntax error occurs after reading the invalid character
+		l.reportAt(f, position.Offset(f.content, int(syntaxErr.Offset)-1), "",
+			lintError, ruleSyntax, "invalid json: %v", syntaxErr)
+	case errors.As(err, &typeErr):
+		// field of type error is like teststeps.0.name, list indexes are not included in early go versions
+		field := regexJSONFieldIndex.ReplaceAllString(typeErr.Field, "[$1]")
+		pos, ok := f.positions[field]
+		if !ok {
+			pos = position.Offset(f.content, int(typeErr.Offset))
+		}
+		l.reportAt(f, pos, field, lintError, ruleSyntax,
+			"field %s should be %v, got %s", field, typeErr.Type, typeErr.Value)
+	case errors.As(err, &yamlTypeErr):
+		for _, msg := range yamlTypeErr.Errors {
+			l.reportYAMLError(f, msg)
+		}
+	case f.ext == ".json":
+		l.report(f, "", lintError, ruleSyntax, "invalid json: %v", err)
+	default:
+		l.reportYAMLError(f, err.Error())
+	}
+}
+
+func (l *linter) reportYAMLError(f *lintFile, msg string) {
+	pos := position.Position{Line: 1, Column: 1}
+	if matched := regexYAMLErrorLine.FindStringSubmatch(msg); matched != nil {
+		pos.Line, _ = strconv.Atoi(matched[1])
+		msg = matched[2]
+	}
+	l.reportAt(f, pos, "", lintError, ruleSyntax, "invalid yaml: %s", msg)
+}
+
+// decode decodes file into typed struct to find fields with invalid types
+func (l *linter) decode(f *lintFile, v interface{}) {
+	var err error
+	if f.ext == ".json" {
+		err = json.Unmarshal(f.content, v)
+	} else {
+		err = yaml.Unmarshal(f.content, v)
+	}
+	if err != nil {
+		l.reportDecodeError(f, err)
+	}
+}
+
+func (l *linter) report(f *lintFile, field, severity, rule, format string, args ...interface{}) {
+	l.reportAt(f, f.positions.Lookup(field), field, severity, rule, format, args...)
+}
+
+func (l *linter) reportAt(f *lintFile, pos position.Position, field, severity, rule, format string,
+	args ...interface{}) {
+	issue := &LintIssue{
+		Path:     f.path,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Severity: severity,
+		Rule:     rule,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	key := fmt.Sprintf("%s:%d:%d:%s:%s", issue.Path, issue.Line, issue.Column, issue.Rule, issue.Message)
+	if l.reported[key] {
+		return
+	}
+	l.reported[key] = true
+	l.issues = append(l.issues, issue)
+}
+
+// lintPath lints file specified by user or found in folder, files in folder without teststeps are skipped,
+// and file specified by user with request but without teststeps is linted as api
+func (l *linter) lintPath(path string, specified bool) {
+	f := l.loadFile(path)
+	data := f.mapping()
+	if data == nil {
+		if f.data != nil && specified {
+			l.report(f, "", lintError, ruleInvalidFormat, "testcase should be a mapping of config and teststeps")
+		}
+		return
+	}
+	if _, ok := data["teststeps"]; ok {
+		l.lintTestCase(path, nil)
+		return
+	}
+	if !specified {
+		return
+	}
+	if _, ok := data["request"]; ok {
+		l.lintAPI(path, nil)
+		return
+	}
+	l.lintTestCase(path, nil)
+}
+
+// lintTestCase lints testcase with variables inherited from caller, names of exported variables are returned
+func (l *linter) lintTestCase(path string, inherited map[string]bool) []string {
+	f := l.loadFile(path)
+	data := f.mapping()
+	if data == nil {
+		return nil
+	}
+	absPath, _ := filepath.Abs(path)
+	l.stack = append(l.stack, absPath)
+	defer func() {
+		l.stack = l.stack[:len(l.stack)-1]
+	}()
+
+	l.checkFields(f, data, reflect.TypeOf(TCase{}), "")
+	tc := &TCase{}
+	l.decode(f, tc)
+	config, ok := data["config"].(map[string]interface{})
+	if !ok || tc.Config == nil {
+		l.report(f, "config", lintError, ruleInvalidFormat, "config is required and should be a mapping")
+		tc.Config = &TConfig{}
+	}
+	tc.Config.Path = path
+	teststeps, ok := data["teststeps"].([]interface{})
+	if !ok {
+		l.report(f, "teststeps", lintError, ruleInvalidFormat, "teststeps is required and should be a list")
+	}
+	plugin := l.loadPlugin(f)
+
+	// variables defined in config
+	scope := mergeNames(inherited, mapKeys(config["variables"]), parametersNames(config["parameters"]))
+	for i, file := range tc.Config.VariablesFiles {
+		field := position.Join("config.variables_files", i)
+		variablesPath, err := locateVariablesFile(file, path)
+		if err != nil {
+			l.report(f, field, lintError, ruleReferenceNotFound, "%v", err)
+			continue
+		}
+		var variables map[string]interface{}
+		if err := builtin.LoadFile(variablesPath, &variables); err != nil {
+			l.report(f, field, lintError, ruleSyntax, "load variables file %s failed: %v", file, err)
+			continue
+		}
+		scope = mergeNames(scope, mapKeys(variables))
+	}
+	// undefined variables may be defined in secrets file which could not be decrypted
+	severity := lintError
+	if tc.Config.SecretsFile != "" {
+		secretVariables, err := l.runner.loadSecrets(tc.Config)
+		if err != nil {
+			l.report(f, "config.secrets_file", lintWarning, ruleSecrets,
+				"load secrets file failed, undefined variables are reported as warnings: %v", err)
+			severity = lintWarning
+		}
+		scope = mergeNames(scope, mapKeys(secretVariables))
+	}
+	for _, key := range []string{"name", "base_url", "headers", "variables", "parameters"} {
+		l.checkValue(f, plugin, config[key], position.Join("config", key), scope, severity)
+	}
+
+	// variables extracted or exported by previous steps
+	session := map[string]bool{}
+	for i, item := range teststeps {
+		stepPath := position.Join("teststeps", i)
+		step, ok := item.(map[string]interface{})
+		if !ok {
+			l.report(f, stepPath, lintError, ruleInvalidFormat, "teststep should be a mapping")
+			continue
+		}
+		stepScope := mergeNames(scope, session, mapKeys(step["variables"]), parametersNames(step["parameters"]))
+		l.checkValue(f, plugin, step["variables"], position.Join(stepPath, "variables"), stepScope, severity)
+		l.checkValue(f, plugin, step["parameters"], position.Join(stepPath, "parameters"), stepScope, severity)
+
+		// step variables are passed to referenced api and testcase
+		var referenced []string
+		switch {
+		case step["api"] != nil:
+			if apiPath := l.resolveReference(f, step, stepPath, "api"); apiPath != "" {
+				referenced = l.lintAPI(apiPath, stepScope)
+			}
+		case step["testcase"] != nil:
+			casePath := l.resolveReference(f, step, stepPath, "testcase")
+			if casePath == "" {
+				break
+			}
+			absCasePath, _ := filepath.Abs(casePath)
+			if stringInSlice(absCasePath, l.stack) {
+				l.report(f, position.Join(stepPath, "testcase"), lintError, ruleCircularReference,
+					"testcase %s is referenced circularly", step["testcase"])
+				break
+			}
+			referenced = l.lintTestCase(casePath, stepScope)
+		case step["request"] == nil && step["socket"] == nil && step["transaction"] == nil &&
+			step["rendezvous"] == nil && step["think_time"] == nil:
+			l.report(f, stepPath, lintError, ruleInvalidFormat,
+				"teststep should contain one of request, socket, api, testcase, transaction, rendezvous or think_time")
+		}
+
+		extracted := append(mapKeys(step["extract"]), mapKeys(step["extractors"])...)
+		stepScope = mergeNames(stepScope, lintStepVariables, extracted)
+		for _, key := range []string{"request", "socket", "setup_hooks", "teardown_hooks"} {
+			l.checkValue(f, plugin, step[key], position.Join(stepPath, key), stepScope, severity)
+		}
+		l.checkValidators(f, plugin, step["validate"], position.Join(stepPath, "validate"), stepScope, severity)
+		l.checkExtract(f, step["extract"], position.Join(stepPath, "extract"))
+		l.checkExtractors(f, step["extractors"], position.Join(stepPath, "extractors"))
+
+		session = mergeNames(session, referenced, extracted, listStrings(step["export"]))
+	}
+	return listStrings(config["export"])
+}
+
+// lintAPI lints api with variables of caller, names of extracted variables are returned
+func (l *linter) lintAPI(path string, inherited map[string]bool) []string {
+	f := l.loadFile(path)
+	data := f.mapping()
+	if data == nil {
+		if f.data != nil {
+			l.report(f, "", lintError, ruleInvalidFormat, "api should be a mapping")
+		}
+		return nil
+	}
+
+	l.checkFields(f, data, reflect.TypeOf(API{}), "")
+	l.decode(f, &API{})
+	if _, ok := data["request"]; !ok {
+		l.report(f, "", lintError, ruleInvalidFormat, "request is required for api")
+	}
+	plugin := l.loadPlugin(f)
+
+	extracted := append(mapKeys(data["extract"]), mapKeys(data["extractors"])...)
+	scope := mergeNames(inherited, mapKeys(data["variables"]), lintStepVariables, extracted)
+	for _, key := range []string{"variables", "request", "setup_hooks", "teardown_hooks"} {
+		l.checkValue(f, plugin, data[key], key, scope, lintError)
+	}
+	l.checkValidators(f, plugin, data["validate"], "validate", scope, lintError)
+	l.checkExtract(f, data["extract"], "extract")
+	l.checkExtractors(f, data["extractors"], "extractors")
+	return extracted
+}
+
+// resolveReference locates referenced api or testcase in project root directory,
+// empty path is returned if not found
+func (l *linter) resolveReference(f *lintFile, step map[string]interface{}, stepPath, key string) string {
+	field := position.Join(stepPath, key)
+	ref, ok := step[key].(string)
+	if !ok {
+		l.report(f, field, lintError, ruleInvalidFormat, "referenced %s path should be string, got %v", key, step[key])
+		return ""
+	}
+	projectRootDir, err := getProjectRootDirPath(f.path)
+	if err != nil {
+		l.report(f, field, lintError, ruleReferenceNotFound, "failed to get project root dir: %v", err)
+		return ""
+	}
+	path := filepath.Join(projectRootDir, ref)
+	if _, err := os.Stat(path); err != nil {
+		l.report(f, field, lintError, ruleReferenceNotFound,
+			"referenced %s %s not found in project root directory %s", key, ref, projectRootDir)
+		return ""
+	}
+	return path
+}
+
+// checkFields reports fields of data not defined in json tags of struct type recursively,
+// fields of interface{} type are not checked
+func (l *linter) checkFields(f *lintFile, data interface{}, typ reflect.Type, path string) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		m, ok := data.(map[string]interface{})
+		if !ok {
+			// invalid type is reported by decoding
+			return
+		}
+		fields := jsonFields(typ)
+		for key, value := range m {
+			field, ok := fields[key]
+			if !ok {
+				l.reportUnknownField(f, position.Join(path, key), key, mapKeys(fields))
+				continue
+			}
+			l.checkFields(f, value, field.Type, position.Join(path, key))
+		}
+	case reflect.Slice, reflect.Array:
+		list, _ := data.([]interface{})
+		for i, item := range list {
+			l.checkFields(f, item, typ.Elem(), position.Join(path, i))
+		}
+	case reflect.Map:
+		m, _ := data.(map[string]interface{})
+		for key, value := range m {
+			l.checkFields(f, value, typ.Elem(), position.Join(path, key))
+		}
+	}
+}
+
+func (l *linter) reportUnknownField(f *lintFile, field, key string, candidates []string) {
+	msg := fmt.Sprintf("unknown field %s", key)
+	if suggestion := closestName(key, candidates); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %s?", suggestion)
+	}
+	l.report(f, field, lintError, ruleUnknownField, "%s", msg)
+}
+
+// jsonFields returns struct fields by json names, fields of embedded structs are included
+func jsonFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for name, embedded := range jsonFields(field.Type) {
+				fields[name] = embedded
+			}
+			continue
+		}
+		if field.PkgPath != "" || tag == "-" {
+			// unexported or ignored field
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// checkValue reports variables not in scope and functions not found in strings of value recursively
+func (l *linter) checkValue(f *lintFile, plugin *lintPlugin, value interface{}, path string,
+	scope map[string]bool, severity string) {
+	switch v := value.(type) {
+	case string:
+		for name := range findallVariables(v) {
+			if !scope[name] {
+				l.report(f, path, severity, ruleUndefinedVariable,
+					"variable %s is not defined or extracted before use", name)
+			}
+		}
+		for _, name := range findallFunctions(v) {
+			l.checkFunction(f, plugin, name, path)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			l.checkValue(f, plugin, item, position.Join(path, key), scope, severity)
+		}
+	case []interface{}:
+		for i, item := range v {
+			l.checkValue(f, plugin, item, position.Join(path, i), scope, severity)
+		}
+	}
+}
+
+func (l *linter) checkFunction(f *lintFile, plugin *lintPlugin, name, path string) {
+	if _, ok := builtin.Functions[name]; ok {
+		return
+	}
+	if _, ok := l.functions[name]; ok {
+		return
+	}
+	if plugin.has(name) || plugin.failed {
+		return
+	}
+	l.report(f, path, lintError, ruleUnknownFunction, "function %s is not found in builtin functions or plugin", name)
+}
+
+func (l *linter) checkAssertion(f *lintFile, plugin *lintPlugin, name, path string) {
+	if _, ok := builtin.Assertions[name]; ok {
+		return
+	}
+	if name == assertJSONSchema || name == assertSnapshot {
+		return
+	}
+	if plugin.has(name) || plugin.failed {
+		return
+	}
+	msg := fmt.Sprintf("assertion %s is not found in builtin assertions or plugin", name)
+	if suggestion := closestName(name, mapKeys(builtin.Assertions)); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %s?", suggestion)
+	}
+	l.report(f, path, lintError, ruleUnknownAssertion, "%s", msg)
+}
+
+// checkValidators lints validators in HRP format {check, assert, expect, msg}, HttpRunner format
+// {assert: [check, expect]}, and logical combinators any_of/all_of/not of nested validators
+func (l *linter) checkValidators(f *lintFile, plugin *lintPlugin, value interface{}, path string,
+	scope map[string]bool, severity string) {
+	if value == nil {
+		return
+	}
+	validators, ok := value.([]interface{})
+	if !ok {
+		l.report(f, path, lintError, ruleInvalidFormat, "validators should be a list")
+		return
+	}
+	for i, item := range validators {
+		l.checkValidator(f, plugin, item, position.Join(path, i), scope, severity)
+	}
+}
+
+func (l *linter) checkValidator(f *lintFile, plugin *lintPlugin, value interface{}, path string,
+	scope map[string]bool, severity string) {
+	validator, ok := value.(map[string]interface{})
+	if !ok {
+		l.report(f, path, lintError, ruleInvalidFormat, "validator should be a mapping")
+		return
+	}
+
+	for _, key := range []string{assertAnyOf, assertAllOf, assertNot} {
+		nested, ok := validator[key]
+		if !ok {
+			continue
+		}
+		l.checkValidatorFields(f, validator, path, key, "msg")
+		if key == assertNot {
+			l.checkValidator(f, plugin, nested, position.Join(path, key), scope, severity)
+		} else {
+			l.checkValidators(f, plugin, nested, position.Join(path, key), scope, severity)
+		}
+		return
+	}
+
+	_, checkExisted := validator["check"]
+	_, assertExisted := validator["assert"]
+	if checkExisted || assertExisted {
+		// HRP validator format
+		l.checkValidatorFields(f, validator, path, "check", "assert", "expect", "msg")
+		if !checkExisted || !assertExisted {
+			l.report(f, path, lintError, ruleInvalidFormat, "validator should contain check, assert and expect")
+			return
+		}
+		if assert, ok := validator["assert"].(string); ok {
+			l.checkAssertion(f, plugin, assert, position.Join(path, "assert"))
+		}
+		l.checkCheckItem(f, plugin, validator["check"], position.Join(path, "check"), scope, severity)
+		l.checkValue(f, plugin, validator["expect"], position.Join(path, "expect"), scope, severity)
+		return
+	}
+
+	if len(validator) != 1 {
+		l.report(f, path, lintError, ruleInvalidFormat,
+			"validator should be {check, assert, expect} or {assert: [check, expect]}")
+		return
+	}
+	// HttpRunner validator format
+	for assert, content := range validator {
+		field := position.Join(path, assert)
+		checkAndExpect, ok := content.([]interface{})
+		if !ok || len(checkAndExpect) != 2 {
+			l.report(f, field, lintError, ruleInvalidFormat, "validator %s should be [check, expect]", assert)
+			return
+		}
+		l.checkAssertion(f, plugin, assert, field)
+		l.checkCheckItem(f, plugin, checkAndExpect[0], position.Join(field, 0), scope, severity)
+		l.checkValue(f, plugin, checkAndExpect[1], position.Join(field, 1), scope, severity)
+	}
+}
+
+func (l *linter) checkValidatorFields(f *lintFile, validator map[string]interface{}, path string, fields ...string) {
+	for key := range validator {
+		if !stringInSlice(key, fields) {
+			l.reportUnknownField(f, position.Join(path, key), key, fields)
+		}
+	}
+}
+
+// checkCheckItem checks variables in check item, selectors like $.data are not variables
+func (l *linter) checkCheckItem(f *lintFile, plugin *lintPlugin, value interface{}, path string,
+	scope map[string]bool, severity string) {
+	check, ok := value.(string)
+	if !ok {
+		l.report(f, path, lintError, ruleInvalidFormat, "check should be string, got %v", value)
+		return
+	}
+	if !isSelectorExpr(check) {
+		l.checkValue(f, plugin, check, path, scope, severity)
+	}
+}
+
+// checkExtract checks extract which is mapping of variable names to expression strings
+func (l *linter) checkExtract(f *lintFile, value interface{}, path string) {
+	if value == nil {
+		return
+	}
+	extract, ok := value.(map[string]interface{})
+	if !ok {
+		l.report(f, path, lintError, ruleInvalidFormat, "extract should be a mapping of variable names to expressions")
+		return
+	}
+	for name, expr := range extract {
+		field := position.Join(path, name)
+		switch expr.(type) {
+		case string:
+		case map[string]interface{}:
+			l.report(f, field, lintError, ruleInvalidFormat,
+				"extractor object of %s should be put in extractors", name)
+		default:
+			l.report(f, field, lintError, ruleInvalidFormat, "extract expression of %s should be string", name)
+		}
+	}
+}
+
+// checkExtractors checks extractors which is mapping of variable names to Extractor
+func (l *linter) checkExtractors(f *lintFile, value interface{}, path string) {
+	if value == nil {
+		return
+	}
+	extractors, ok := value.(map[string]interface{})
+	if !ok {
+		l.report(f, path, lintError, ruleInvalidFormat, "extractors should be a mapping of variable names to extractors")
+		return
+	}
+	for name, extractor := range extractors {
+		field := position.Join(path, name)
+		v, ok := extractor.(map[string]interface{})
+		if !ok {
+			l.report(f, field, lintError, ruleInvalidFormat, "extractor %s should be mapping with expr", name)
+			continue
+		}
+		l.checkFields(f, v, reflect.TypeOf(Extractor{}), field)
+		if _, ok := v["expr"].(string); !ok {
+			l.report(f, field, lintError, ruleInvalidFormat, "extractor %s should contain expr string", name)
+		}
+	}
+}
+
+// lintPlugin is function plugin of project, which is initialized once to check plugin functions
+type lintPlugin struct {
+	plugin funplugin.IPlugin // nil if plugin file not found
+	failed bool              // plugin functions are not checked if failed to init plugin
+}
+
+func (p *lintPlugin) has(name string) bool {
+	return p.plugin != nil && p.plugin.Has(name)
+}
+
+func (l *linter) loadPlugin(f *lintFile) *lintPlugin {
+	pluginPath, err := locatePlugin(f.path)
+	if err != nil {
+		return &lintPlugin{}
+	}
+	if p, ok := l.plugins[pluginPath]; ok {
+		return p
+	}
+	p := &lintPlugin{}
+	l.plugins[pluginPath] = p
+	p.plugin, err = funplugin.Init(pluginPath)
+	if err != nil {
+		p.failed = true
+		l.report(f, "", lintWarning, rulePlugin,
+			"init plugin %s failed, plugin functions and assertions are not checked: %v", pluginPath, err)
+	}
+	return p
+}
+
+// lintDisplayPath returns path relative to current working directory if possible
+func lintDisplayPath(absPath string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return absPath
+	}
+	relPath, err := filepath.Rel(cwd, absPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return absPath
+	}
+	return relPath
+}
+
+// parametersNames returns names of parameters, e.g. username-password => username, password
+func parametersNames(parameters interface{}) []string {
+	var names []string
+	for _, key := range mapKeys(parameters) {
+		names = append(names, strings.Split(key, "-")...)
+	}
+	return names
+}
+
+// mapKeys returns keys of map with string keys, nil is returned for other types
+func mapKeys(m interface{}) []string {
+	value := reflect.ValueOf(m)
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	keys := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func listStrings(value interface{}) []string {
+	list, _ := value.([]interface{})
+	var names []string
+	for _, item := range list {
+		if name, ok := item.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// mergeNames merges names into a new scope, names are map[string]bool or []string
+func mergeNames(scopes ...interface{}) map[string]bool {
+	merged := make(map[string]bool)
+	for _, scope := range scopes {
+		switch names := scope.(type) {
+		case map[string]bool:
+			for name := range names {
+				merged[name] = true
+			}
+		case []string:
+			for _, name := range names {
+				merged[name] = true
+			}
+		}
+	}
+	return merged
+}
+
+// closestName returns candidate with the smallest edit distance to name, empty if none is close enough
+func closestName(name string, candidates []string) string {
+	closest, minDistance := "", 3
+	for _, candidate := range candidates {
+		if d := editDistance(name, candidate); d < minDistance && d < len(candidate) {
+			closest, minDistance = candidate, d
+		}
+	}
+	return closest
+}
+
+// editDistance returns levenshtein distance of two strings, transposition of adjacent chars counts one
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package hrp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	dir := writeVariablesFiles(t, map[string]string{
+		"common.yml": "base_url: https://example.com\n",
+		"api/get.yml": `name: get
+request:
+    method: GET
+    url: /get
+    params:
+        user: $user
+extract:
+    token: body.token
+`,
+		"testcases/child.yml": `config:
+    name: child
+    export: [session_id]
+teststeps:
+-   name: login
+    request:
+        method: POST
+        url: /login
+        json: {token: $token}
+    extract:
+        session_id: body.id
+`,
+		"testcases/demo.yml": `config:
+    name: demo
+    variables_files: [../common.yml]
+    variables:
+        user: leo
+teststeps:
+-   name: get token
+    api: api/get.yml
+-   name: call child
+    testcase: testcases/child.yml
+-   name: post
+    request:
+        method: POST
+        url: $base_url/post
+        headers:
+            X-Token: $token
+            X-Session: ${session_id}
+            X-Unknown: $unknown
+        body: ${gen_random_string(8)} ${not_exist_func($user)}
+    valdiate:
+    -   eq: [status_code, 200]
+    validate:
+    -   eq: [status_code, 200]
+    -   eqauls: [body.user, $user]
+    -   check: body.token
+        assert: equal
+        expect: $token
+        mgs: token
+    -   any_of:
+        -   eq: [body.id, $id]
+        -   not: {check: body.id, assert: length_equal, expect: 0}
+    extractors:
+        id: {expr: body.id, defualt: 0}
+-   name: not found
+    api: api/not_found.yml
+-   name: unknown
+    requests: {url: /get}
+`,
+		"testcases/broken.json": `{"config": {"name": "broken"}, "teststeps": [}`,
+		"testcases/loop.yml":    "config: {name: loop}\nteststeps:\n-   name: self\n    testcase: testcases/loop.yml\n",
+		"testcases/data.yml":    "users: [leo]\n",
+		".hidden/demo.yml":      "teststeps: [{name: hidden, invalid: true}]\n",
+	})
+
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Lint(".")
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	expected := []*LintIssue{
+		{"testcases/broken.json", 1, 46, lintError, ruleSyntax, "",
+			"invalid json: invalid character '}' looking for beginning of value"},
+		// variables of caller are unknown when referenced testcase is linted alone
+		{"testcases/child.yml", 9, 16, lintError, ruleUndefinedVariable, "teststeps[0].request.json.token",
+			"variable token is not defined or extracted before use"},
+		{"testcases/demo.yml", 18, 13, lintError, ruleUndefinedVariable, "teststeps[2].request.headers.X-Unknown",
+			"variable unknown is not defined or extracted before use"},
+		{"testcases/demo.yml", 19, 9, lintError, ruleUnknownFunction, "teststeps[2].request.body",
+			"function not_exist_func is not found in builtin functions or plugin"},
+		{"testcases/demo.yml", 20, 5, lintError, ruleUnknownField, "teststeps[2].valdiate",
+			"unknown field valdiate, did you mean validate?"},
+		{"testcases/demo.yml", 24, 9, lintError, ruleUnknownAssertion, "teststeps[2].validate[1].eqauls",
+			"assertion eqauls is not found in builtin assertions or plugin, did you mean equals?"},
+		{"testcases/demo.yml", 28, 9, lintError, ruleUnknownField, "teststeps[2].validate[2].mgs",
+			"unknown field mgs, did you mean msg?"},
+		{"testcases/demo.yml", 33, 29, lintError, ruleUnknownField, "teststeps[2].extractors.id.defualt",
+			"unknown field defualt, did you mean default?"},
+		{"testcases/demo.yml", 35, 5, lintError, ruleReferenceNotFound, "teststeps[3].api",
+			"referenced api api/not_found.yml not found in project root directory " + dir},
+		{"testcases/demo.yml", 36, 5, lintError, ruleInvalidFormat, "teststeps[4]",
+			"teststep should contain one of request, socket, api, testcase, transaction, rendezvous or think_time"},
+		{"testcases/demo.yml", 37, 5, lintError, ruleUnknownField, "teststeps[4].requests",
+			"unknown field requests, did you mean request?"},
+		{"testcases/loop.yml", 4, 5, lintError, ruleCircularReference, "teststeps[0].testcase",
+			"testcase testcases/loop.yml is referenced circularly"},
+	}
+	if !assert.Equal(t, expected, issues) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "testcases/demo.yml:20:5: error: unknown field valdiate, did you mean validate? [unknown-field]",
+		expected[4].String()) {
+		t.Fail()
+	}
+
+	// api file specified explicitly is linted as api
+	issues, err = Lint("api/get.yml")
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Len(t, issues, 1) || !assert.Equal(t, "request.params.user", issues[0].Field) {
+		t.Fail()
+	}
+
+	if _, err := Lint("not_found.yml"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestLintExtractorObjectInExtract(t *testing.T) {
+	dir := writeVariablesFiles(t, map[string]string{
+		"api.yml": "name: get\nrequest: {method: GET, url: /get}\nextract:\n    id: {expr: body.id}\n",
+	})
+	issues, err := Lint(filepath.Join(dir, "api.yml"))
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+	if !assert.Contains(t, messages, "extractor object of id should be put in extractors") {
+		t.Fail()
+	}
+}
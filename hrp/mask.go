@@ -0,0 +1,169 @@
+package hrp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/redact"
+)
+
+// maskBody masks values matched by redact JSON paths in json or form body,
+// body is returned as is if nothing is masked or body could not be decoded.
+func maskBody(redactor *redact.Redactor, contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		form := make(map[string]interface{}, len(values))
+		for key, value := range values {
+			if len(value) == 1 {
+				form[key] = value[0]
+				continue
+			}
+			list := make([]interface{}, len(value))
+			for i, v := range value {
+				list[i] = v
+			}
+			form[key] = list
+		}
+		masked := redactor.MaskJSON(form)
+		if reflect.DeepEqual(masked, form) {
+			return body
+		}
+		maskedValues := make(url.Values)
+		for key, value := range masked.(map[string]interface{}) {
+			if list, ok := value.([]interface{}); ok {
+				for _, v := range list {
+					maskedValues.Add(key, convertString(v))
+				}
+				continue
+			}
+			maskedValues.Add(key, convertString(value))
+		}
+		return []byte(maskedValues.Encode())
+	}
+
+	var data interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return body
+	}
+	masked := redactor.MaskJSON(data)
+	if reflect.DeepEqual(masked, data) {
+		return body
+	}
+	maskedBody, err := json.Marshal(masked)
+	if err != nil {
+		return body
+	}
+	return maskedBody
+}
+
+// maskRequest returns copy of request with sensitive headers and body fields masked, body of req is kept readable
+func maskRequest(redactor *redact.Redactor, req *http.Request) (*http.Request, error) {
+	masked := req.Clone(req.Context())
+	masked.Header = redactor.MaskHTTPHeader(req.Header)
+	if req.Body == nil {
+		return masked, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	setBodyBytes(masked, maskBody(redactor, req.Header.Get("Content-Type"), body))
+	return masked, nil
+}
+
+// maskResponse returns copy of response with sensitive headers and body fields masked
+func maskResponse(redactor *redact.Redactor, resp *http.Response, body []byte) *http.Response {
+	masked := *resp
+	masked.Header = redactor.MaskHTTPHeader(resp.Header)
+	maskedBody := maskBody(redactor, resp.Header.Get("Content-Type"), body)
+	masked.Body = io.NopCloser(bytes.NewReader(maskedBody))
+	if resp.ContentLength >= 0 {
+		masked.ContentLength = int64(len(maskedBody))
+	}
+	return &masked
+}
+
+// maskSessionMap returns copy of request map or response meta recorded in summary,
+// with sensitive headers, cookies and body fields masked
+func maskSessionMap(redactor *redact.Redactor, m map[string]interface{}) map[string]interface{} {
+	// cookies are sent in Cookie and Set-Cookie headers
+	sensitiveCookies := redactor.IsSensitiveHeader("Cookie") || redactor.IsSensitiveHeader("Set-Cookie")
+	masked := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		switch key {
+		case "headers":
+			value = maskFields(value, redactor.IsSensitiveHeader)
+		case "cookies":
+			value = maskFields(value, func(string) bool { return sensitiveCookies })
+		case "body", "payload":
+			value = redactor.MaskJSON(value)
+		}
+		masked[key] = value
+	}
+	return masked
+}
+
+// maskFields returns copy of fields with values of sensitive names masked,
+// fields should be map[string]string or map[string]interface{}
+func maskFields(fields interface{}, sensitive func(name string) bool) interface{} {
+	switch v := fields.(type) {
+	case map[string]string:
+		masked := make(map[string]string, len(v))
+		for name, value := range v {
+			if sensitive(name) {
+				value = redact.Mask
+			}
+			masked[name] = value
+		}
+		return masked
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for name, value := range v {
+			if sensitive(name) {
+				value = redact.Mask
+			}
+			masked[name] = value
+		}
+		return masked
+	}
+	return fields
+}
+
+// maskCaseSummary masks values of sensitive variables in testcase summary and its step records,
+// variables are replaced with masked copies since they may be shared with running testcases
+func maskCaseSummary(redactor *redact.Redactor, summary *testCaseSummary) {
+	if summary.InOut != nil {
+		summary.InOut.ConfigVars = redactor.MaskVariables(summary.InOut.ConfigVars)
+		summary.InOut.ExportVars = redactor.MaskVariables(summary.InOut.ExportVars)
+	}
+	for _, record := range summary.Records {
+		maskStepData(redactor, record)
+	}
+}
+
+func maskStepData(redactor *redact.Redactor, data *stepData) {
+	data.ExportVars = redactor.MaskVariables(data.ExportVars)
+	data.Parameters = redactor.MaskVariables(data.Parameters)
+	switch v := data.Data.(type) {
+	case []*stepData:
+		for _, iteration := range v {
+			maskStepData(redactor, iteration)
+		}
+	case *testCaseSummary:
+		maskCaseSummary(redactor, v)
+	}
+}
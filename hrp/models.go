@@ -2,12 +2,16 @@ package hrp
 
 import (
 	"fmt"
-	"math/rand"
+	"io"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
 	"github.com/httprunner/httprunner/hrp/internal/builtin"
 	"github.com/httprunner/httprunner/hrp/internal/version"
 )
@@ -30,25 +34,50 @@ type TConfig struct {
 	BaseURL           string                 `json:"base_url,omitempty" yaml:"base_url,omitempty"`
 	Headers           map[string]string      `json:"headers,omitempty" yaml:"headers,omitempty"`
 	Variables         map[string]interface{} `json:"variables,omitempty" yaml:"variables,omitempty"`
+	VariablesFiles    []string               `json:"variables_files,omitempty" yaml:"variables_files,omitempty"` // JSON/YAML files of shared variables
 	Parameters        map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	ParametersSetting *TParamsConfig         `json:"parameters_setting,omitempty" yaml:"parameters_setting,omitempty"`
 	ThinkTime         *ThinkTimeConfig       `json:"think_time,omitempty" yaml:"think_time,omitempty"`
 	Export            []string               `json:"export,omitempty" yaml:"export,omitempty"`
 	Weight            int                    `json:"weight,omitempty" yaml:"weight,omitempty"`
-	Path              string                 `json:"path,omitempty" yaml:"path,omitempty"` // testcase file path
+	OpenAPI           string                 `json:"openapi,omitempty" yaml:"openapi,omitempty"`                                 // openapi document for contract validation
+	OpenAPIUnmatched  bool                   `json:"openapi_allow_unmatched,omitempty" yaml:"openapi_allow_unmatched,omitempty"` // pass requests matching no openapi operation
+	Redact            *RedactConfig          `json:"redact,omitempty" yaml:"redact,omitempty"`                                   // secrets to be masked in logs, summary and report
+	SecretsFile       string                 `json:"secrets_file,omitempty" yaml:"secrets_file,omitempty"`                       // encrypted variables file, see hrp secrets
+	Path              string                 `json:"path,omitempty" yaml:"path,omitempty"`                                       // testcase file path
+
+	// names of variables only defined in variables files, recorded before they are merged into variables
+	fileVariableNames map[string]bool
 }
 
 type TParamsConfig struct {
-	Strategy  interface{} `json:"strategy,omitempty" yaml:"strategy,omitempty"` // map[string]string、string
-	Iteration int         `json:"iteration,omitempty" yaml:"iteration,omitempty"`
-	Iterators []*Iterator `json:"parameterIterator,omitempty" yaml:"parameterIterator,omitempty"` // 保存参数的迭代器
+	Strategy    interface{} `json:"strategy,omitempty" yaml:"strategy,omitempty"` // map[string]string、string
+	Iteration   int         `json:"iteration,omitempty" yaml:"iteration,omitempty"`
+	OnExhausted string      `json:"on_exhausted,omitempty" yaml:"on_exhausted,omitempty"`           // stop_test(default) or stop_user, for once strategy
+	Iterators   []*Iterator `json:"parameterIterator,omitempty" yaml:"parameterIterator,omitempty"` // 保存参数的迭代器
 }
 
 const (
-	strategyRandom     string = "random"
-	strategySequential string = "Sequential"
+	strategyRandom       string = "random"
+	strategySequential   string = "Sequential"
+	strategyUnique       string = "unique"        // rows are partitioned among virtual users
+	strategyOnce         string = "once"          // each row is picked only once
+	strategyVUSequential string = "vu_sequential" // each virtual user picks rows sequentially with its own cursor
+)
+
+const (
+	exhaustedStopTest string = "stop_test"
+	exhaustedStopUser string = "stop_user"
 )
 
+// RedactConfig specifies secrets to be masked in request logs, summary and HTML report,
+// values of Authorization, Cookie and other default sensitive headers are always masked.
+type RedactConfig struct {
+	Headers   []string `json:"headers,omitempty" yaml:"headers,omitempty"`       // header names, case-insensitive
+	Variables []string `json:"variables,omitempty" yaml:"variables,omitempty"`   // variable names, including extracted variables
+	JSONPaths []string `json:"json_paths,omitempty" yaml:"json_paths,omitempty"` // JSONPath expressions evaluated against request and response bodies
+}
+
 type ThinkTimeConfig struct {
 	Strategy string      `json:"strategy,omitempty" yaml:"strategy,omitempty"` // default、random、limit、multiply、ignore
 	Setting  interface{} `json:"setting,omitempty" yaml:"setting,omitempty"`   // random(map): {"min_percentage": 0.5, "max_percentage": 1.5}; 10、multiply(float64): 1.5
@@ -127,12 +156,67 @@ func (ttc *ThinkTimeConfig) checkThinkTime() {
 
 type paramsType []map[string]interface{}
 
+// paramsStream reads parameters lazily from csv or jsonl file, rows are read from the beginning again
+// once the file is exhausted
+type paramsStream struct {
+	names  []string
+	stream *builtin.ParamsStream
+	count  int
+}
+
+// newParamsStream validates all rows of stream and counts them without holding them in memory
+func newParamsStream(name string, stream *builtin.ParamsStream) (*paramsStream, error) {
+	s := &paramsStream{names: strings.Split(name, "-"), stream: stream}
+	for {
+		row, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parseParameterElement(s.names, row); err != nil {
+			return nil, errors.Wrapf(err, "invalid parameters in %s", stream.Path())
+		}
+		s.count++
+	}
+	if err := stream.Reset(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *paramsStream) Iterator() *Iterator {
+	return &Iterator{
+		stream:    s,
+		iteration: s.count,
+		index:     0,
+	}
+}
+
+func (s *paramsStream) next() (map[string]interface{}, error) {
+	row, err := s.stream.Next()
+	if err == io.EOF {
+		if err := s.stream.Reset(); err != nil {
+			return nil, err
+		}
+		row, err = s.stream.Next()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseParameterElement(s.names, row)
+}
+
 type Iterator struct {
 	sync.Mutex
 	data      paramsType
-	strategy  string // random, sequential
+	stream    *paramsStream // parameters read lazily from file, data is ignored if set
+	strategy  string        // random, sequential, unique, once, vu_sequential
 	iteration int
 	index     int
+	users     int         // count of virtual users sharing the iterator, which is used by unique strategy
+	cursors   map[int]int // virtual user id => count of parameters picked by the virtual user
 }
 
 func (params paramsType) Iterator() *Iterator {
@@ -143,6 +227,24 @@ func (params paramsType) Iterator() *Iterator {
 	}
 }
 
+// rows returns count of parameters rows
+func (iter *Iterator) rows() int {
+	if iter.stream != nil {
+		return iter.stream.count
+	}
+	return len(iter.data)
+}
+
+// setUsers sets count of virtual users, each virtual user should be handed at least one row with unique strategy
+func (iter *Iterator) setUsers(users int) error {
+	if iter.strategy == strategyUnique && iter.rows() > 0 && iter.rows() < users {
+		return errors.Errorf("unique strategy requires at least %d parameters rows for %d virtual users, got %d",
+			users, users, iter.rows())
+	}
+	iter.users = users
+	return nil
+}
+
 func (iter *Iterator) HasNext() bool {
 	if iter.iteration == -1 {
 		return true
@@ -151,21 +253,98 @@ func (iter *Iterator) HasNext() bool {
 }
 
 func (iter *Iterator) Next() (value map[string]interface{}) {
+	value, _, err := iter.next(nil)
+	if err != nil {
+		log.Error().Err(err).Msg("get next parameters failed")
+		return map[string]interface{}{}
+	}
+	return value
+}
+
+// next returns next parameters picked for the virtual user, false is returned if parameters are exhausted
+// with once strategy. Random generator of the virtual user is used to pick parameters with random strategy.
+// Error is returned if parameters could not be read from parameters file.
+func (iter *Iterator) next(vu *virtualUser) (value map[string]interface{}, ok bool, err error) {
 	iter.Lock()
 	defer iter.Unlock()
-	if len(iter.data) == 0 {
+	rows := iter.rows()
+	if rows == 0 {
 		iter.index++
-		return map[string]interface{}{}
+		return map[string]interface{}{}, true, nil
 	}
-	if iter.strategy == strategyRandom {
-		randSource := rand.New(rand.NewSource(time.Now().Unix()))
-		randIndex := randSource.Intn(len(iter.data))
-		value = iter.data[randIndex]
-	} else {
-		value = iter.data[iter.index%len(iter.data)]
+	if iter.strategy == strategyOnce && iter.index >= rows {
+		return nil, false, nil
+	}
+	if iter.stream != nil {
+		iter.index++
+		value, err := iter.stream.next()
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "read parameters from %s failed", iter.stream.stream.Path())
+		}
+		return value, true, nil
+	}
+
+	vuID := 0
+	random := defaultRandom
+	if vu != nil {
+		vuID = vu.id
+		random = vu.random
+	}
+	switch iter.strategy {
+	case strategyRandom:
+		value = iter.data[random.Intn(rows)]
+	case strategyUnique:
+		// rows are partitioned among virtual users, virtual user n (starting from 1) is handed
+		// rows n-1, n-1+users, n-1+2*users, ... in turn
+		users := iter.users
+		if users <= 0 {
+			users = 1
+		}
+		slot := 0
+		if vuID > 0 {
+			slot = (vuID - 1) % users
+		}
+		count := (rows - slot + users - 1) / users
+		if count <= 0 {
+			return nil, false, nil
+		}
+		value = iter.data[slot+(iter.cursor(vuID)%count)*users]
+	case strategyVUSequential:
+		value = iter.data[iter.cursor(vuID)%rows]
+	default:
+		value = iter.data[iter.index%rows]
 	}
 	iter.index++
-	return value
+	return value, true, nil
+}
+
+// close closes parameters file if parameters are read lazily
+func (iter *Iterator) close() {
+	iter.Lock()
+	defer iter.Unlock()
+	if iter.stream != nil {
+		iter.stream.stream.Close()
+	}
+}
+
+// closeIterators closes parameters files of all iterators, it should be called when testcase or load test finishes
+func (c *TParamsConfig) closeIterators() {
+	if c == nil {
+		return
+	}
+	for _, iter := range c.Iterators {
+		iter.close()
+	}
+}
+
+// cursor returns count of parameters picked by the virtual user and increases it
+func (iter *Iterator) cursor(vuID int) int {
+	if iter.cursors == nil {
+		iter.cursors = make(map[int]int)
+	}
+	cursor := iter.cursors[vuID]
+	iter.cursors[vuID]++
+	return cursor
 }
 
 // Request represents HTTP request data structure.
@@ -184,6 +363,19 @@ type Request struct {
 	Verify         bool                   `json:"verify,omitempty" yaml:"verify,omitempty"`
 }
 
+// Socket represents raw TCP/UDP socket data structure.
+// This is used for teststep which talks to services over plain sockets.
+type Socket struct {
+	Network   string      `json:"network" yaml:"network"` // required, tcp, tls or udp
+	Address   string      `json:"address" yaml:"address"` // required, host:port
+	Payload   interface{} `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Encoding  string      `json:"encoding,omitempty" yaml:"encoding,omitempty"`   // payload encoding, text(default), hex or base64
+	Delimiter string      `json:"delimiter,omitempty" yaml:"delimiter,omitempty"` // read until delimiter
+	Length    int         `json:"length,omitempty" yaml:"length,omitempty"`       // read until length bytes received
+	Timeout   float32     `json:"timeout,omitempty" yaml:"timeout,omitempty"`     // seconds, read until timeout if neither delimiter nor length is set
+	Verify    bool        `json:"verify,omitempty" yaml:"verify,omitempty"`       // verify server certificate for tls
+}
+
 type API struct {
 	Name          string                 `json:"name" yaml:"name"` // required
 	Request       *Request               `json:"request,omitempty" yaml:"request,omitempty"`
@@ -191,9 +383,10 @@ type API struct {
 	SetupHooks    []string               `json:"setup_hooks,omitempty" yaml:"setup_hooks,omitempty"`
 	TeardownHooks []string               `json:"teardown_hooks,omitempty" yaml:"teardown_hooks,omitempty"`
 	Extract       map[string]string      `json:"extract,omitempty" yaml:"extract,omitempty"`
+	Extractors    map[string]Extractor   `json:"extractors,omitempty" yaml:"extractors,omitempty"` // override extract with the same variable name
 	Validators    []interface{}          `json:"validate,omitempty" yaml:"validate,omitempty"`
 	Export        []string               `json:"export,omitempty" yaml:"export,omitempty"`
-	Path          string
+	Path          string                 `json:"path,omitempty" yaml:"path,omitempty"`
 }
 
 func (api *API) GetPath() string {
@@ -210,6 +403,21 @@ type Validator struct {
 	Assert  string      `json:"assert" yaml:"assert"`
 	Expect  interface{} `json:"expect" yaml:"expect"`
 	Message string      `json:"msg,omitempty" yaml:"msg,omitempty"` // optional
+	// logical combinators of nested validators, check/assert/expect are ignored if specified
+	AnyOf []Validator `json:"any_of,omitempty" yaml:"any_of,omitempty"` // pass if any validator passes
+	AllOf []Validator `json:"all_of,omitempty" yaml:"all_of,omitempty"` // pass if all validators pass
+	Not   *Validator  `json:"not,omitempty" yaml:"not,omitempty"`       // pass if validator fails
+}
+
+// Extractor represents extractor of one variable from response, expression string in extract is used as
+// extractor with detected type, e.g. regex if it contains (.*), otherwise jmespath.
+type Extractor struct {
+	Type     string      `json:"type,omitempty" yaml:"type,omitempty"` // jmespath, jsonpath, regex, xpath or css, detected from expr if not set
+	Expr     string      `json:"expr" yaml:"expr"`
+	All      bool        `json:"all,omitempty" yaml:"all,omitempty"`           // regex: extract all matches as list
+	Group    *int        `json:"group,omitempty" yaml:"group,omitempty"`       // regex: index of capture group, defaults to 1, or 0 if no group
+	Default  interface{} `json:"default,omitempty" yaml:"default,omitempty"`   // value used if nothing matched
+	Required bool        `json:"required,omitempty" yaml:"required,omitempty"` // fail the step if nothing matched
 }
 
 // IAPI represents interface for api,
@@ -220,27 +428,32 @@ type IAPI interface {
 }
 
 // TStep represents teststep data structure.
-// Each step maybe two different type: make one HTTP request or reference another testcase.
+// Each step maybe different types: make one HTTP request, talk over a raw socket or reference another testcase.
 type TStep struct {
 	Name          string                 `json:"name" yaml:"name"` // required
 	Request       *Request               `json:"request,omitempty" yaml:"request,omitempty"`
+	Socket        *Socket                `json:"socket,omitempty" yaml:"socket,omitempty"`
 	API           interface{}            `json:"api,omitempty" yaml:"api,omitempty"`           // *APIPath or *API
 	TestCase      interface{}            `json:"testcase,omitempty" yaml:"testcase,omitempty"` // *TestCasePath or *TestCase
 	Transaction   *Transaction           `json:"transaction,omitempty" yaml:"transaction,omitempty"`
 	Rendezvous    *Rendezvous            `json:"rendezvous,omitempty" yaml:"rendezvous,omitempty"`
 	ThinkTime     *ThinkTime             `json:"think_time,omitempty" yaml:"think_time,omitempty"`
 	Variables     map[string]interface{} `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Parameters    map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"` // step is run once for each parameters row
 	SetupHooks    []string               `json:"setup_hooks,omitempty" yaml:"setup_hooks,omitempty"`
 	TeardownHooks []string               `json:"teardown_hooks,omitempty" yaml:"teardown_hooks,omitempty"`
 	Extract       map[string]string      `json:"extract,omitempty" yaml:"extract,omitempty"`
+	Extractors    map[string]Extractor   `json:"extractors,omitempty" yaml:"extractors,omitempty"` // override extract with the same variable name
 	Validators    []interface{}          `json:"validate,omitempty" yaml:"validate,omitempty"`
 	Export        []string               `json:"export,omitempty" yaml:"export,omitempty"`
+	SoftAssert    bool                   `json:"soft_assert,omitempty" yaml:"soft_assert,omitempty"` // evaluate all validators even if some failed
 }
 
 type stepType string
 
 const (
 	stepTypeRequest     stepType = "request"
+	stepTypeSocket      stepType = "socket"
 	stepTypeTestCase    stepType = "testcase"
 	stepTypeTransaction stepType = "transaction"
 	stepTypeRendezvous  stepType = "rendezvous"
@@ -293,6 +506,7 @@ type TCase struct {
 
 // IStep represents interface for all types for teststeps, includes:
 // StepRequest, StepRequestWithOptionalArgs, StepRequestValidation, StepRequestExtraction,
+// StepSocket,
 // StepTestCaseWithOptionalArgs,
 // StepTransaction, StepRendezvous.
 type IStep interface {
@@ -410,6 +624,9 @@ type stepData struct {
 	ContentSize int64                  `json:"content_size" yaml:"content_size"`                   // response body length
 	ExportVars  map[string]interface{} `json:"export_vars,omitempty" yaml:"export_vars,omitempty"` // extract variables
 	Attachment  string                 `json:"attachment,omitempty" yaml:"attachment,omitempty"`   // step error information
+	Curl        string                 `json:"curl,omitempty" yaml:"curl,omitempty"`               // rendered request in curl command line
+	Iteration   int                    `json:"iteration,omitempty" yaml:"iteration,omitempty"`     // iteration number of step with parameters, starting from 1
+	Parameters  map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`   // parameters of the iteration
 }
 
 type testCaseInOut struct {
@@ -431,8 +648,9 @@ type testCaseSummary struct {
 
 type validationResult struct {
 	Validator
-	CheckValue  interface{} `json:"check_value" yaml:"check_value"`
-	CheckResult string      `json:"check_result" yaml:"check_result"`
+	CheckValue  interface{}         `json:"check_value" yaml:"check_value"`
+	CheckResult string              `json:"check_result" yaml:"check_result"`
+	Children    []*validationResult `json:"children,omitempty" yaml:"children,omitempty"` // results of nested validators
 }
 
 type reqResps struct {
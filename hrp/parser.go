@@ -22,7 +22,8 @@ func newParser() *parser {
 }
 
 type parser struct {
-	plugin funplugin.IPlugin // plugin is used to call functions
+	plugin    funplugin.IPlugin      // plugin is used to call functions
+	functions map[string]interface{} // functions override builtin functions, e.g. random functions of virtual user
 }
 
 func buildURL(baseURL, stepURL string) string {
@@ -120,15 +121,13 @@ func parseJSONNumber(raw builtinJSON.Number) (interface{}, error) {
 }
 
 const (
-	regexVariable     = `[a-zA-Z_]\w*`    // variable name should start with a letter or underscore
-	regexFunctionName = `[a-zA-Z_]\w*`    // function name should start with a letter or underscore
-	regexNumber       = `^-?\d+(\.\d+)?$` // match number, e.g. 123, -123, 1.23, -1.23
+	regexVariable = `[a-zA-Z_]\w*`    // variable name should start with a letter or underscore
+	regexNumber   = `^-?\d+(\.\d+)?$` // match number, e.g. 123, -123, 1.23, -1.23
 )
 
 var (
-	regexCompileVariable = regexp.MustCompile(fmt.Sprintf(`\$\{(%s)\}|\$(%s)`, regexVariable, regexVariable))     // parse ${var} or $var
-	regexCompileFunction = regexp.MustCompile(fmt.Sprintf(`\$\{(%s)\(([\$\w\.\-/\s=,]*)\)\}`, regexFunctionName)) // parse ${func1($a, $b)}
-	regexCompileNumber   = regexp.MustCompile(regexNumber)                                                        // parse number
+	regexCompileVariable = regexp.MustCompile(fmt.Sprintf(`\$\{(%s)\}|\$(%s)`, regexVariable, regexVariable)) // parse ${var} or $var
+	regexCompileNumber   = regexp.MustCompile(regexNumber)                                                    // parse number
 )
 
 // parseString parse string with variables
@@ -152,7 +151,7 @@ func (p *parser) parseString(raw string, variablesMapping map[string]interface{}
 		remainedString = remainedString[startPosition:]
 
 		// Notice: notation priority
-		// $$ > ${func($a, $b)} > $var
+		// $$ > ${ expr } > ${func($a, $b)} > $var
 
 		// search $$, use $$ to escape $ notation
 		if strings.HasPrefix(remainedString, "$$") { // found $$
@@ -162,16 +161,36 @@ func (p *parser) parseString(raw string, variablesMapping map[string]interface{}
 			continue
 		}
 
+		// search expression like ${ $a + 1 }
+		if content, length, ok := matchExpression(remainedString); ok && isExpression(remainedString[:length], content) {
+			result, err := p.evalExpression(content, variablesMapping)
+			if err != nil {
+				return raw, err
+			}
+			if length == len(raw) {
+				// raw string is an expression, e.g. "${ $a + 1 }", return its eval value directly
+				return result, nil
+			}
+
+			// raw string contains one or many expressions, e.g. "id-${ $a + 1 }"
+			matchStartPosition += length
+			parsedString += fmt.Sprintf("%v", result)
+			remainedString = raw[matchStartPosition:]
+			continue
+		}
+
 		// search function like ${func($a, $b)}
-		funcMatched := regexCompileFunction.FindStringSubmatch(remainedString)
-		if len(funcMatched) == 3 {
-			funcName := funcMatched[1]
-			argsStr := funcMatched[2]
+		if funcName, argsStr, length, ok := matchFunction(remainedString); ok {
 			arguments, err := parseFunctionArguments(argsStr)
 			if err != nil {
 				return raw, err
 			}
-			parsedArgs, err := p.parseData(arguments, variablesMapping)
+			if len(arguments) > 0 {
+				if _, ok := arguments[len(arguments)-1].(keywordArguments); ok && !p.acceptKeywordArguments(funcName, len(arguments)-1) {
+					return raw, fmt.Errorf("function %s does not accept keyword arguments", funcName)
+				}
+			}
+			parsedArgs, err := p.parseArgument(arguments, variablesMapping)
 			if err != nil {
 				return raw, err
 			}
@@ -185,13 +204,13 @@ func (p *parser) parseString(raw string, variablesMapping map[string]interface{}
 			log.Info().Str("funcName", funcName).Interface("arguments", arguments).
 				Interface("output", result).Msg("call function success")
 
-			if funcMatched[0] == raw {
+			if length == len(raw) {
 				// raw_string is a function, e.g. "${add_one(3)}", return its eval value directly
 				return result, nil
 			}
 
 			// raw_string contains one or many functions, e.g. "abc${add_one(3)}def"
-			matchStartPosition += len(funcMatched[0])
+			matchStartPosition += length
 			parsedString += fmt.Sprintf("%v", result)
 			remainedString = raw[matchStartPosition:]
 			log.Debug().
@@ -201,6 +220,14 @@ func (p *parser) parseString(raw string, variablesMapping map[string]interface{}
 			continue
 		}
 
+		// keep ${...} which is not expression, function or variable as literal text, e.g. ${HOME:-x}
+		if length, ok := matchLiteral(remainedString); ok {
+			matchStartPosition += length
+			parsedString += remainedString[:length]
+			remainedString = raw[matchStartPosition:]
+			continue
+		}
+
 		// search variable like ${var} or $var
 		varMatched := regexCompileVariable.FindStringSubmatch(remainedString)
 		if len(varMatched) == 3 {
@@ -237,6 +264,69 @@ func (p *parser) parseString(raw string, variablesMapping map[string]interface{}
 	return parsedString, nil
 }
 
+// parseArgument parses function argument with variables mapping, quoted strings are used literally
+func (p *parser) parseArgument(argument interface{}, variablesMapping map[string]interface{}) (interface{}, error) {
+	switch v := argument.(type) {
+	case quotedString:
+		return string(v), nil
+	case []interface{}:
+		parsedList := make([]interface{}, len(v))
+		for i, item := range v {
+			parsedItem, err := p.parseArgument(item, variablesMapping)
+			if err != nil {
+				return argument, err
+			}
+			parsedList[i] = parsedItem
+		}
+		return parsedList, nil
+	case map[string]interface{}, keywordArguments:
+		// keys are identifiers or quoted strings
+		m := reflect.ValueOf(v)
+		parsedMap := make(map[string]interface{}, m.Len())
+		for _, key := range m.MapKeys() {
+			parsedValue, err := p.parseArgument(m.MapIndex(key).Interface(), variablesMapping)
+			if err != nil {
+				return argument, err
+			}
+			parsedMap[key.String()] = parsedValue
+		}
+		return parsedMap, nil
+	default:
+		return p.parseData(v, variablesMapping)
+	}
+}
+
+// acceptKeywordArguments checks if function could take keyword arguments map after positional arguments
+// as its last map parameter or variadic ...interface{} parameter, plugin functions always accept it
+// since their signatures are unknown
+func (p *parser) acceptKeywordArguments(funcName string, positional int) bool {
+	if p.plugin != nil && p.plugin.Has(funcName) {
+		return true
+	}
+	function, ok := p.functions[funcName]
+	if !ok {
+		function, ok = builtin.Functions[funcName]
+	}
+	if !ok {
+		// function not found error is returned when calling
+		return true
+	}
+	fnType := reflect.TypeOf(function)
+	if fnType.Kind() != reflect.Func || fnType.NumIn() == 0 {
+		return false
+	}
+	last := fnType.In(fnType.NumIn() - 1)
+	if fnType.IsVariadic() {
+		last = last.Elem()
+		if positional < fnType.NumIn()-1 {
+			return false
+		}
+	} else if positional != fnType.NumIn()-1 || last.Kind() != reflect.Map {
+		return false
+	}
+	return reflect.TypeOf(map[string]interface{}{}).AssignableTo(last)
+}
+
 // callFunc calls function with arguments
 // only support return at most one result value
 func (p *parser) callFunc(funcName string, arguments ...interface{}) (interface{}, error) {
@@ -246,7 +336,10 @@ func (p *parser) callFunc(funcName string, arguments ...interface{}) (interface{
 	}
 
 	// get builtin function
-	function, ok := builtin.Functions[funcName]
+	function, ok := p.functions[funcName]
+	if !ok {
+		function, ok = builtin.Functions[funcName]
+	}
 	if !ok {
 		return nil, fmt.Errorf("function %s is not found", funcName)
 	}
@@ -281,6 +374,25 @@ func mergeVariables(variables, overriddenVariables map[string]interface{}) map[s
 	return mergedVariables
 }
 
+// merge two extractors map, the first map have higher priority
+func mergeExtractors(extractors, overriddenExtractors map[string]Extractor) map[string]Extractor {
+	if overriddenExtractors == nil {
+		return extractors
+	}
+	if extractors == nil {
+		return overriddenExtractors
+	}
+
+	mergedExtractors := make(map[string]Extractor)
+	for k, v := range overriddenExtractors {
+		mergedExtractors[k] = v
+	}
+	for k, v := range extractors {
+		mergedExtractors[k] = v
+	}
+	return mergedExtractors
+}
+
 // merge two map, the first map have higher priority
 func mergeMap(m, overriddenMap map[string]string) map[string]string {
 	if overriddenMap == nil {
@@ -354,6 +466,7 @@ func extendWithAPI(testStep *TStep, overriddenStep *API) {
 	testStep.Variables = mergeVariables(testStep.Variables, overriddenStep.Variables)
 	// merge & override extractors
 	testStep.Extract = mergeMap(testStep.Extract, overriddenStep.Extract)
+	testStep.Extractors = mergeExtractors(testStep.Extractors, overriddenStep.Extractors)
 	// merge & override validators
 	testStep.Validators = mergeValidators(testStep.Validators, overriddenStep.Validators)
 	// merge & override setupHooks
@@ -394,32 +507,6 @@ func literalEval(raw string) (interface{}, error) {
 	return result, nil
 }
 
-func parseFunctionArguments(argsStr string) ([]interface{}, error) {
-	argsStr = strings.TrimSpace(argsStr)
-	if argsStr == "" {
-		return []interface{}{}, nil
-	}
-
-	// split arguments by comma
-	args := strings.Split(argsStr, ",")
-	arguments := make([]interface{}, len(args))
-	for index, arg := range args {
-		arg = strings.TrimSpace(arg)
-		if arg == "" {
-			continue
-		}
-
-		// parse argument to number if possible
-		arg, err := literalEval(arg)
-		if err != nil {
-			return nil, err
-		}
-		arguments[index] = arg
-	}
-
-	return arguments, nil
-}
-
 func (p *parser) parseVariables(variables map[string]interface{}) (map[string]interface{}, error) {
 	parsedVariables := make(map[string]interface{})
 	var traverseRounds int
@@ -522,7 +609,7 @@ func findallVariables(raw string) variableSet {
 		remainedString = remainedString[startPosition:]
 
 		// Notice: notation priority
-		// $$ > $var
+		// $$ > ${ expr } > ${func($a, $b)} > $var
 
 		// search $$, use $$ to escape $ notation
 		if strings.HasPrefix(remainedString, "$$") { // found $$
@@ -531,6 +618,33 @@ func findallVariables(raw string) variableSet {
 			continue
 		}
 
+		// search expression like ${ $a + 1 }
+		if content, length, ok := matchExpression(remainedString); ok && isExpression(remainedString[:length], content) {
+			for _, varName := range findExpressionVariables(content) {
+				varSet[varName] = struct{}{}
+			}
+			matchStartPosition += length
+			remainedString = raw[matchStartPosition:]
+			continue
+		}
+
+		// search function like ${func($a, $b)}, variables in arguments and nested functions are included
+		if _, argsStr, length, ok := matchFunction(remainedString); ok {
+			for varName := range findallVariables(stripQuoted(argsStr)) {
+				varSet[varName] = struct{}{}
+			}
+			matchStartPosition += length
+			remainedString = raw[matchStartPosition:]
+			continue
+		}
+
+		// skip literal text like ${HOME:-x}
+		if length, ok := matchLiteral(remainedString); ok {
+			matchStartPosition += length
+			remainedString = raw[matchStartPosition:]
+			continue
+		}
+
 		// search variable like ${var} or $var
 		varMatched := regexCompileVariable.FindStringSubmatch(remainedString)
 		if len(varMatched) == 3 {
@@ -553,6 +667,53 @@ func findallVariables(raw string) variableSet {
 	return varSet
 }
 
+// findallFunctions returns names of functions called in raw string, including functions called
+// in arguments of functions and in expressions, e.g. ${f(${g($a)})} => [f, g]
+func findallFunctions(raw string) []string {
+	var functions []string
+	for i := 0; i < len(raw); {
+		// locate $ char position
+		startPosition := strings.Index(raw[i:], "$")
+		if startPosition == -1 {
+			break
+		}
+		i += startPosition
+		remainedString := raw[i:]
+
+		// skip escaped $$
+		if strings.HasPrefix(remainedString, "$$") {
+			i += 2
+			continue
+		}
+
+		// search expression like ${ max($a, 1) > 0 }
+		if content, length, ok := matchExpression(remainedString); ok && isExpression(remainedString[:length], content) {
+			if _, _, exprFunctions, err := translateExpression(content); err == nil {
+				functions = append(functions, exprFunctions...)
+			}
+			i += length
+			continue
+		}
+
+		// search function like ${func($a, $b)}
+		if funcName, argsStr, length, ok := matchFunction(remainedString); ok {
+			functions = append(functions, funcName)
+			functions = append(functions, findallFunctions(stripQuoted(argsStr))...)
+			i += length
+			continue
+		}
+
+		// skip literal text like ${HOME:-x}
+		if length, ok := matchLiteral(remainedString); ok {
+			i += length
+			continue
+		}
+
+		i++
+	}
+	return functions
+}
+
 func genCartesianProduct(paramsMap map[string]paramsType) paramsType {
 	if len(paramsMap) == 0 {
 		return nil
@@ -575,11 +736,14 @@ func genCartesianProduct(paramsMap map[string]paramsType) paramsType {
 	return cartesianProduct
 }
 
-func parseParameters(parameters map[string]interface{}, variablesMapping map[string]interface{}) (map[string]paramsType, error) {
+// parseParameters parses parameters with functions of p, e.g. seeded random functions of virtual user.
+func (p *parser) parseParameters(parameters map[string]interface{}, variablesMapping map[string]interface{}) (
+	map[string]paramsType, map[string]*paramsStream, error) {
 	if len(parameters) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 	parsedParametersSlice := make(map[string]paramsType)
+	parsedParametersStreams := make(map[string]*paramsStream)
 	var err error
 	for k, v := range parameters {
 		var parameterSlice paramsType
@@ -588,15 +752,24 @@ func parseParameters(parameters map[string]interface{}, variablesMapping map[str
 		case reflect.String:
 			// e.g. username-password: ${parameterize(examples/hrp/account.csv)} -> [{"username": "test1", "password": "111111"}, {"username": "test2", "password": "222222"}]
 			var parsedParameterContent interface{}
-			parsedParameterContent, err = newParser().parseString(rawValue.String(), variablesMapping)
+			parsedParameterContent, err = p.parseString(rawValue.String(), variablesMapping)
 			if err != nil {
 				log.Error().Interface("parameterContent", rawValue).Msg("[parseParameters] parse parameter content error")
-				return nil, err
+				return nil, nil, err
+			}
+			// e.g. user: ${parameterize(users.jsonl, stream=true)}, rows are read lazily
+			if stream, ok := parsedParameterContent.(*builtin.ParamsStream); ok {
+				parsedParametersStreams[k], err = newParamsStream(k, stream)
+				if err != nil {
+					stream.Close()
+					return nil, nil, err
+				}
+				continue
 			}
 			parsedParameterRawValue := reflect.ValueOf(parsedParameterContent)
 			if parsedParameterRawValue.Kind() != reflect.Slice {
 				log.Error().Interface("parameterContent", parsedParameterRawValue).Msg("[parseParameters] parsed parameter content should be slice")
-				return nil, errors.New("parsed parameter content should be slice")
+				return nil, nil, errors.New("parsed parameter content should be slice")
 			}
 			parameterSlice, err = parseSlice(k, parsedParameterRawValue.Interface())
 		case reflect.Slice:
@@ -604,14 +777,14 @@ func parseParameters(parameters map[string]interface{}, variablesMapping map[str
 			parameterSlice, err = parseSlice(k, rawValue.Interface())
 		default:
 			log.Error().Interface("parameter", parameters).Msg("[parseParameters] parameter content should be slice or text(functions call)")
-			return nil, errors.New("parameter content should be slice or text(functions call)")
+			return nil, nil, errors.New("parameter content should be slice or text(functions call)")
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		parsedParametersSlice[k] = parameterSlice
 	}
-	return parsedParametersSlice, nil
+	return parsedParametersSlice, parsedParametersStreams, nil
 }
 
 func parseSlice(parameterName string, parameterContent interface{}) ([]map[string]interface{}, error) {
@@ -622,48 +795,57 @@ func parseSlice(parameterName string, parameterContent interface{}) ([]map[strin
 		return nil, errors.New("parameterContent should be slice")
 	}
 	for i := 0; i < parameterContentSlice.Len(); i++ {
-		parameterMap := make(map[string]interface{})
-		elem := reflect.ValueOf(parameterContentSlice.Index(i).Interface())
-		switch elem.Kind() {
-		case reflect.Map:
-			// e.g. "username-password": [{"username": "test1", "password": "passwd1", "other": "111"}, {"username": "test2", "password": "passwd2", "other": ""222}]
-			// -> [{"username": "test1", "password": "passwd1"}, {"username": "test2", "password": "passwd2"}]
-			for _, key := range parameterNameSlice {
-				if _, ok := elem.Interface().(map[string]interface{})[key]; ok {
-					parameterMap[key] = elem.MapIndex(reflect.ValueOf(key)).Interface()
-				} else {
-					log.Error().Interface("parameterNameSlice", parameterNameSlice).Msg("[parseParameters] parameter name not found")
-					return nil, errors.New("parameter name not found")
-				}
-			}
-		case reflect.Slice:
-			// e.g. "username-password": [["test1", "passwd1"], ["test2", "passwd2"]]
-			// -> [{"username": "test1", "password": "passwd1"}, {"username": "test2", "password": "passwd2"}]
-			if len(parameterNameSlice) != elem.Len() {
-				log.Error().Interface("parameterNameSlice", parameterNameSlice).Interface("parameterContent", elem.Interface()).Msg("[parseParameters] parameter name slice and parameter content slice should have the same length")
-				return nil, errors.New("parameter name slice and parameter content slice should have the same length")
-			} else {
-				for j := 0; j < elem.Len(); j++ {
-					parameterMap[parameterNameSlice[j]] = elem.Index(j).Interface()
-				}
-			}
-		default:
-			// e.g. "app_version": [3.1, 3.0]
-			// -> [{"app_version": 3.1}, {"app_version": 3.0}]
-			if len(parameterNameSlice) != 1 {
-				log.Error().Interface("parameterNameSlice", parameterNameSlice).Msg("[parseParameters] parameter name slice should have only one element when parameter content is string")
-				return nil, errors.New("parameter name slice should have only one element when parameter content is string")
-			}
-			parameterMap[parameterNameSlice[0]] = elem.Interface()
+		parameterMap, err := parseParameterElement(parameterNameSlice, parameterContentSlice.Index(i).Interface())
+		if err != nil {
+			return nil, err
 		}
 		parameterSlice = append(parameterSlice, parameterMap)
 	}
 	return parameterSlice, nil
 }
 
-func initParameterIterator(cfg *TConfig, mode string) (err error) {
+// parseParameterElement converts one element of parameter content to parameters map with specified names
+func parseParameterElement(parameterNameSlice []string, parameterContent interface{}) (map[string]interface{}, error) {
+	parameterMap := make(map[string]interface{})
+	elem := reflect.ValueOf(parameterContent)
+	switch elem.Kind() {
+	case reflect.Map:
+		// e.g. "username-password": [{"username": "test1", "password": "passwd1", "other": "111"}, {"username": "test2", "password": "passwd2", "other": ""222}]
+		// -> [{"username": "test1", "password": "passwd1"}, {"username": "test2", "password": "passwd2"}]
+		for _, key := range parameterNameSlice {
+			value := elem.MapIndex(reflect.ValueOf(key))
+			if !value.IsValid() {
+				log.Error().Interface("parameterNameSlice", parameterNameSlice).Msg("[parseParameters] parameter name not found")
+				return nil, errors.Errorf("parameter name %s not found", key)
+			}
+			parameterMap[key] = value.Interface()
+		}
+	case reflect.Slice:
+		// e.g. "username-password": [["test1", "passwd1"], ["test2", "passwd2"]]
+		// -> [{"username": "test1", "password": "passwd1"}, {"username": "test2", "password": "passwd2"}]
+		if len(parameterNameSlice) != elem.Len() {
+			log.Error().Interface("parameterNameSlice", parameterNameSlice).Interface("parameterContent", elem.Interface()).Msg("[parseParameters] parameter name slice and parameter content slice should have the same length")
+			return nil, errors.New("parameter name slice and parameter content slice should have the same length")
+		}
+		for j := 0; j < elem.Len(); j++ {
+			parameterMap[parameterNameSlice[j]] = elem.Index(j).Interface()
+		}
+	default:
+		// e.g. "app_version": [3.1, 3.0]
+		// -> [{"app_version": 3.1}, {"app_version": 3.0}]
+		if len(parameterNameSlice) != 1 {
+			log.Error().Interface("parameterNameSlice", parameterNameSlice).Msg("[parseParameters] parameter name slice should have only one element when parameter content is string")
+			return nil, errors.New("parameter name slice should have only one element when parameter content is string")
+		}
+		parameterMap[parameterNameSlice[0]] = parameterContent
+	}
+	return parameterMap, nil
+}
+
+func initParameterIterator(p *parser, cfg *TConfig, mode string) (err error) {
 	var parameters map[string]paramsType
-	parameters, err = parseParameters(cfg.Parameters, cfg.Variables)
+	var streams map[string]*paramsStream
+	parameters, streams, err = p.parseParameters(cfg.Parameters, cfg.Variables)
 	if err != nil {
 		return err
 	}
@@ -675,6 +857,12 @@ func initParameterIterator(cfg *TConfig, mode string) (err error) {
 	if mode == "boomer" {
 		cfg.ParametersSetting.Iteration = -1
 	}
+	switch cfg.ParametersSetting.OnExhausted {
+	case "", exhaustedStopTest, exhaustedStopUser:
+	default:
+		return errors.Errorf("invalid on_exhausted %s, should be %s or %s",
+			cfg.ParametersSetting.OnExhausted, exhaustedStopTest, exhaustedStopUser)
+	}
 	rawValue := reflect.ValueOf(cfg.ParametersSetting.Strategy)
 	switch rawValue.Kind() {
 	case reflect.Map:
@@ -684,16 +872,29 @@ func initParameterIterator(cfg *TConfig, mode string) (err error) {
 				// use strategy if configured
 				cfg.ParametersSetting.Iterators = append(
 					cfg.ParametersSetting.Iterators,
-					newIterator(v, rawValue.MapIndex(reflect.ValueOf(k)).Interface().(string), cfg.ParametersSetting.Iteration),
+					newIterator(v.Iterator(), rawValue.MapIndex(reflect.ValueOf(k)).Interface().(string), cfg.ParametersSetting.Iteration),
 				)
 			} else {
 				// use sequential strategy by default
 				cfg.ParametersSetting.Iterators = append(
 					cfg.ParametersSetting.Iterators,
-					newIterator(v, strategySequential, cfg.ParametersSetting.Iteration),
+					newIterator(v.Iterator(), strategySequential, cfg.ParametersSetting.Iteration),
 				)
 			}
 		}
+		for k, v := range streams {
+			strategy := strategySequential
+			if s, ok := rawValue.Interface().(map[string]interface{})[k]; ok {
+				strategy = s.(string)
+			}
+			if !isStreamStrategy(strategy) {
+				return errors.Errorf("%s strategy is not supported for streamed parameters %s", strategy, k)
+			}
+			cfg.ParametersSetting.Iterators = append(
+				cfg.ParametersSetting.Iterators,
+				newIterator(v.Iterator(), strategy, cfg.ParametersSetting.Iteration),
+			)
+		}
 	case reflect.String:
 		// strategy: random, 仅生成一个的迭代器，该迭代器在参数笛卡尔积slice中随机选取元素
 		if len(rawValue.String()) == 0 {
@@ -701,24 +902,56 @@ func initParameterIterator(cfg *TConfig, mode string) (err error) {
 		} else {
 			cfg.ParametersSetting.Strategy = strings.ToLower(rawValue.String())
 		}
+		iter, err := newProductIterator(parameters, streams, cfg.ParametersSetting.Strategy.(string))
+		if err != nil {
+			return err
+		}
 		cfg.ParametersSetting.Iterators = append(
 			cfg.ParametersSetting.Iterators,
-			newIterator(genCartesianProduct(parameters), cfg.ParametersSetting.Strategy.(string), cfg.ParametersSetting.Iteration),
+			newIterator(iter, cfg.ParametersSetting.Strategy.(string), cfg.ParametersSetting.Iteration),
 		)
 	default:
 		// default strategy: sequential, 仅生成一个的迭代器，该迭代器在参数笛卡尔积slice中顺序选取元素
 		cfg.ParametersSetting.Strategy = strategySequential
+		iter, err := newProductIterator(parameters, streams, strategySequential)
+		if err != nil {
+			return err
+		}
 		cfg.ParametersSetting.Iterators = append(
 			cfg.ParametersSetting.Iterators,
-			newIterator(genCartesianProduct(parameters), cfg.ParametersSetting.Strategy.(string), cfg.ParametersSetting.Iteration),
+			newIterator(iter, cfg.ParametersSetting.Strategy.(string), cfg.ParametersSetting.Iteration),
 		)
 	}
 	return nil
 }
 
-func newIterator(parameters paramsType, strategy string, iteration int) *Iterator {
-	iter := parameters.Iterator()
-	iter.strategy = strategy
+// newProductIterator returns iterator of parameters cartesian product, streamed parameters could not be
+// combined with others since the product could not be generated lazily.
+func newProductIterator(parameters map[string]paramsType, streams map[string]*paramsStream, strategy string) (*Iterator, error) {
+	if len(streams) == 0 {
+		return genCartesianProduct(parameters).Iterator(), nil
+	}
+	if len(streams)+len(parameters) > 1 {
+		return nil, errors.New("streamed parameters could not be combined with other parameters, " +
+			"specify strategy for each parameter in parameters_setting instead")
+	}
+	if !isStreamStrategy(strategy) {
+		return nil, errors.Errorf("%s strategy is not supported for streamed parameters", strategy)
+	}
+	for _, stream := range streams {
+		return stream.Iterator(), nil
+	}
+	return nil, nil
+}
+
+// isStreamStrategy checks if strategy is supported by streamed parameters, which could only be read in order
+func isStreamStrategy(strategy string) bool {
+	strategy = strings.ToLower(strategy)
+	return strategy == strings.ToLower(strategySequential) || strategy == strategyOnce
+}
+
+func newIterator(iter *Iterator, strategy string, iteration int) *Iterator {
+	iter.strategy = strings.ToLower(strategy)
 	if iteration > 0 {
 		iter.iteration = iteration
 	} else if iteration < 0 {
@@ -726,5 +959,9 @@ func newIterator(parameters paramsType, strategy string, iteration int) *Iterato
 	} else if iter.iteration == 0 {
 		iter.iteration = 1
 	}
+	// each row is picked only once
+	if iter.strategy == strategyOnce && iter.rows() > 0 && iter.iteration > iter.rows() {
+		iter.iteration = iter.rows()
+	}
 	return iter
 }
@@ -2,7 +2,10 @@ package hrp
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,7 +71,7 @@ func TestRegexCompileAbnormalVariable(t *testing.T) {
 	}
 }
 
-func TestRegexCompileFunction(t *testing.T) {
+func TestMatchFunction(t *testing.T) {
 	testData := []string{
 		"${func1()}",
 		"${func1($a)}",
@@ -76,17 +79,19 @@ func TestRegexCompileFunction(t *testing.T) {
 		"${func1($a, 123)}",
 		"${func1(123, $b)}",
 		"abc${func1(123, $b)}123",
+		"${func1('a,b', \"c)\")}",
+		"${func1(${func2($a)}, [1, 2], {\"k\": \"}\"})}",
 	}
 
 	for _, expr := range testData {
-		varMatched := regexCompileFunction.FindStringSubmatch(expr)
-		if !assert.Len(t, varMatched, 3) {
+		_, _, _, ok := matchFunction(expr[strings.Index(expr, "$"):])
+		if !assert.True(t, ok) {
 			t.Fail()
 		}
 	}
 }
 
-func TestRegexCompileAbnormalFunction(t *testing.T) {
+func TestMatchAbnormalFunction(t *testing.T) {
 	testData := []string{
 		"${func1()",
 		"${func1(}",
@@ -95,14 +100,16 @@ func TestRegexCompileAbnormalFunction(t *testing.T) {
 		"${1func1()}", // function name can not start with number
 		"${func1($a}",
 		"abc$func1(123, $b)}123",
+		"${func1('a)}",
+		"${func1(1) + 1}", // expression
 		// "${func1($a $b)}",
 		// "${func1($a, $123)}",
 		// "${func1(123 $b)}",
 	}
 
 	for _, expr := range testData {
-		varMatched := regexCompileFunction.FindStringSubmatch(expr)
-		if !assert.Len(t, varMatched, 0) {
+		_, _, _, ok := matchFunction(expr[strings.Index(expr, "$"):])
+		if !assert.False(t, ok) {
 			t.Fail()
 		}
 	}
@@ -524,6 +531,20 @@ func TestParseFunctionArguments(t *testing.T) {
 		{"1, -2.3", []interface{}{1, -2.3}},
 		{"1,,2", []interface{}{1, nil, 2}},
 		{" $var1 , 2 ", []interface{}{"$var1", 2}},
+		{"'a,b', \"c)d\"", []interface{}{quotedString("a,b"), quotedString("c)d")}},
+		{`'it\'s', "say \"hi\""`, []interface{}{quotedString("it's"), quotedString(`say "hi"`)}},
+		{"'123'", []interface{}{quotedString("123")}},
+		{"'$5', ' a '", []interface{}{quotedString("$5"), quotedString(" a ")}},
+		{"http://a.com/?x=1&y=2, /api/$id", []interface{}{"http://a.com/?x=1&y=2", "/api/$id"}},
+		{"true, false, null", []interface{}{true, false, nil}},
+		{"[1, 'a', $b, []]", []interface{}{[]interface{}{1, quotedString("a"), "$b", []interface{}{}}}},
+		{`{"a": 1, b: [2, {c: 'd'}]}`, []interface{}{
+			map[string]interface{}{"a": 1, "b": []interface{}{2, map[string]interface{}{"c": quotedString("d")}}},
+		}},
+		{"${f(${g($a)}, 1)}, ${h()}", []interface{}{"${f(${g($a)}, 1)}", "${h()}"}},
+		{"1, key=value, n = 2", []interface{}{1, keywordArguments{"key": "value", "n": 2}}},
+		{"data={'a': [1]}", []interface{}{keywordArguments{"data": map[string]interface{}{"a": []interface{}{1}}}}},
+		{"$a==1", []interface{}{"$a==1"}},
 	}
 
 	for _, data := range testData {
@@ -537,6 +558,26 @@ func TestParseFunctionArguments(t *testing.T) {
 	}
 }
 
+func TestParseFunctionArgumentsAbnormal(t *testing.T) {
+	testData := []string{
+		"'abc",
+		"'a' b",
+		"[1, 2",
+		"{a 1}",
+		"[abc]",
+		"a=1, 2",
+		"a=1, a=2",
+		"1)",
+	}
+
+	for _, expr := range testData {
+		_, err := parseFunctionArguments(expr)
+		if !assert.Error(t, err, expr) {
+			t.Fail()
+		}
+	}
+}
+
 func TestParseDataStringWithFunctions(t *testing.T) {
 	variablesMapping := map[string]interface{}{
 		"n": 5,
@@ -572,6 +613,9 @@ func TestParseDataStringWithFunctions(t *testing.T) {
 		{"${max($a, $b)}", 12.3},
 		{"abc${max($a, $b)}123", "abc12.3123"},
 		{"abc${max($a, 3.45)}123", "abc12.3123"},
+		{"${max(${max($b, 1)}, 2)}", 3.45},
+		{"${md5('a,b')}", "b345e1dc09f20fdefdea469f09167892"},
+		{"id-${md5(\"a,b\")}", "id-b345e1dc09f20fdefdea469f09167892"},
 	}
 
 	for _, data := range testData2 {
@@ -585,6 +629,76 @@ func TestParseDataStringWithFunctions(t *testing.T) {
 	}
 }
 
+func TestParseDataWithFunctionArguments(t *testing.T) {
+	variablesMapping := map[string]interface{}{"a": 5}
+	parser := newParser()
+	parser.functions = map[string]interface{}{
+		"concat": func(args ...interface{}) string { return fmt.Sprint(args...) },
+		"opts":   func(name string, options map[string]interface{}) string { return fmt.Sprint(name, options) },
+		"pair":   func(a, b interface{}) string { return fmt.Sprint(a, b) },
+	}
+
+	testData := []struct {
+		expr   string
+		expect interface{}
+	}{
+		// quoted arguments are used literally
+		{"${concat('$a', \"${concat(1)}\", ' x ')}", "$a${concat(1)} x "},
+		{"${concat($a, ['$a', $a])}", "5 [$a 5]"},
+		{"${opts('$a', k='$a')}", "$amap[k:$a]"},
+		{"${concat(k=$a)}", "map[k:5]"},
+	}
+	for _, data := range testData {
+		value, err := parser.parseData(data.expr, variablesMapping)
+		if !assert.NoError(t, err, data.expr) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expect, value, data.expr) {
+			t.Fail()
+		}
+	}
+
+	// keyword arguments are not shifted into positional parameters
+	for _, expr := range []string{"${pair(1, k=2)}", "${opts(k=2)}", "${md5(k='abc')}"} {
+		if _, err := parser.parseData(expr, variablesMapping); !assert.Error(t, err, expr) {
+			t.Fail()
+		}
+	}
+}
+
+func TestParseDataWithBuiltinFunctions(t *testing.T) {
+	variablesMapping := map[string]interface{}{
+		"token": "abc",
+		"items": []interface{}{"x"},
+	}
+
+	testData := []struct {
+		expr   string
+		expect interface{}
+	}{
+		{"${sha1($token)}", "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{"${base64_decode(${base64_encode($token)})}", "abc"},
+		{"${url_encode('a b&c')}", "a+b%26c"},
+		{"${hmac(md5, key, 'The quick brown fox jumps over the lazy dog')}", "80070713463e7749b90c2dc24911e275"},
+		{"${random_int(3, 3)}", 3},
+		{"${random_choice($items)}", "x"},
+		{`${json_loads('{"a": [1, 2.5]}')}`, map[string]interface{}{"a": []interface{}{1, 2.5}}},
+		{`${json_dumps({"a": $token})}`, `{"a":"abc"}`},
+		{"${get_time(date, -1d)}", time.Now().AddDate(0, 0, -1).Format("2006-01-02")},
+	}
+
+	parser := newParser()
+	for _, data := range testData {
+		value, err := parser.parseData(data.expr, variablesMapping)
+		if !assert.NoError(t, err, data.expr) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expect, value, data.expr) {
+			t.Fail()
+		}
+	}
+}
+
 func TestConvertString(t *testing.T) {
 	testData := []struct {
 		raw    interface{}
@@ -619,6 +733,10 @@ func TestParseVariables(t *testing.T) {
 			map[string]interface{}{"n": 34.5, "a": 12.3, "b": "$n", "varFoo2": "${max($a, $b)}"},
 			map[string]interface{}{"n": 34.5, "a": 12.3, "b": 34.5, "varFoo2": 34.5},
 		},
+		{
+			map[string]interface{}{"total": "${ $price * $count }", "price": "$p", "p": 2.5, "count": 4},
+			map[string]interface{}{"total": 10.0, "price": 2.5, "p": 2.5, "count": 4},
+		},
 	}
 
 	parser := newParser()
@@ -722,6 +840,12 @@ func TestFindallVariables(t *testing.T) {
 		{"${func()}", nil},
 		{"a${func(1,2)}b", nil},
 		{"${gen_md5($TOKEN, $data, $random)}", []string{"TOKEN", "data", "random"}},
+		{"${ $a + $b * 2 }", []string{"a", "b"}},
+		{"${f($a, ${g($b, [$c])})}-$d", []string{"a", "b", "c", "d"}},
+		{"${f('$a', ${g(\"$b\", [$c])})}", []string{"c"}},
+		{"${f(key=$a)}", []string{"a"}},
+		{"id-${ $resp.items[0].id }-$c", []string{"c", "resp"}},
+		{"${ $vip ? '$gold' : len($items) }", []string{"items", "vip"}},
 	}
 
 	for _, data := range testData {
@@ -736,6 +860,27 @@ func TestFindallVariables(t *testing.T) {
 	}
 }
 
+func TestFindallFunctions(t *testing.T) {
+	testData := []struct {
+		raw             string
+		expectFunctions []string
+	}{
+		{"", nil},
+		{"$var/${var}", nil},
+		{"$${func()}", nil},
+		{"a${func(1,2)}b", []string{"func"}},
+		{"${f($a, ${g('$b', [$c])})}-${h()}", []string{"f", "g", "h"}},
+		{"${ max($a, 1) > 0 && $b.len() > 0 }", []string{"max"}},
+		{"${f('${g()}')}", []string{"f"}},
+	}
+
+	for _, data := range testData {
+		if !assert.Equal(t, data.expectFunctions, findallFunctions(data.raw), data.raw) {
+			t.Fail()
+		}
+	}
+}
+
 func TestParseParameters(t *testing.T) {
 	testData := []struct {
 		rawVars      map[string]interface{}
@@ -769,7 +914,7 @@ func TestParseParameters(t *testing.T) {
 		},
 	}
 	for _, data := range testData {
-		params, _ := parseParameters(data.rawVars, map[string]interface{}{})
+		params, _, _ := newParser().parseParameters(data.rawVars, map[string]interface{}{})
 		value := genCartesianProduct(params)
 		if !assert.Len(t, value, data.expectLength) {
 			t.Fail()
@@ -777,6 +922,34 @@ func TestParseParameters(t *testing.T) {
 	}
 }
 
+func TestParseParametersWithParserFunctions(t *testing.T) {
+	// functions of parser are used, e.g. seeded random functions of virtual user
+	p := newParser()
+	p.functions = map[string]interface{}{
+		"gen_users": func() []interface{} { return []interface{}{"u1", "u2"} },
+	}
+	params, _, err := p.parseParameters(map[string]interface{}{"user": "${gen_users()}"}, map[string]interface{}{})
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	expected := paramsType{{"user": "u1"}, {"user": "u2"}}
+	if !assert.Equal(t, expected, params["user"]) {
+		t.Fail()
+	}
+
+	// seeded virtual users generate same parameters
+	seed := int64(2022)
+	raw := map[string]interface{}{"code": "${random_choice([[1, 2], [3, 4], [5, 6], [7, 8], [9, 10]])}"}
+	p1, p2 := newParser(), newParser()
+	p1.functions = newVirtualUser(&seed, 0).functions
+	p2.functions = newVirtualUser(&seed, 0).functions
+	params1, _, err1 := p1.parseParameters(raw, map[string]interface{}{})
+	params2, _, err2 := p2.parseParameters(raw, map[string]interface{}{})
+	if !assert.NoError(t, err1) || !assert.NoError(t, err2) || !assert.Equal(t, params1, params2) {
+		t.Fail()
+	}
+}
+
 func TestParseParametersError(t *testing.T) {
 	testData := []struct {
 		rawVars map[string]interface{}
@@ -796,15 +969,165 @@ func TestParseParametersError(t *testing.T) {
 				"username-password": fmt.Sprintf("${param(%s/account.csv)}", hrpExamplesDir),
 				"user_agent":        []interface{}{"IOS/10.1", "IOS/10.2"}},
 		},
+		{
+			map[string]interface{}{
+				"username-password": fmt.Sprintf("${parameterize(%s/not_found.csv)}", hrpExamplesDir)},
+		},
+		{
+			map[string]interface{}{
+				"username-password": fmt.Sprintf("${parameterize(%s/account.csv, stream=yes)}", hrpExamplesDir)},
+		},
+		{
+			map[string]interface{}{
+				"user": fmt.Sprintf("${parameterize(%s/account.csv, stream=true)}", hrpExamplesDir)},
+		},
 	}
 	for _, data := range testData {
-		_, err := parseParameters(data.rawVars, map[string]interface{}{})
+		_, _, err := newParser().parseParameters(data.rawVars, map[string]interface{}{})
 		if !assert.Error(t, err) {
 			t.Fail()
 		}
 	}
 }
 
+func TestInitParameterIteratorWithStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.jsonl")
+	content := `{"username": "u1", "age": 18}` + "\n" + `{"username": "u2", "age": 20}` + "\n" + `["u3", 22]` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stream := fmt.Sprintf("${parameterize(%s, stream=true)}", path)
+
+	cfg := &TConfig{
+		Parameters: map[string]interface{}{"username-age": stream},
+	}
+	if err := initParameterIterator(newParser(), cfg, "runner"); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	iter := cfg.ParametersSetting.Iterators[0]
+	var values []map[string]interface{}
+	for iter.HasNext() {
+		values = append(values, iter.Next())
+	}
+	expected := []map[string]interface{}{
+		{"username": "u1", "age": 18},
+		{"username": "u2", "age": 20},
+		{"username": "u3", "age": 22},
+	}
+	if !assert.Equal(t, expected, values) {
+		t.Fail()
+	}
+	// stream is read from the beginning again once exhausted
+	if !assert.Equal(t, expected[0], iter.Next()) {
+		t.Fail()
+	}
+	cfg.ParametersSetting.closeIterators()
+
+	// error is returned if parameters file could not be read
+	cfg = &TConfig{
+		Parameters: map[string]interface{}{"username-age": stream},
+	}
+	if err := initParameterIterator(newParser(), cfg, "runner"); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	cfg.ParametersSetting.closeIterators()
+	if _, _, err := cfg.ParametersSetting.Iterators[0].next(nil); !assert.Error(t, err) {
+		t.Fail()
+	}
+
+	// streamed parameters could not be combined in cartesian product or picked randomly
+	cfg = &TConfig{
+		Parameters: map[string]interface{}{
+			"username-age": stream,
+			"user_agent":   []interface{}{"IOS/10.1", "IOS/10.2"},
+		},
+	}
+	if err := initParameterIterator(newParser(), cfg, "runner"); !assert.Error(t, err) {
+		t.Fail()
+	}
+	for _, strategy := range []string{"random", "unique", "vu_sequential"} {
+		cfg = &TConfig{
+			Parameters:        map[string]interface{}{"username-age": stream},
+			ParametersSetting: &TParamsConfig{Strategy: strategy},
+		}
+		if err := initParameterIterator(newParser(), cfg, "runner"); !assert.Error(t, err) {
+			t.Fail()
+		}
+	}
+
+	// each parameter has its own iterator with strategy map
+	cfg = &TConfig{
+		Parameters: map[string]interface{}{
+			"username-age": stream,
+			"user_agent":   []interface{}{"IOS/10.1", "IOS/10.2"},
+		},
+		ParametersSetting: &TParamsConfig{Strategy: map[string]interface{}{"user_agent": "random"}},
+	}
+	if err := initParameterIterator(newParser(), cfg, "boomer"); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Len(t, cfg.ParametersSetting.Iterators, 2) {
+		t.Fail()
+	}
+}
+
+func TestIteratorStrategies(t *testing.T) {
+	params := paramsType{}
+	for i := 0; i < 5; i++ {
+		params = append(params, map[string]interface{}{"index": i})
+	}
+	vus := []*virtualUser{newVirtualUser(nil, 1), newVirtualUser(nil, 2)}
+	pick := func(iter *Iterator, count int) map[int][]interface{} {
+		picked := make(map[int][]interface{})
+		for i := 0; i < count; i++ {
+			vu := vus[i%len(vus)]
+			if value, ok, _ := iter.next(vu); ok {
+				picked[vu.id] = append(picked[vu.id], value["index"])
+			}
+		}
+		return picked
+	}
+
+	// rows are partitioned among virtual users
+	iter := newIterator(params.Iterator(), strategyUnique, -1)
+	if !assert.NoError(t, iter.setUsers(2)) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, map[int][]interface{}{1: {0, 2, 4, 0}, 2: {1, 3, 1, 3}}, pick(iter, 8)) {
+		t.Fail()
+	}
+	if !assert.Error(t, newIterator(params.Iterator(), strategyUnique, -1).setUsers(6)) {
+		t.Fail()
+	}
+
+	// each row is picked only once
+	iter = newIterator(params.Iterator(), strategyOnce, -1)
+	if !assert.Equal(t, map[int][]interface{}{1: {0, 2, 4}, 2: {1, 3}}, pick(iter, 8)) {
+		t.Fail()
+	}
+	if _, ok, _ := iter.next(vus[0]); !assert.False(t, ok) {
+		t.Fail()
+	}
+	if !assert.Equal(t, 5, newIterator(params.Iterator(), strategyOnce, 10).iteration) {
+		t.Fail()
+	}
+
+	// each virtual user has its own cursor
+	iter = newIterator(params.Iterator(), strategyVUSequential, -1)
+	if !assert.Equal(t, map[int][]interface{}{1: {0, 1, 2, 3}, 2: {0, 1, 2, 3}}, pick(iter, 8)) {
+		t.Fail()
+	}
+
+	// invalid on_exhausted
+	cfg := &TConfig{
+		Parameters:        map[string]interface{}{"index": []interface{}{1, 2}},
+		ParametersSetting: &TParamsConfig{Strategy: "once", OnExhausted: "stop"},
+	}
+	if err := initParameterIterator(newParser(), cfg, "boomer"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
 func TestParseSlice(t *testing.T) {
 	testData := []struct {
 		rawVar1 string
@@ -0,0 +1,47 @@
+package hrp
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+)
+
+// seedStride separates seeds of virtual users derived from the run seed
+const seedStride = 1000003
+
+// defaultRandom is used to pick parameters randomly if no virtual user is specified
+var defaultRandom = builtin.NewRandom(time.Now().UnixNano())
+
+// virtualUser holds random generator of one virtual user, parameters iteration, random builtin functions
+// and think time randomization of the virtual user are reproducible once the run seed is specified.
+type virtualUser struct {
+	id        int
+	random    *rand.Rand
+	functions map[string]interface{} // random builtin functions using random generator of the virtual user
+}
+
+// newVirtualUser creates virtual user with seed derived from the run seed, seed of virtual user 0 equals
+// the run seed, which is used by hrp run. Seed is generated from current time if the run seed is not specified.
+func newVirtualUser(seed *int64, id int) *virtualUser {
+	var vuSeed int64
+	if seed != nil {
+		vuSeed = *seed + int64(id)*seedStride
+	} else {
+		vuSeed = time.Now().UnixNano() + int64(id)
+	}
+	random := builtin.NewRandom(vuSeed)
+	return &virtualUser{
+		id:        id,
+		random:    random,
+		functions: builtin.NewRandomFunctions(random),
+	}
+}
+
+// randomNumber returns random integer in [min, max], 0 is returned if min is greater than max
+func (vu *virtualUser) randomNumber(min, max int) int {
+	if min > max {
+		return 0
+	}
+	return min + vu.random.Intn(max-min+1)
+}
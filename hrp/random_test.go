@@ -0,0 +1,85 @@
+package hrp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+)
+
+func TestVirtualUserReproducible(t *testing.T) {
+	params := paramsType{}
+	for i := 0; i < 100; i++ {
+		params = append(params, map[string]interface{}{"index": i})
+	}
+
+	generate := func(vu *virtualUser) []interface{} {
+		iter := newIterator(params.Iterator(), strategyRandom, 5)
+		var values []interface{}
+		for iter.HasNext() {
+			value, _, _ := iter.next(vu)
+			values = append(values, value["index"])
+		}
+
+		p := newParser()
+		p.functions = vu.functions
+		for _, expr := range []string{"${random_int(0, 1000000)}", "${fake_name(zh_CN)}", "${uuid4()}"} {
+			value, err := p.parseString(expr, map[string]interface{}{})
+			if !assert.NoError(t, err) {
+				t.Fail()
+			}
+			values = append(values, value)
+		}
+		return append(values, vu.randomNumber(0, 1000000))
+	}
+
+	seed := int64(2022)
+	first := generate(newVirtualUser(&seed, 1))
+	if !assert.Equal(t, first, generate(newVirtualUser(&seed, 1))) {
+		t.Fail()
+	}
+	// virtual users have different random data
+	if !assert.NotEqual(t, first, generate(newVirtualUser(&seed, 2))) {
+		t.Fail()
+	}
+	// random data are not reproducible without seed
+	if !assert.NotEqual(t, generate(newVirtualUser(nil, 1)), generate(newVirtualUser(nil, 1))) {
+		t.Fail()
+	}
+}
+
+func TestRunnerSetSeed(t *testing.T) {
+	r1 := NewRunner(t).SetSeed(1)
+	r2 := NewRunner(t).SetSeed(1)
+	testcase := &TestCase{Config: NewConfig("seed")}
+	c1 := r1.newCaseRunner(testcase)
+	c2 := r2.newCaseRunner(testcase)
+
+	v1, err := c1.parser.parseString("${gen_random_string(16)}", nil)
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	v2, err := c2.parser.parseString("${gen_random_string(16)}", nil)
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, v1, v2) {
+		t.Fail()
+	}
+}
+
+func TestRunnerFrozenTime(t *testing.T) {
+	defer builtin.ResetClock()
+
+	frozen := time.Date(2022, 3, 8, 15, 4, 5, 0, time.UTC)
+	NewRunner(t).SetFrozenTime(frozen)
+	value, err := newParser().parseString("${get_timestamp()}", nil)
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Equal(t, frozen.UnixNano()/int64(time.Millisecond), value) {
+		t.Fail()
+	}
+}
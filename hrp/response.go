@@ -2,7 +2,6 @@ package hrp
 
 import (
 	"bytes"
-	builtinJSON "encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,7 +14,9 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/httprunner/httprunner/hrp/internal/builtin"
+	"github.com/httprunner/httprunner/hrp/internal/dom"
 	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/redact"
 )
 
 func newResponseObject(t *testing.T, parser *parser, resp *http.Response) (*responseObject, error) {
@@ -40,10 +41,15 @@ func newResponseObject(t *testing.T, parser *parser, resp *http.Response) (*resp
 	}
 
 	// parse response body
+	contentType := resp.Header.Get("Content-Type")
 	var body interface{}
+	var document *dom.Document
 	if err := json.Unmarshal(respBodyBytes, &body); err != nil {
-		// response body is not json, use raw body
-		body = string(respBodyBytes)
+		// response body is not json, convert xml body to map or use raw body
+		body, document = parseXMLBody(contentType, respBodyBytes)
+		if body == nil {
+			body = string(respBodyBytes)
+		}
 	}
 
 	respObjMeta := respObjMeta{
@@ -52,7 +58,38 @@ func newResponseObject(t *testing.T, parser *parser, resp *http.Response) (*resp
 		Cookies:    cookies,
 		Body:       body,
 	}
+	respObj, err := newResponseObjectWithMeta(t, parser, respObjMeta)
+	if err != nil {
+		return nil, err
+	}
+	respObj.rawBody = respBodyBytes
+	respObj.contentType = contentType
+	respObj.document = document
+	return respObj, nil
+}
+
+// newSocketResponseObject wraps bytes received from socket in the same respObjMeta shape,
+// thus the received data could be extracted and validated the same way as HTTP response body.
+func newSocketResponseObject(t *testing.T, parser *parser, received []byte) (*responseObject, error) {
+	var body interface{}
+	if err := json.Unmarshal(received, &body); err != nil {
+		// received data is not json, use raw text
+		body = string(received)
+	}
+	respObjMeta := respObjMeta{
+		Headers: map[string]string{},
+		Cookies: map[string]string{},
+		Body:    body,
+	}
+	respObj, err := newResponseObjectWithMeta(t, parser, respObjMeta)
+	if err != nil {
+		return nil, err
+	}
+	respObj.rawBody = received
+	return respObj, nil
+}
 
+func newResponseObjectWithMeta(t *testing.T, parser *parser, respObjMeta respObjMeta) (*responseObject, error) {
 	// convert respObjMeta to interface{}
 	respObjMetaBytes, _ := json.Marshal(respObjMeta)
 	var data interface{}
@@ -85,120 +122,287 @@ type responseObject struct {
 	parser            *parser
 	respObjMeta       interface{}
 	validationResults []*validationResult
+	contract          *contract // validate against openapi operation if set
+	caseDir           string    // directory of testcase file, used to locate json schema and snapshot files
+	updateSnapshots   bool      // rewrite snapshot files with current values
+	softAssert        bool      // evaluate all validators instead of returning on the first failure
+	rawBody           []byte    // raw response body, used in regexp, xpath and css selector queries
+	contentType       string
+	document          *dom.Document    // parsed XML/HTML document of response body, parsed lazily
+	redactor          *redact.Redactor // masks values of sensitive variables in logs
 }
 
 const textExtractorSubRegexp string = `(.*)`
 
 func (v *responseObject) extractField(value string) interface{} {
 	var result interface{}
-	if strings.Contains(value, textExtractorSubRegexp) {
+	switch {
+	case strings.HasPrefix(value, xpathExprPrefix):
+		result = v.searchXPath(value)
+	case strings.HasPrefix(value, cssExprPrefix):
+		result = v.searchCSS(value)
+	case strings.HasPrefix(value, jsonPathExprPrefix):
+		result = v.searchJSONPath(value)
+	case strings.Contains(value, textExtractorSubRegexp):
 		result = v.searchRegexp(value)
-	} else {
+	default:
 		result = v.searchJmespath(value)
 	}
 	return result
 }
 
-func (v *responseObject) Extract(extractors map[string]string) map[string]interface{} {
-	if extractors == nil {
-		return nil
+// Extract extracts variables from response with expression strings and extractor objects, extractor object
+// overrides expression string with the same variable name. For expression string, the expression itself is
+// returned if searching failed, which is kept for compatibility.
+func (v *responseObject) Extract(extractors map[string]string, typedExtractors map[string]Extractor) (map[string]interface{}, error) {
+	if extractors == nil && typedExtractors == nil {
+		return nil, nil
 	}
 
 	extractMapping := make(map[string]interface{})
-	for key, value := range extractors {
-		extractedValue := v.extractField(value)
-		log.Info().Str("from", value).Interface("value", extractedValue).Msg("extract value")
-		log.Info().Str("variable", key).Interface("value", extractedValue).Msg("set variable")
-		extractMapping[key] = extractedValue
+	for key, expr := range extractors {
+		if _, ok := typedExtractors[key]; ok {
+			continue
+		}
+		v.setExtracted(extractMapping, key, expr, v.extractField(expr))
+	}
+	errs := make(map[string]error)
+	for key, extractor := range typedExtractors {
+		extractor := extractor
+		extractedValue, err := v.extract(&extractor)
+		if err != nil {
+			log.Error().Str("variable", key).Err(err).Msg("extract value failed")
+			errs[key] = err
+			continue
+		}
+		v.setExtracted(extractMapping, key, extractor.Expr, extractedValue)
+	}
+	if len(errs) > 0 {
+		return extractMapping, &extractError{errs: errs}
 	}
+	return extractMapping, nil
+}
 
-	return extractMapping
+func (v *responseObject) setExtracted(extractMapping map[string]interface{}, key, from string, value interface{}) {
+	masked := v.redactor.MaskVariable(key, value)
+	log.Info().Str("from", from).Interface("value", masked).Msg("extract value")
+	log.Info().Str("variable", key).Interface("value", masked).Msg("set variable")
+	extractMapping[key] = value
 }
 
+// Validate validates response with validators, returns on the first failed validator by default.
+// In soft assert mode, all validators are evaluated and failures are aggregated in the returned error.
 func (v *responseObject) Validate(iValidators []interface{}, variablesMapping map[string]interface{}) (err error) {
+	var errs []error
 	for _, iValidator := range iValidators {
 		validator, ok := iValidator.(Validator)
 		if !ok {
-			return errors.New("validator type error")
+			err = errors.New("validator type error")
+		} else {
+			err = v.validate(validator, variablesMapping)
+		}
+		if err == nil {
+			continue
 		}
-		// parse check value
-		checkItem := validator.Check
-		var checkValue interface{}
-		if strings.Contains(checkItem, "$") {
-			// reference variable
-			checkValue, err = v.parser.parseData(checkItem, variablesMapping)
-			if err != nil {
+		if !v.softAssert {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	if v.contract != nil {
+		if err := v.validateContract(); err != nil {
+			if !v.softAssert {
 				return err
 			}
-		} else {
-			// regExp or jmesPath
-			checkValue = v.extractField(checkItem)
+			errs = append(errs, err)
 		}
+	}
+	if len(errs) > 0 {
+		return &softAssertError{errs: errs}
+	}
+	return nil
+}
 
-		// get assert method
-		assertMethod := validator.Assert
-		assertFunc, ok := builtin.Assertions[assertMethod]
-		if !ok {
-			return errors.New(fmt.Sprintf("unexpected assertMethod: %v", assertMethod))
-		}
+func (v *responseObject) validate(validator Validator, variablesMapping map[string]interface{}) (err error) {
+	if isCombinator(validator) {
+		return v.validateCombinator(validator, variablesMapping)
+	}
 
-		// parse expected value
-		expectValue, err := v.parser.parseData(validator.Expect, variablesMapping)
+	// parse check value
+	checkItem := validator.Check
+	var checkValue interface{}
+	if !isSelectorExpr(checkItem) && strings.Contains(checkItem, "$") {
+		// reference variable
+		checkValue, err = v.parser.parseData(checkItem, variablesMapping)
 		if err != nil {
 			return err
 		}
-		validResult := &validationResult{
-			Validator: Validator{
-				Check:   validator.Check,
-				Expect:  expectValue,
-				Assert:  assertMethod,
-				Message: validator.Message,
-			},
-			CheckValue:  checkValue,
-			CheckResult: "fail",
-		}
+	} else {
+		// regExp, jmesPath, xpath or css selector
+		checkValue = v.extractField(checkItem)
+	}
 
-		// do assertion
-		result := assertFunc(v.t, checkValue, expectValue)
-		if result {
-			validResult.CheckResult = "pass"
+	// get assert method
+	assertMethod := validator.Assert
+	if assertMethod == assertJSONSchema {
+		return v.validateJSONSchema(validator, checkValue, variablesMapping)
+	}
+	if assertMethod == assertSnapshot {
+		return v.validateSnapshot(validator, checkValue, variablesMapping)
+	}
+	assertFunc, ok := builtin.Assertions[assertMethod]
+	if !ok {
+		// fallback to custom assertion function defined in plugin
+		if v.parser.plugin != nil && v.parser.plugin.Has(assertMethod) {
+			return v.validateWithPlugin(validator, checkValue, variablesMapping)
 		}
-		v.validationResults = append(v.validationResults, validResult)
-		log.Info().
-			Str("checkExpr", validator.Check).
-			Str("assertMethod", assertMethod).
-			Interface("expectValue", expectValue).
-			Interface("checkValue", checkValue).
-			Bool("result", result).
-			Msgf("validate %s", checkItem)
-		if !result {
-			v.t.Fail()
-			return errors.New(fmt.Sprintf(
-				"do assertion failed, checkExpr: %v, assertMethod: %v, checkValue: %v, expectValue: %v",
-				validator.Check,
-				assertMethod,
-				checkValue,
-				expectValue,
-			))
+		return errors.New(fmt.Sprintf("unexpected assertMethod: %v", assertMethod))
+	}
+
+	// parse expected value
+	expectValue, err := v.parser.parseData(validator.Expect, variablesMapping)
+	if err != nil {
+		return err
+	}
+	validResult := &validationResult{
+		Validator: Validator{
+			Check:   validator.Check,
+			Expect:  expectValue,
+			Assert:  assertMethod,
+			Message: validator.Message,
+		},
+		CheckValue:  checkValue,
+		CheckResult: "fail",
+	}
+
+	// do assertion
+	result := assertFunc(v.t, checkValue, expectValue)
+	if result {
+		validResult.CheckResult = "pass"
+	}
+	v.validationResults = append(v.validationResults, validResult)
+	log.Info().
+		Str("checkExpr", validator.Check).
+		Str("assertMethod", assertMethod).
+		Interface("expectValue", expectValue).
+		Interface("checkValue", checkValue).
+		Bool("result", result).
+		Msgf("validate %s", checkItem)
+	if !result {
+		v.t.Fail()
+		return errors.New(fmt.Sprintf(
+			"do assertion failed, checkExpr: %v, assertMethod: %v, checkValue: %v, expectValue: %v",
+			validator.Check,
+			assertMethod,
+			checkValue,
+			expectValue,
+		))
+	}
+	return nil
+}
+
+// validateWithPlugin calls custom assertion function in plugin with check value and expect value,
+// the function should return bool, or error message string which is empty if assertion passed.
+// Error returned by plugin function is also regarded as assertion failure message.
+func (v *responseObject) validateWithPlugin(validator Validator, checkValue interface{},
+	variablesMapping map[string]interface{}) error {
+
+	assertMethod := validator.Assert
+	expectValue, err := v.parser.parseData(validator.Expect, variablesMapping)
+	if err != nil {
+		return err
+	}
+
+	var result bool
+	var message string
+	ret, err := v.parser.plugin.Call(assertMethod, checkValue, expectValue)
+	switch r := ret.(type) {
+	case bool:
+		result = r
+	case string:
+		result = r == ""
+		message = r
+	case nil:
+		result = true
+	default:
+		message = fmt.Sprintf("unexpected return value of assertion function: %v", ret)
+	}
+	if err != nil {
+		result = false
+		message = err.Error()
+	}
+
+	validResult := &validationResult{
+		Validator: Validator{
+			Check:   validator.Check,
+			Expect:  expectValue,
+			Assert:  assertMethod,
+			Message: validator.Message,
+		},
+		CheckValue:  checkValue,
+		CheckResult: "pass",
+	}
+	if !result {
+		validResult.CheckResult = "fail"
+		if message != "" && validator.Message != "" {
+			validResult.Message = fmt.Sprintf("%s, %s", validator.Message, message)
+		} else if message != "" {
+			validResult.Message = message
 		}
 	}
+	v.validationResults = append(v.validationResults, validResult)
+	log.Info().
+		Str("checkExpr", validator.Check).
+		Str("assertMethod", assertMethod).
+		Interface("expectValue", expectValue).
+		Interface("checkValue", checkValue).
+		Bool("result", result).
+		Str("message", message).
+		Msgf("validate %s with plugin", validator.Check)
+	if !result {
+		v.t.Fail()
+		return errors.New(fmt.Sprintf(
+			"do assertion failed, checkExpr: %v, assertMethod: %v, checkValue: %v, expectValue: %v, message: %v",
+			validator.Check,
+			assertMethod,
+			checkValue,
+			expectValue,
+			message,
+		))
+	}
 	return nil
 }
 
+// softAssertError aggregates errors of all failed validators in soft assert mode
+type softAssertError struct {
+	errs []error
+}
+
+func (e *softAssertError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d validators failed", len(e.errs))
+	for i, err := range e.errs {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, err.Error())
+	}
+	return b.String()
+}
+
 func (v *responseObject) searchJmespath(expr string) interface{} {
 	checkValue, err := jmespath.Search(expr, v.respObjMeta)
 	if err != nil {
 		log.Error().Str("expr", expr).Err(err).Msg("search jmespath failed")
 		return expr // jmespath not found, return the expression
 	}
-	if number, ok := checkValue.(builtinJSON.Number); ok {
-		checkNumber, err := parseJSONNumber(number)
-		if err != nil {
-			log.Error().Interface("json number", number).Err(err).Msg("convert json number failed")
-		}
-		return checkNumber
+	return convertJSONNumber(checkValue)
+}
+
+func (v *responseObject) searchJSONPath(expr string) interface{} {
+	value, err := v.queryJSONPath(expr)
+	if err != nil {
+		log.Error().Str("expr", expr).Err(err).Msg("search jsonpath failed")
+		return expr // jsonpath not found, return the expression
 	}
-	return checkValue
+	return value
 }
 
 func (v *responseObject) searchRegexp(expr string) interface{} {
@@ -208,6 +412,10 @@ func (v *responseObject) searchRegexp(expr string) interface{} {
 		return expr
 	}
 	bodyStr, ok := respMap["body"].(string)
+	if !ok && v.rawBody != nil {
+		// body is converted to map, e.g. xml body, search raw body instead
+		bodyStr, ok = string(v.rawBody), true
+	}
 	if !ok {
 		log.Error().Interface("resp", respMap).Msg("convert body to string failed")
 		return expr
@@ -1,6 +1,7 @@
 package hrp
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -9,6 +10,16 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func newTestResponseObject(t *testing.T, body string) *responseObject {
+	resp := http.Response{StatusCode: 200}
+	resp.Body = io.NopCloser(strings.NewReader(body))
+	respObj, err := newResponseObject(t, newParser(), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return respObj
+}
+
 func TestSearchRegexp(t *testing.T) {
 	testText := `hrp aims to be a one-stop solution for HTTP(S) testing, covering API testing, load testing and digital experience monitoring (DEM).`
 	testData := []struct {
@@ -33,3 +44,126 @@ func TestSearchRegexp(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateWithSoftAssert(t *testing.T) {
+	validators := []interface{}{
+		Validator{Check: "status_code", Assert: "equals", Expect: 201},
+		Validator{Check: "body.name", Assert: "equals", Expect: "doggie"},
+		Validator{Check: "body.id", Assert: "greater_than", Expect: 10},
+		Validator{Check: "body.id", Assert: "not_exist_assertion", Expect: 10},
+	}
+
+	// return on the first failed validator by default
+	respObj := newTestResponseObject(&testing.T{}, `{"id": 1, "name": "doggie"}`)
+	err := respObj.Validate(validators, map[string]interface{}{})
+	if !assert.Error(t, err) || !assert.Len(t, respObj.validationResults, 1) {
+		t.Fail()
+	}
+
+	// evaluate all validators in soft assert mode
+	respObj = newTestResponseObject(&testing.T{}, `{"id": 1, "name": "doggie"}`)
+	respObj.softAssert = true
+	err = respObj.Validate(validators, map[string]interface{}{})
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	softErr, ok := err.(*softAssertError)
+	if !assert.True(t, ok) || !assert.Len(t, softErr.errs, 3) {
+		t.FailNow()
+	}
+	if !assert.Contains(t, err.Error(), "3 validators failed") ||
+		!assert.Contains(t, err.Error(), "unexpected assertMethod: not_exist_assertion") {
+		t.Fail()
+	}
+	var results []string
+	for _, result := range respObj.validationResults {
+		results = append(results, result.CheckResult)
+	}
+	if !assert.Equal(t, []string{"fail", "pass", "fail"}, results) {
+		t.Fail()
+	}
+}
+
+// fakePlugin implements funplugin.IPlugin with go functions, used to test custom assertions
+type fakePlugin struct {
+	functions map[string]func(args ...interface{}) (interface{}, error)
+}
+
+func (p *fakePlugin) Type() string { return "fake" }
+
+func (p *fakePlugin) Has(funcName string) bool {
+	_, ok := p.functions[funcName]
+	return ok
+}
+
+func (p *fakePlugin) Call(funcName string, args ...interface{}) (interface{}, error) {
+	return p.functions[funcName](args...)
+}
+
+func (p *fakePlugin) Quit() error { return nil }
+
+func TestValidateWithPluginAssertion(t *testing.T) {
+	transitions := map[string]string{"created": "paid", "paid": "shipped"}
+	plugin := &fakePlugin{functions: map[string]func(args ...interface{}) (interface{}, error){
+		// return error message, empty if passed
+		"valid_transition": func(args ...interface{}) (interface{}, error) {
+			from, to := args[0].(string), args[1].(string)
+			if transitions[from] != to {
+				return fmt.Sprintf("invalid order state transition: %s -> %s", from, to), nil
+			}
+			return "", nil
+		},
+		// return bool
+		"is_even": func(args ...interface{}) (interface{}, error) {
+			return args[0].(int64)%2 == 0, nil
+		},
+		// return error
+		"always_error": func(args ...interface{}) (interface{}, error) {
+			return nil, fmt.Errorf("something wrong")
+		},
+	}}
+
+	testData := []struct {
+		validator Validator
+		result    string
+		message   string
+	}{
+		{Validator{Check: "body.state", Assert: "valid_transition", Expect: "paid"}, "pass", ""},
+		{Validator{Check: "body.state", Assert: "valid_transition", Expect: "shipped", Message: "check state"},
+			"fail", "check state, invalid order state transition: created -> shipped"},
+		{Validator{Check: "body.count", Assert: "is_even", Expect: nil}, "pass", ""},
+		{Validator{Check: "body.count", Assert: "always_error", Expect: 1}, "fail", "something wrong"},
+		// builtin assertions take precedence
+		{Validator{Check: "body.count", Assert: "equals", Expect: 2}, "pass", ""},
+	}
+	for _, data := range testData {
+		respObj := newTestResponseObject(&testing.T{}, `{"state": "created", "count": 2}`)
+		respObj.parser.plugin = plugin
+		err := respObj.Validate([]interface{}{data.validator}, map[string]interface{}{})
+		if data.result == "pass" {
+			if !assert.NoError(t, err) {
+				t.Fail()
+			}
+		} else if !assert.Error(t, err) {
+			t.Fail()
+		}
+		if !assert.Len(t, respObj.validationResults, 1) {
+			t.FailNow()
+		}
+		if !assert.Equal(t, data.result, respObj.validationResults[0].CheckResult) {
+			t.Fail()
+		}
+		if data.message != "" && !assert.Equal(t, data.message, respObj.validationResults[0].Message) {
+			t.Fail()
+		}
+	}
+
+	// assertion not found in builtin and plugin
+	respObj := newTestResponseObject(&testing.T{}, `{"state": "created"}`)
+	respObj.parser.plugin = plugin
+	err := respObj.Validate([]interface{}{Validator{Check: "body.state", Assert: "not_found", Expect: 1}},
+		map[string]interface{}{})
+	if !assert.Error(t, err) || !assert.Contains(t, err.Error(), "unexpected assertMethod") {
+		t.Fail()
+	}
+}
@@ -31,6 +31,8 @@ import (
 
 	"github.com/httprunner/httprunner/hrp/internal/builtin"
 	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/openapi"
+	"github.com/httprunner/httprunner/hrp/internal/redact"
 	"github.com/httprunner/httprunner/hrp/internal/sdk"
 )
 
@@ -60,17 +62,30 @@ func NewRunner(t *testing.T) *HRPRunner {
 			},
 			Timeout: 30 * time.Second,
 		},
+		vu:       newVirtualUser(nil, 0),
+		redactor: redact.New(),
 	}
 }
 
 type HRPRunner struct {
-	t             *testing.T
-	failfast      bool
-	requestsLogOn bool
-	pluginLogOn   bool
-	saveTests     bool
-	genHTMLReport bool
-	client        *http.Client
+	t               *testing.T
+	failfast        bool
+	requestsLogOn   bool
+	pluginLogOn     bool
+	saveTests       bool
+	genHTMLReport   bool
+	dryRun          bool
+	exportCurl      bool
+	updateSnapshots bool
+	softAssert      bool
+	exportOnFailure bool
+	client          *http.Client
+	openapiDocs     sync.Map     // cached openapi documents, path => *openapi.Document
+	secrets         sync.Map     // cached decrypted secrets variables, path => map[string]interface{}
+	variablesFiles  sync.Map     // cached variables files, path => map[string]interface{}
+	seed            *int64       // run seed to reproduce random data, generated from current time if not set
+	vu              *virtualUser // virtual user of hrp run
+	redactor        *redact.Redactor
 }
 
 // SetClientTransport configures transport of http client for high concurrency load testing
@@ -137,6 +152,69 @@ func (r *HRPRunner) GenHTMLReport() *HRPRunner {
 	return r
 }
 
+// SetDryRun configures whether to render requests without sending them.
+// In dry run mode, validators and extractors are not executed, think time is skipped,
+// and extracted variables are replaced with labeled placeholders.
+func (r *HRPRunner) SetDryRun(dryRun bool) *HRPRunner {
+	log.Info().Bool("dryRun", dryRun).Msg("[init] SetDryRun")
+	r.dryRun = dryRun
+	return r
+}
+
+// SetExportCurl configures whether to export rendered requests as curl command lines in summary.
+func (r *HRPRunner) SetExportCurl(exportCurl bool) *HRPRunner {
+	log.Info().Bool("exportCurl", exportCurl).Msg("[init] SetExportCurl")
+	r.exportCurl = exportCurl
+	return r
+}
+
+// SetUpdateSnapshots configures whether to rewrite snapshot files with current values
+// instead of comparing with them.
+func (r *HRPRunner) SetUpdateSnapshots(updateSnapshots bool) *HRPRunner {
+	log.Info().Bool("updateSnapshots", updateSnapshots).Msg("[init] SetUpdateSnapshots")
+	r.updateSnapshots = updateSnapshots
+	return r
+}
+
+// SetSoftAssert configures whether to evaluate all validators of each step,
+// the failed step reports all failures in an aggregated error.
+func (r *HRPRunner) SetSoftAssert(softAssert bool) *HRPRunner {
+	log.Info().Bool("softAssert", softAssert).Msg("[init] SetSoftAssert")
+	r.softAssert = softAssert
+	return r
+}
+
+// SetExportOnFailure configures whether to export extracted variables of steps failed in soft assert mode,
+// which could be referenced by subsequent steps when running with continue on failure.
+func (r *HRPRunner) SetExportOnFailure(exportOnFailure bool) *HRPRunner {
+	log.Info().Bool("exportOnFailure", exportOnFailure).Msg("[init] SetExportOnFailure")
+	r.exportOnFailure = exportOnFailure
+	return r
+}
+
+// SetSeed configures the run seed, which makes parameters iteration, random builtin functions
+// and think time randomization reproducible.
+func (r *HRPRunner) SetSeed(seed int64) *HRPRunner {
+	log.Info().Int64("seed", seed).Msg("[init] SetSeed")
+	r.seed = &seed
+	r.vu = newVirtualUser(r.seed, 0)
+	return r
+}
+
+// SetFrozenTime freezes clock of time-based builtin functions at t, e.g. get_timestamp and get_time.
+func (r *HRPRunner) SetFrozenTime(t time.Time) *HRPRunner {
+	log.Info().Time("frozenTime", t).Msg("[init] SetFrozenTime")
+	builtin.FreezeTime(t)
+	return r
+}
+
+// SetTimeOffset shifts clock of time-based builtin functions by offset, e.g. -24h.
+func (r *HRPRunner) SetTimeOffset(offset time.Duration) *HRPRunner {
+	log.Info().Dur("timeOffset", offset).Msg("[init] SetTimeOffset")
+	builtin.SetTimeOffset(offset)
+	return r
+}
+
 // Run starts to execute one or multiple testcases.
 func (r *HRPRunner) Run(testcases ...ITestCase) error {
 	event := sdk.EventTracking{
@@ -149,6 +227,10 @@ func (r *HRPRunner) Run(testcases ...ITestCase) error {
 	defer sdk.SendEvent(event.StartTiming("execution"))
 	// record execution data to summary
 	s := newOutSummary()
+	// each run masks its own secrets, which are also masked in logs during the run
+	r.redactor = redact.New()
+	redact.Attach(r.redactor)
+	defer redact.Detach(r.redactor)
 
 	// load all testcases
 	testCases, err := loadTestCases(testcases...)
@@ -158,30 +240,28 @@ func (r *HRPRunner) Run(testcases ...ITestCase) error {
 
 	for _, testcase := range testCases {
 		cfg := testcase.Config
-		// parse config parameters
-		err := initParameterIterator(cfg, "runner")
+		// parse config parameters with functions of the run virtual user, thus random data are reproducible
+		p := newParser()
+		p.functions = r.vu.functions
+		err := initParameterIterator(p, cfg, "runner")
 		if err != nil {
+			cfg.ParametersSetting.closeIterators()
 			log.Error().Interface("parameters", cfg.Parameters).Err(err).Msg("parse config parameters failed")
 			return err
 		}
-		// 在runner模式下，指定整体策略，cfg.ParametersSetting.Iterators仅包含一个CartesianProduct的迭代器
-		for it := cfg.ParametersSetting.Iterators[0]; it.HasNext(); {
-			// iterate through all parameter iterators and update case variables
-			for _, it := range cfg.ParametersSetting.Iterators {
-				if it.HasNext() {
-					cfg.Variables = mergeVariables(it.Next(), cfg.Variables)
-				}
-			}
-			caseRunnerObj := r.newCaseRunner(testcase)
-			if err = caseRunnerObj.run(); err != nil {
-				log.Error().Err(err).Msg("[Run] run testcase failed")
-				return err
-			}
-			caseSummary := caseRunnerObj.getSummary()
-			s.appendCaseSummary(caseSummary)
+		err = r.runParameterizedTestCase(testcase, s)
+		// close parameters files when testcase finishes
+		cfg.ParametersSetting.closeIterators()
+		if err != nil {
+			return err
 		}
 	}
 	s.Time.Duration = time.Since(s.Time.StartAt).Seconds()
+	// mask secrets in summary and HTML report
+	for _, caseSummary := range s.Details {
+		maskCaseSummary(r.redactor, caseSummary)
+	}
+	r.redactor.Data(s)
 	// save summary
 	if r.saveTests {
 		dir, _ := filepath.Split(summaryPath)
@@ -204,6 +284,35 @@ func (r *HRPRunner) Run(testcases ...ITestCase) error {
 	return nil
 }
 
+// runParameterizedTestCase runs testcase once for each iteration of config parameters
+func (r *HRPRunner) runParameterizedTestCase(testcase *TestCase, s *Summary) error {
+	cfg := testcase.Config
+	// 在runner模式下，指定整体策略，cfg.ParametersSetting.Iterators仅包含一个CartesianProduct的迭代器
+	for it := cfg.ParametersSetting.Iterators[0]; it.HasNext(); {
+		// iterate through all parameter iterators and update case variables
+		for _, it := range cfg.ParametersSetting.Iterators {
+			if it.HasNext() {
+				params, ok, err := it.next(r.vu)
+				if err != nil {
+					log.Error().Err(err).Msg("[Run] get config parameters failed")
+					return err
+				}
+				if ok {
+					cfg.Variables = mergeVariables(params, cfg.Variables)
+				}
+			}
+		}
+		caseRunnerObj := r.newCaseRunner(testcase)
+		if err := caseRunnerObj.run(); err != nil {
+			log.Error().Err(err).Msg("[Run] run testcase failed")
+			return err
+		}
+		caseSummary := caseRunnerObj.getSummary()
+		s.appendCaseSummary(caseSummary)
+	}
+	return nil
+}
+
 func loadTestCases(iTestCases ...ITestCase) ([]*TestCase, error) {
 	testCases := make([]*TestCase, 0)
 
@@ -269,6 +378,7 @@ func (r *HRPRunner) newCaseRunner(testcase *TestCase) *caseRunner {
 		parser:    newParser(),
 		summary:   newSummary(),
 	}
+	caseRunner.setVirtualUser(r.vu)
 	caseRunner.reset()
 	return caseRunner
 }
@@ -283,8 +393,20 @@ type caseRunner struct {
 	// transactions stores transaction timing info.
 	// key is transaction name, value is map of transaction type and time, e.g. start time and end time.
 	transactions map[string]map[transactionType]time.Time
-	startTime    time.Time        // record start time of the testcase
-	summary      *testCaseSummary // record test case summary
+	startTime    time.Time         // record start time of the testcase
+	summary      *testCaseSummary  // record test case summary
+	openapi      *openapi.Document // openapi document for contract validation
+	vu           *virtualUser      // virtual user running the testcase
+	// variables loaded from variables files of caller testcases, which have the lowest priority
+	inheritedVariables map[string]interface{}
+	// parsed variables only defined in variables files, which are inherited by referenced testcases
+	fileVariables map[string]interface{}
+}
+
+// setVirtualUser sets virtual user running the testcase, random data are generated with its random generator.
+func (r *caseRunner) setVirtualUser(vu *virtualUser) {
+	r.vu = vu
+	r.parser.functions = vu.functions
 }
 
 // reset clears runner session variables.
@@ -323,28 +445,12 @@ func (r *caseRunner) run() error {
 				Success: false,
 			}
 		}
-		if stepDataObj.StepType == stepTypeTestCase {
-			// merge test case if the step is test case
-			summary, ok := stepDataObj.Data.(*testCaseSummary)
-			if ok {
-				r.summary.Records = append(r.summary.Records, summary.Records...)
-				r.summary.Stat.Total += summary.Stat.Total
-				r.summary.Stat.Successes += summary.Stat.Successes
-				r.summary.Stat.Failures += summary.Stat.Failures
-			}
-		} else if stepDataObj.StepType == stepTypeRequest {
-			// only record that the test step is the request step
-			r.summary.Records = append(r.summary.Records, stepDataObj)
-			r.summary.Stat.Total += 1
-			if stepDataObj.Success {
-				r.summary.Stat.Successes += 1
-			} else {
-				r.summary.Stat.Failures += 1
-			}
+		if err != nil {
+			stepDataObj.Attachment = err.Error()
 		}
+		r.recordStep(stepDataObj)
 		r.summary.Success = r.summary.Success && stepDataObj.Success
 		if err != nil {
-			stepDataObj.Attachment = err.Error()
 			if r.hrpRunner.failfast {
 				return errors.Wrap(err, "abort running due to failfast setting")
 			}
@@ -355,6 +461,43 @@ func (r *caseRunner) run() error {
 	return nil
 }
 
+// recordStep records step data in testcase summary
+func (r *caseRunner) recordStep(stepDataObj *stepData) {
+	if iterations, ok := stepDataObj.Data.([]*stepData); ok {
+		// record each iteration if the step is run with parameters
+		for _, iteration := range iterations {
+			r.recordStep(iteration)
+		}
+		return
+	}
+	if stepDataObj.StepType == stepTypeTestCase {
+		// merge test case if the step is test case
+		summary, ok := stepDataObj.Data.(*testCaseSummary)
+		if ok {
+			for _, record := range summary.Records {
+				if record.Iteration == 0 {
+					// mark records of referenced testcase with iteration of the step
+					record.Iteration = stepDataObj.Iteration
+					record.Parameters = stepDataObj.Parameters
+				}
+			}
+			r.summary.Records = append(r.summary.Records, summary.Records...)
+			r.summary.Stat.Total += summary.Stat.Total
+			r.summary.Stat.Successes += summary.Stat.Successes
+			r.summary.Stat.Failures += summary.Stat.Failures
+		}
+	} else if stepDataObj.StepType == stepTypeRequest || stepDataObj.StepType == stepTypeSocket {
+		// only record that the test step is the request or socket step
+		r.summary.Records = append(r.summary.Records, stepDataObj)
+		r.summary.Stat.Total += 1
+		if stepDataObj.Success {
+			r.summary.Stat.Successes += 1
+		} else {
+			r.summary.Stat.Failures += 1
+		}
+	}
+}
+
 func (r *caseRunner) runStep(index int, caseConfig *TConfig) (stepResult *stepData, err error) {
 	step := r.TestCase.TestSteps[index]
 
@@ -372,6 +515,126 @@ func (r *caseRunner) runStep(index int, caseConfig *TConfig) (stepResult *stepDa
 
 	log.Info().Str("step", step.Name()).Msg("run step start")
 
+	stepVariables := step.ToStruct().Variables
+	// names of step and session variables, variables loaded from variables files with the same names are overridden
+	overridden := variableNames(stepVariables, r.sessionVariables)
+	// override variables
+	// step variables > session variables (extracted variables from previous steps)
+	stepVariables = mergeVariables(stepVariables, r.sessionVariables)
+	// step variables > testcase config variables
+	stepVariables = mergeVariables(stepVariables, caseConfig.Variables)
+
+	if len(step.ToStruct().Parameters) > 0 {
+		return r.runStepWithParameters(step, stepVariables, overridden, caseConfig)
+	}
+	return r.runStepWithVariables(step, stepVariables, overridden, caseConfig)
+}
+
+// runStepWithParameters runs step once for each parameters row sequentially, parameters override step variables.
+// Variables exported by all iterations are collected into lists in iteration order.
+func (r *caseRunner) runStepWithParameters(step IStep, stepVariables map[string]interface{},
+	overridden map[string]bool, caseConfig *TConfig) (stepResult *stepData, err error) {
+
+	stepResult = &stepData{
+		Name:    step.Name(),
+		Success: true,
+	}
+	// parse step parameters with parsed step variables, e.g. ${parameterize($file)}
+	parsedVariables, err := r.parser.parseVariables(stepVariables)
+	if err != nil {
+		log.Error().Interface("variables", r.hrpRunner.redactor.MaskVariables(stepVariables)).
+			Err(err).Msg("parse step variables failed")
+		return nil, err
+	}
+	parameters, streams, err := r.parser.parseParameters(step.ToStruct().Parameters, parsedVariables)
+	if err != nil {
+		log.Error().Interface("parameters", step.ToStruct().Parameters).Err(err).Msg("parse step parameters failed")
+		return nil, err
+	}
+	iter, err := newProductIterator(parameters, streams, strategySequential)
+	if err != nil {
+		return nil, err
+	}
+	iter = newIterator(iter, strategySequential, 0)
+	defer iter.close()
+
+	var iterations []*stepData
+	exportVars := make(map[string][]interface{})
+	for iter.HasNext() {
+		params, _, readErr := iter.next(r.vu)
+		if readErr != nil {
+			log.Error().Err(readErr).Str("step", step.Name()).Msg("get step parameters failed")
+			stepResult.Success = false
+			if err == nil {
+				err = readErr
+			}
+			break
+		}
+		iteration := len(iterations) + 1
+		log.Info().Str("step", step.Name()).Int("iteration", iteration).
+			Interface("parameters", r.hrpRunner.redactor.MaskVariables(params)).Msg("run step iteration")
+		iterationOverridden := variableNames(params)
+		for name := range overridden {
+			iterationOverridden[name] = true
+		}
+		iterationResult, iterationErr := r.runStepWithVariables(step, mergeVariables(params, stepVariables),
+			iterationOverridden, caseConfig)
+		if iterationResult == nil {
+			iterationResult = &stepData{
+				Name:    step.Name(),
+				Success: false,
+			}
+		}
+		iterationResult.Iteration = iteration
+		iterationResult.Parameters = params
+		if iterationErr != nil {
+			iterationResult.Attachment = iterationErr.Error()
+			if err == nil {
+				err = iterationErr
+			}
+		}
+		iterations = append(iterations, iterationResult)
+
+		stepResult.StepType = iterationResult.StepType
+		stepResult.Success = stepResult.Success && iterationResult.Success
+		stepResult.Elapsed += iterationResult.Elapsed
+		stepResult.ContentSize += iterationResult.ContentSize
+		// keep the same length for all exported lists
+		for _, name := range step.ToStruct().Export {
+			if _, ok := exportVars[name]; !ok {
+				exportVars[name] = []interface{}{}
+			}
+		}
+		for name := range iterationResult.ExportVars {
+			if _, ok := exportVars[name]; !ok {
+				exportVars[name] = make([]interface{}, len(iterations)-1)
+			}
+		}
+		exported := iterationResult.ExportVars
+		if !r.shouldExport(iterationErr) {
+			exported = nil
+		}
+		for name := range exportVars {
+			exportVars[name] = append(exportVars[name], exported[name])
+		}
+		if iterationErr != nil && r.hrpRunner.failfast {
+			break
+		}
+	}
+
+	stepResult.Data = iterations
+	stepResult.ExportVars = make(map[string]interface{}, len(exportVars))
+	for name, values := range exportVars {
+		stepResult.ExportVars[name] = values
+		r.sessionVariables[name] = values
+	}
+	return stepResult, err
+}
+
+// runStepWithVariables runs step once with merged step variables
+func (r *caseRunner) runStepWithVariables(step IStep, stepVariables map[string]interface{},
+	overridden map[string]bool, caseConfig *TConfig) (stepResult *stepData, err error) {
+
 	// copy step and config to avoid data racing
 	copiedStep := &TStep{}
 	if err = copier.Copy(copiedStep, step.ToStruct()); err != nil {
@@ -379,29 +642,29 @@ func (r *caseRunner) runStep(index int, caseConfig *TConfig) (stepResult *stepDa
 		return nil, err
 	}
 
-	stepVariables := copiedStep.Variables
-	// override variables
-	// step variables > session variables (extracted variables from previous steps)
-	stepVariables = mergeVariables(stepVariables, r.sessionVariables)
-	// step variables > testcase config variables
-	stepVariables = mergeVariables(stepVariables, caseConfig.Variables)
-
 	// parse step variables
 	parsedVariables, err := r.parser.parseVariables(stepVariables)
 	if err != nil {
-		log.Error().Interface("variables", caseConfig.Variables).Err(err).Msg("parse step variables failed")
+		log.Error().Interface("variables", r.hrpRunner.redactor.MaskVariables(caseConfig.Variables)).
+			Err(err).Msg("parse step variables failed")
 		return nil, err
 	}
 	copiedStep.Variables = parsedVariables // avoid data racing
 
-	// step type priority order: testcase > request
+	// step type priority order: testcase > socket > request
 	if _, ok := step.(*StepTestCaseWithOptionalArgs); ok {
 		// run referenced testcase
 		log.Info().Str("testcase", copiedStep.Name).Msg("run referenced testcase")
-		stepResult, err = r.runStepTestCase(copiedStep)
+		stepResult, err = r.runStepTestCase(copiedStep, overridden)
 		if err != nil {
 			log.Error().Err(err).Msg("run referenced testcase step failed")
 		}
+	} else if copiedStep.Socket != nil {
+		// run raw socket
+		stepResult, err = r.runStepSocket(copiedStep)
+		if err != nil {
+			log.Error().Err(err).Msg("run socket step failed")
+		}
 	} else {
 		if _, ok := step.(*StepAPIWithOptionalArgs); ok {
 			// run referenced API
@@ -429,18 +692,27 @@ func (r *caseRunner) runStep(index int, caseConfig *TConfig) (stepResult *stepDa
 	}
 
 	// update extracted variables
-	for k, v := range stepResult.ExportVars {
-		r.sessionVariables[k] = v
+	if r.shouldExport(err) {
+		for k, v := range stepResult.ExportVars {
+			r.sessionVariables[k] = v
+		}
 	}
 
 	log.Info().
 		Str("step", step.Name()).
 		Bool("success", stepResult.Success).
-		Interface("exportVars", stepResult.ExportVars).
+		Interface("exportVars", r.hrpRunner.redactor.MaskVariables(stepResult.ExportVars)).
 		Msg("run step end")
 	return stepResult, err
 }
 
+// shouldExport returns whether extracted variables of step are exported, variables of step failed
+// in soft assert mode are exported only if export on failure is specified
+func (r *caseRunner) shouldExport(err error) bool {
+	var softErr *softAssertError
+	return !errors.As(err, &softErr) || r.hrpRunner.exportOnFailure
+}
+
 func (r *caseRunner) runStepThinkTime(step *TStep, ttc *ThinkTimeConfig) (stepResult *stepData, err error) {
 	thinkTime := step.ThinkTime
 	log.Info().
@@ -465,7 +737,7 @@ func (r *caseRunner) runStepThinkTime(step *TStep, ttc *ThinkTimeConfig) (stepRe
 			tt = time.Duration(thinkTime.Time*1000) * time.Millisecond
 			break
 		}
-		res := builtin.GetRandomNumber(int(thinkTime.Time*m["min_percentage"]*1000), int(thinkTime.Time*m["max_percentage"]*1000))
+		res := r.vu.randomNumber(int(thinkTime.Time*m["min_percentage"]*1000), int(thinkTime.Time*m["max_percentage"]*1000))
 		tt = time.Duration(res) * time.Millisecond
 	case thinkTimeMultiply:
 		value, ok := ttc.Setting.(float64) // e.g. 0.5
@@ -483,6 +755,10 @@ func (r *caseRunner) runStepThinkTime(step *TStep, ttc *ThinkTimeConfig) (stepRe
 			tt = limit
 		}
 	}
+	if r.hrpRunner.dryRun {
+		log.Info().Dur("thinkTime", tt).Msg("dry run, skip think time")
+		return stepResult, nil
+	}
 	time.Sleep(tt)
 	return stepResult, nil
 }
@@ -767,6 +1043,13 @@ func (r *caseRunner) runStepRequest(step *TStep) (stepResult *stepData, err erro
 		if len(parsedParams) > 0 {
 			queryParams = make(url.Values)
 			for k, v := range parsedParams {
+				// list value is sent as repeated params, e.g. a=1&a=2
+				if values, ok := v.([]interface{}); ok {
+					for _, value := range values {
+						queryParams.Add(k, fmt.Sprint(value))
+					}
+					continue
+				}
 				queryParams.Add(k, fmt.Sprint(v))
 			}
 		}
@@ -878,11 +1161,30 @@ func (r *caseRunner) runStepRequest(step *TStep) (stepResult *stepData, err erro
 		}
 	}
 
-	// log & print request
-	if err := r.printRequest(req); err != nil {
+	// log & print request, sensitive headers and body fields are masked
+	maskedReq, err := maskRequest(r.hrpRunner.redactor, req)
+	if err != nil {
+		return stepResult, errors.Wrap(err, "read request body failed")
+	}
+	if err := r.printRequest(maskedReq); err != nil {
 		return stepResult, err
 	}
 
+	// export request as curl command line
+	if r.hrpRunner.exportCurl {
+		insecure := !step.Request.Verify && !r.Config.Verify
+		if stepResult.Curl, err = toCurl(maskedReq, insecure); err != nil {
+			return stepResult, errors.Wrap(err, "export curl failed")
+		}
+		stepResult.Curl = r.hrpRunner.redactor.String(stepResult.Curl)
+	}
+
+	// render request only in dry run mode, skip network
+	if r.hrpRunner.dryRun {
+		sessionData.ReqResps.Request = maskSessionMap(r.hrpRunner.redactor, requestMap)
+		return r.dryRunStep(step, stepResult, sessionData), nil
+	}
+
 	// do request action
 	start := time.Now()
 	resp, err := r.hrpRunner.client.Do(req)
@@ -898,11 +1200,6 @@ func (r *caseRunner) runStepRequest(step *TStep) (stepResult *stepData, err erro
 		return stepResult, errors.Wrap(err, "decode response body failed")
 	}
 
-	// log & print response
-	if err := r.printResponse(resp); err != nil {
-		return stepResult, err
-	}
-
 	// new response object
 	respObj, err := newResponseObject(r.hrpRunner.t, r.parser, resp)
 	if err != nil {
@@ -910,6 +1207,11 @@ func (r *caseRunner) runStepRequest(step *TStep) (stepResult *stepData, err erro
 		return
 	}
 
+	// log & print response, response body has been read by response object
+	if err := r.printResponse(maskResponse(r.hrpRunner.redactor, resp, respObj.rawBody)); err != nil {
+		return stepResult, err
+	}
+
 	// add response object to step variables, could be used in teardown hooks
 	step.Variables["hrp_step_response"] = respObj.respObjMeta
 
@@ -921,18 +1223,37 @@ func (r *caseRunner) runStepRequest(step *TStep) (stepResult *stepData, err erro
 		}
 	}
 
-	sessionData.ReqResps.Request = requestMap
-	sessionData.ReqResps.Response = builtin.FormatResponse(respObj.respObjMeta)
+	sessionData.ReqResps.Request = maskSessionMap(r.hrpRunner.redactor, requestMap)
+	sessionData.ReqResps.Response = builtin.FormatResponse(
+		maskSessionMap(r.hrpRunner.redactor, respObj.respObjMeta.(map[string]interface{})))
 
 	// extract variables from response
-	extractors := step.Extract
-	extractMapping := respObj.Extract(extractors)
+	respObj.redactor = r.hrpRunner.redactor
+	extractMapping, err := respObj.Extract(step.Extract, step.Extractors)
 	stepResult.ExportVars = extractMapping
+	if err != nil {
+		stepResult.ContentSize = resp.ContentLength
+		stepResult.Data = sessionData
+		return stepResult, err
+	}
 
 	// override step variables with extracted variables
 	stepVariables := mergeVariables(step.Variables, extractMapping)
 
-	// validate response
+	// validate response, and validate against openapi operation if specified
+	if r.Config.Path != "" {
+		respObj.caseDir = filepath.Dir(r.Config.Path)
+	}
+	respObj.updateSnapshots = r.hrpRunner.updateSnapshots
+	respObj.softAssert = r.hrpRunner.softAssert || step.SoftAssert
+	if r.openapi != nil {
+		respObj.contract = &contract{
+			doc:            r.openapi,
+			method:         req.Method,
+			path:           req.URL.Path,
+			allowUnmatched: r.Config.OpenAPIUnmatched,
+		}
+	}
 	err = respObj.Validate(step.Validators, stepVariables)
 	sessionData.Validators = respObj.validationResults
 	if err == nil {
@@ -946,7 +1267,8 @@ func (r *caseRunner) runStepRequest(step *TStep) (stepResult *stepData, err erro
 }
 
 func (r *caseRunner) printRequest(req *http.Request) error {
-	if !r.hrpRunner.requestsLogOn {
+	// always print rendered request in dry run mode
+	if !r.hrpRunner.requestsLogOn && !r.hrpRunner.dryRun {
 		return nil
 	}
 	reqContentType := req.Header.Get("Content-Type")
@@ -960,10 +1282,70 @@ func (r *caseRunner) printRequest(req *http.Request) error {
 	if req.Body != nil && !printBody {
 		reqContent += fmt.Sprintf("(request body omitted for Content-Type: %v)", reqContentType)
 	}
-	fmt.Println(reqContent)
+	fmt.Println(r.hrpRunner.redactor.String(reqContent))
 	return nil
 }
 
+const (
+	checkResultNotExecuted  = "not executed"
+	dryRunPlaceholderFormat = "<dry-run:%s>"
+)
+
+// notExecutedResult converts validator to not executed result, nested validators are converted recursively
+func (r *caseRunner) notExecutedResult(validator Validator, variables map[string]interface{}) *validationResult {
+	result := &validationResult{
+		Validator: Validator{
+			Check:   validator.Check,
+			Assert:  validator.Assert,
+			Message: validator.Message,
+		},
+		CheckResult: checkResultNotExecuted,
+	}
+	if isCombinator(validator) {
+		var nested []Validator
+		result.Assert, nested = combinatorValidators(validator)
+		for _, n := range nested {
+			result.Children = append(result.Children, r.notExecutedResult(n, variables))
+		}
+		return result
+	}
+	// parse expected value if possible, keep raw expect otherwise
+	expectValue, err := r.parser.parseData(validator.Expect, variables)
+	if err != nil {
+		expectValue = validator.Expect
+	}
+	result.Expect = expectValue
+	return result
+}
+
+// dryRunStep marks step validators as not executed
+// and exports labeled placeholders for variables to be extracted.
+func (r *caseRunner) dryRunStep(step *TStep, stepResult *stepData, sessionData *SessionData) *stepData {
+	for _, iValidator := range step.Validators {
+		validator, ok := iValidator.(Validator)
+		if !ok {
+			continue
+		}
+		sessionData.Validators = append(sessionData.Validators, r.notExecutedResult(validator, step.Variables))
+	}
+
+	if len(step.Extract) > 0 || len(step.Extractors) > 0 {
+		stepResult.ExportVars = make(map[string]interface{})
+		for varName := range step.Extract {
+			stepResult.ExportVars[varName] = fmt.Sprintf(dryRunPlaceholderFormat, varName)
+		}
+		for varName := range step.Extractors {
+			stepResult.ExportVars[varName] = fmt.Sprintf(dryRunPlaceholderFormat, varName)
+		}
+	}
+
+	log.Info().Str("step", step.Name).Msg("dry run, skip sending request")
+	sessionData.Success = true
+	stepResult.Success = true
+	stepResult.Data = sessionData
+	return stepResult
+}
+
 func (r *caseRunner) printResponse(resp *http.Response) error {
 	if !r.hrpRunner.requestsLogOn {
 		return nil
@@ -979,7 +1361,7 @@ func (r *caseRunner) printResponse(resp *http.Response) error {
 	if !printBody {
 		respContent += fmt.Sprintf("(response body omitted for Content-Type: %v)", respContentType)
 	}
-	fmt.Println(respContent)
+	fmt.Println(r.hrpRunner.redactor.String(respContent))
 	fmt.Println("--------------------------------------------------")
 	return nil
 }
@@ -1022,7 +1404,7 @@ func decodeResponseBody(resp *http.Response) (err error) {
 	return nil
 }
 
-func (r *caseRunner) runStepTestCase(step *TStep) (stepResult *stepData, err error) {
+func (r *caseRunner) runStepTestCase(step *TStep, overridden map[string]bool) (stepResult *stepData, err error) {
 	stepResult = &stepData{
 		Name:     step.Name,
 		StepType: stepTypeTestCase,
@@ -1036,11 +1418,15 @@ func (r *caseRunner) runStepTestCase(step *TStep) (stepResult *stepData, err err
 		log.Error().Err(err).Msg("copy testcase failed")
 		return stepResult, err
 	}
+	// variables of caller loaded from variables files do not override variables of referenced testcase
+	inheritedVariables := r.splitInheritedVariables(step, overridden)
 	// override testcase config
 	extendWithTestCase(step, copiedTestCase)
 
 	start := time.Now()
 	caseRunnerObj := r.hrpRunner.newCaseRunner(copiedTestCase)
+	caseRunnerObj.setVirtualUser(r.vu)
+	caseRunnerObj.inheritedVariables = inheritedVariables
 	err = caseRunnerObj.run()
 	stepResult.Elapsed = time.Since(start).Milliseconds()
 	if err != nil {
@@ -1054,13 +1440,42 @@ func (r *caseRunner) runStepTestCase(step *TStep) (stepResult *stepData, err err
 }
 
 func (r *caseRunner) parseConfig(cfg *TConfig) error {
+	// configure secrets to be masked
+	if cfg.Redact != nil {
+		r.hrpRunner.redactor.AddHeaders(cfg.Redact.Headers...)
+		r.hrpRunner.redactor.AddVariables(cfg.Redact.Variables...)
+		if err := r.hrpRunner.redactor.AddJSONPaths(cfg.Redact.JSONPaths...); err != nil {
+			return errors.Wrap(err, "parse redact json paths failed")
+		}
+	}
+
+	// merge variables of encrypted secrets file, config variables take precedence
+	if cfg.SecretsFile != "" {
+		secretVariables, err := r.hrpRunner.loadSecrets(cfg)
+		if err != nil {
+			return errors.Wrap(err, "load secrets file failed")
+		}
+		cfg.Variables = mergeVariables(cfg.Variables, secretVariables)
+	}
+
+	// merge variables of variables files, config variables take precedence
+	fileVariableNames, err := r.mergeFileVariables(cfg)
+	if err != nil {
+		return errors.Wrap(err, "load variables files failed")
+	}
+
 	// parse config variables
 	parsedVariables, err := r.parser.parseVariables(cfg.Variables)
 	if err != nil {
-		log.Error().Interface("variables", cfg.Variables).Err(err).Msg("parse config variables failed")
+		log.Error().Interface("variables", r.hrpRunner.redactor.MaskVariables(cfg.Variables)).
+			Err(err).Msg("parse config variables failed")
 		return err
 	}
 	cfg.Variables = parsedVariables
+	r.fileVariables = make(map[string]interface{}, len(fileVariableNames))
+	for name := range fileVariableNames {
+		r.fileVariables[name] = parsedVariables[name]
+	}
 
 	// parse config name
 	parsedName, err := r.parser.parseString(cfg.Name, cfg.Variables)
@@ -1079,6 +1494,13 @@ func (r *caseRunner) parseConfig(cfg *TConfig) error {
 	// ensure correction of think time config
 	cfg.ThinkTime.checkThinkTime()
 
+	// load openapi document for contract validation
+	if cfg.OpenAPI != "" {
+		if r.openapi, err = r.hrpRunner.loadOpenAPI(cfg); err != nil {
+			return errors.Wrap(err, "load openapi document failed")
+		}
+	}
+
 	return nil
 }
 
@@ -1,15 +1,23 @@
 package hrp
 
 import (
+	"fmt"
+	"io"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/redact"
 	"github.com/httprunner/httprunner/hrp/internal/scaffold"
 )
 
@@ -241,6 +249,65 @@ func TestGenHTMLReport(t *testing.T) {
 	}
 }
 
+func TestRunCaseWithDryRun(t *testing.T) {
+	testcase := &TestCase{
+		Config: NewConfig("dry run").
+			SetBaseURL("http://127.0.0.1:1"). // unreachable, request should never be sent
+			WithVariables(map[string]interface{}{"user": "debugtalk"}),
+		TestSteps: []IStep{
+			NewStep("login").
+				POST("/login").
+				WithBody(map[string]interface{}{"user": "$user"}).
+				Extract().
+				WithJmesPath("body.token", "token").
+				Validate().
+				AssertEqual("status_code", 200, "check status code"),
+			NewStep("think").SetThinkTime(10),
+			NewStep("get profile").
+				GET("/users/$user").
+				WithHeaders(map[string]string{"Authorization": "Bearer $token", "X-Session": "$token"}).
+				Validate().
+				AssertEqual("body.user", "$user", "check user"),
+		},
+	}
+	caseRunner := NewRunner(t).SetDryRun(true).SetExportCurl(true).newCaseRunner(testcase)
+	startTime := time.Now()
+	if err := caseRunner.run(); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	// think time is skipped in dry run
+	if !assert.Less(t, time.Since(startTime), 5*time.Second) {
+		t.Fail()
+	}
+	records := caseRunner.getSummary().Records
+	if !assert.Len(t, records, 2) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, "<dry-run:token>", records[0].ExportVars["token"]) {
+		t.Fail()
+	}
+	session := records[1].Data.(*SessionData)
+	request := session.ReqResps.Request.(map[string]interface{})
+	headers := request["headers"].(map[string]string)
+	if !assert.Equal(t, "<dry-run:token>", headers["X-Session"]) {
+		t.Fail()
+	}
+	// sensitive headers are masked in dry run as well
+	if !assert.Equal(t, redact.Mask, headers["Authorization"]) {
+		t.Fail()
+	}
+	if !assert.Contains(t, records[1].Curl, "-H 'X-Session: <dry-run:token>'") ||
+		!assert.Contains(t, records[1].Curl, "-H 'Authorization: ******'") {
+		t.Fail()
+	}
+	if !assert.Equal(t, checkResultNotExecuted, session.Validators[0].CheckResult) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "debugtalk", session.Validators[0].Expect) {
+		t.Fail()
+	}
+}
+
 func TestRunCaseWithPluginJSON(t *testing.T) {
 	buildHashicorpGoPlugin()
 	defer removeHashicorpGoPlugin()
@@ -328,3 +395,266 @@ func TestLoadTestCases(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestRunCaseWithSoftAssert(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"token": "abc", "auth": %q}`, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	testcase := &TestCase{
+		Config: NewConfig("soft assert").SetBaseURL(server.URL),
+		TestSteps: []IStep{
+			NewStep("login").
+				POST("/login").
+				Extract().
+				WithJmesPath("body.token", "token").
+				Validate().
+				SoftAssert().
+				AssertEqual("status_code", 201, "check status code").
+				AssertEqual("body.token", "abc", "check token").
+				AssertEqual("body.auth", "none", "check auth"),
+			NewStep("get profile").
+				GET("/profile").
+				WithHeaders(map[string]string{"Authorization": "Bearer $token"}).
+				Validate().
+				AssertEqual("body.auth", "Bearer abc", "check auth"),
+		},
+	}
+
+	// all validators are evaluated, and extracted variables of failed step are exported
+	caseRunner := NewRunner(nil).SetFailfast(false).SetExportOnFailure(true).newCaseRunner(testcase)
+	if err := caseRunner.run(); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	records := caseRunner.getSummary().Records
+	if !assert.Len(t, records, 2) {
+		t.Fatal()
+	}
+	if !assert.Len(t, records[0].Data.(*SessionData).Validators, 3) {
+		t.Fail()
+	}
+	if !assert.Contains(t, records[0].Attachment, "2 validators failed") {
+		t.Fail()
+	}
+	if !assert.True(t, records[1].Success) {
+		t.Fail()
+	}
+
+	// extracted variables of step failed in soft assert mode are not exported by default
+	caseRunner = NewRunner(nil).SetFailfast(false).newCaseRunner(testcase)
+	if err := caseRunner.run(); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	records = caseRunner.getSummary().Records
+	if !assert.False(t, records[1].Success) {
+		t.Fail()
+	}
+
+	// extracted variables of failed step without soft assert are exported as before
+	testcase.TestSteps[0].(*StepRequestValidation).step.SoftAssert = false
+	caseRunner = NewRunner(nil).SetFailfast(false).newCaseRunner(testcase)
+	if err := caseRunner.run(); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	records = caseRunner.getSummary().Records
+	if !assert.True(t, records[1].Success) {
+		t.Fail()
+	}
+}
+
+func TestRunCaseWithRedact(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=s-98765; Path=/")
+		fmt.Fprint(w, `{"data": {"token": "tk-12345"}, "user": "debugtalk"}`)
+	}))
+	defer server.Close()
+
+	testcase := &TestCase{
+		Config: NewConfig("redact").
+			SetBaseURL(server.URL).
+			WithVariables(map[string]interface{}{"password": "p@ssw0rd", "user": "debugtalk"}).
+			SetRedact(&RedactConfig{
+				Headers:   []string{"X-Sign"},
+				Variables: []string{"password"},
+				JSONPaths: []string{"$.data.token", "$.password"},
+			}),
+		TestSteps: []IStep{
+			NewStep("login").
+				POST("/login").
+				WithHeaders(map[string]string{"X-Sign": "sign-abcdef"}).
+				WithBody(map[string]interface{}{"user": "$user", "password": "$password"}).
+				Extract().
+				WithJmesPath("body.data.token", "token"),
+			NewStep("get profile").
+				GET("/profile").
+				WithHeaders(map[string]string{"Authorization": "Bearer $token"}),
+		},
+	}
+
+	caseRunner := NewRunner(nil).SetRequestsLogOn().newCaseRunner(testcase)
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = writer
+	err = caseRunner.run()
+	os.Stdout = stdout
+	writer.Close()
+	output, _ := io.ReadAll(reader)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	// extracted token is still used in the following request
+	if !assert.Equal(t, "tk-12345", caseRunner.sessionVariables["token"]) {
+		t.Fail()
+	}
+
+	summary := caseRunner.getSummary()
+	maskCaseSummary(caseRunner.hrpRunner.redactor, summary)
+	content, err := json.Marshal(summary)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	// values are masked at field level in printed requests and responses, and in summary
+	for _, data := range []string{string(output), string(content)} {
+		for _, secret := range []string{"p@ssw0rd", "sign-abcdef", "tk-12345", "s-98765"} {
+			if !assert.NotContains(t, data, secret) {
+				t.Fail()
+			}
+		}
+		if !assert.Contains(t, data, "debugtalk") {
+			t.Fail()
+		}
+	}
+	// values of fields are not registered as secrets, thus replacer does not grow with traffic
+	if !assert.Equal(t, "tk-12345", caseRunner.hrpRunner.redactor.String("tk-12345")) {
+		t.Fail()
+	}
+}
+
+func TestRunRequestWithRepeatedParams(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+	}))
+	defer server.Close()
+
+	testcase := &TestCase{
+		Config: NewConfig("repeated params").SetBaseURL(server.URL),
+		TestSteps: []IStep{
+			NewStep("get").
+				WithVariables(map[string]interface{}{"b": 2}).
+				GET("/get").
+				WithParams(map[string]interface{}{"a": []interface{}{1, "$b"}, "c": 3}),
+		},
+	}
+	if err := NewRunner(t).Run(testcase); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, url.Values{"a": {"1", "2"}, "c": {"3"}}, query) {
+		t.Fail()
+	}
+}
+
+func TestRunStepWithParameters(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		names = append(names, r.URL.Query().Get("name"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": "id-%s"}`, r.URL.Query().Get("name"))
+	}))
+	defer server.Close()
+
+	product := &TestCase{
+		Config: NewConfig("create product").SetBaseURL(server.URL),
+		TestSteps: []IStep{
+			NewStep("create product").
+				POST("/products").
+				WithParams(map[string]interface{}{"name": "$name"}).
+				Extract().
+				WithJmesPath("body.id", "product_id"),
+		},
+	}
+	testcase := &TestCase{
+		Config: NewConfig("create products").SetBaseURL(server.URL),
+		TestSteps: []IStep{
+			NewStep("create products").
+				WithVariables(map[string]interface{}{"name": "default"}).
+				WithParameters(map[string]interface{}{"name": []interface{}{"p1", "p2", "p3"}}).
+				CallRefCase(product).
+				Export("product_id"),
+			NewStep("get products").
+				GET("/products").
+				WithParams(map[string]interface{}{"name": "${json_dumps($product_id)}"}),
+		},
+	}
+	testcase.Config.Export = []string{"product_id"}
+
+	caseRunner := NewRunner(t).newCaseRunner(testcase)
+	if err := caseRunner.run(); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	summary := caseRunner.getSummary()
+
+	// exports of all iterations are collected into list
+	productIDs := []interface{}{"id-p1", "id-p2", "id-p3"}
+	if !assert.Equal(t, productIDs, summary.InOut.ExportVars["product_id"]) {
+		t.Fail()
+	}
+	// each iteration is recorded in summary
+	if !assert.Len(t, summary.Records, 4) || !assert.Equal(t, 4, summary.Stat.Total) {
+		t.FailNow()
+	}
+	for i, name := range []string{"p1", "p2", "p3"} {
+		if !assert.Equal(t, i+1, summary.Records[i].Iteration) {
+			t.Fail()
+		}
+		if !assert.Equal(t, map[string]interface{}{"name": name}, summary.Records[i].Parameters) {
+			t.Fail()
+		}
+	}
+	if !assert.Zero(t, summary.Records[3].Iteration) {
+		t.Fail()
+	}
+	// parameters override step variables, list could be referenced by later steps
+	if !assert.Equal(t, []string{"p1", "p2", "p3", `["id-p1","id-p2","id-p3"]`}, names) {
+		t.Fail()
+	}
+}
+
+func TestRunCloseConfigParametersStream(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		names = append(names, r.URL.Query().Get("name"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "users.csv")
+	if err := os.WriteFile(path, []byte("name\nu1\nu2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	testcase := &TestCase{
+		Config: NewConfig("stream parameters").
+			SetBaseURL(server.URL).
+			WithParameters(map[string]interface{}{
+				"name": fmt.Sprintf("${parameterize(%s, stream=true)}", path),
+			}),
+		TestSteps: []IStep{
+			NewStep("get").GET("/get").WithParams(map[string]interface{}{"name": "$name"}),
+		},
+	}
+	if err := NewRunner(t).Run(testcase); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, []string{"u1", "u2"}, names) {
+		t.Fail()
+	}
+	// parameters file is closed when testcase finishes
+	if _, _, err := testcase.Config.ParametersSetting.Iterators[0].next(nil); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,96 @@
+package hrp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+	"github.com/httprunner/httprunner/hrp/internal/jsonschema"
+)
+
+const assertJSONSchema = "json_schema"
+
+// validateJSONSchema validates check value against json schema, expect is either inline schema
+// or schema file path relative to testcase file. Each violation is recorded as a failed validation
+// result with its JSON pointer location in check value.
+func (v *responseObject) validateJSONSchema(validator Validator, checkValue interface{},
+	variablesMapping map[string]interface{}) error {
+
+	var schema, expectValue interface{}
+	switch expect := validator.Expect.(type) {
+	case string:
+		// schema file path, may reference variables
+		parsed, err := v.parser.parseData(expect, variablesMapping)
+		if err != nil {
+			return err
+		}
+		expectValue = parsed
+		schema, err = v.loadSchema(fmt.Sprint(parsed))
+		if err != nil {
+			return err
+		}
+	default:
+		// inline schema is not parsed, otherwise $ref would be regarded as variable reference
+		expectValue = expect
+		schema = expect
+	}
+
+	violations := jsonschema.Validate(schema, checkValue)
+	log.Info().
+		Str("checkExpr", validator.Check).
+		Str("assertMethod", assertJSONSchema).
+		Interface("checkValue", checkValue).
+		Int("violations", len(violations)).
+		Msgf("validate %s", validator.Check)
+	if len(violations) == 0 {
+		v.validationResults = append(v.validationResults, &validationResult{
+			Validator: Validator{
+				Check:   validator.Check,
+				Expect:  expectValue,
+				Assert:  assertJSONSchema,
+				Message: validator.Message,
+			},
+			CheckValue:  checkValue,
+			CheckResult: "pass",
+		})
+		return nil
+	}
+
+	var messages []string
+	for _, violation := range violations {
+		message := violation.Error()
+		if validator.Message != "" {
+			message = fmt.Sprintf("%s, %s", validator.Message, message)
+		}
+		v.validationResults = append(v.validationResults, &validationResult{
+			Validator: Validator{
+				Check:   validator.Check,
+				Expect:  expectValue,
+				Assert:  assertJSONSchema,
+				Message: message,
+			},
+			CheckValue:  violation.Value,
+			CheckResult: "fail",
+		})
+		messages = append(messages, violation.Error())
+	}
+	v.t.Fail()
+	return errors.Errorf("json schema validation failed, checkExpr: %v, %d violations: %s",
+		validator.Check, len(violations), strings.Join(messages, "; "))
+}
+
+// loadSchema loads json schema from json/yaml file, relative path is located in testcase directory
+func (v *responseObject) loadSchema(path string) (interface{}, error) {
+	if !filepath.IsAbs(path) && v.caseDir != "" {
+		path = filepath.Join(v.caseDir, path)
+	}
+	var schema interface{}
+	if err := builtin.LoadFile(path, &schema); err != nil {
+		return nil, errors.Wrap(err, "load json schema failed")
+	}
+	return schema, nil
+}
@@ -0,0 +1,96 @@
+package hrp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var petSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"id", "name"},
+	"properties": map[string]interface{}{
+		"id":   map[string]interface{}{"type": "integer"},
+		"name": map[string]interface{}{"type": "string"},
+		"tags": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"$ref": "#/definitions/tag"},
+		},
+	},
+	"definitions": map[string]interface{}{
+		"tag": map[string]interface{}{"type": "string", "minLength": 1},
+	},
+}
+
+func TestValidateJSONSchemaInline(t *testing.T) {
+	respObj := newTestResponseObject(t, `{"id": 1, "name": "doggie", "tags": ["a"]}`)
+	validators := []interface{}{
+		Validator{Check: "body", Assert: "json_schema", Expect: petSchema},
+	}
+	if !assert.NoError(t, respObj.Validate(validators, map[string]interface{}{})) {
+		t.Fail()
+	}
+	if !assert.Len(t, respObj.validationResults, 1) || !assert.Equal(t, "pass", respObj.validationResults[0].CheckResult) {
+		t.Fail()
+	}
+
+	// every violation is reported with its location
+	respObj = newTestResponseObject(&testing.T{}, `{"id": "1", "tags": ["a", ""]}`)
+	err := respObj.Validate(validators, map[string]interface{}{})
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	for _, location := range []string{"/id", "/name", "/tags/1"} {
+		if !assert.Contains(t, err.Error(), location+":") {
+			t.Fail()
+		}
+	}
+	if !assert.Len(t, respObj.validationResults, 3) {
+		t.FailNow()
+	}
+	for _, result := range respObj.validationResults {
+		if !assert.Equal(t, "fail", result.CheckResult) {
+			t.Fail()
+		}
+	}
+	if !assert.Contains(t, respObj.validationResults[1].Message, "/id:") ||
+		!assert.Equal(t, "1", respObj.validationResults[1].CheckValue) {
+		t.Fail()
+	}
+}
+
+func TestValidateJSONSchemaFile(t *testing.T) {
+	caseDir := t.TempDir()
+	schema := `{"type": "array", "items": {"type": "object", "required": ["id"]}, "minItems": 1}`
+	if err := os.WriteFile(filepath.Join(caseDir, "pets.schema.json"), []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	respObj := newTestResponseObject(t, `{"data": [{"id": 1}, {"id": 2}]}`)
+	respObj.caseDir = caseDir
+	validators := []interface{}{
+		Validator{Check: "body.data", Assert: "json_schema", Expect: "$schema_file"},
+	}
+	variables := map[string]interface{}{"schema_file": "pets.schema.json"}
+	if !assert.NoError(t, respObj.Validate(validators, variables)) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "pets.schema.json", respObj.validationResults[0].Expect) {
+		t.Fail()
+	}
+
+	respObj = newTestResponseObject(&testing.T{}, `{"data": [{"name": "doggie"}]}`)
+	respObj.caseDir = caseDir
+	err := respObj.Validate(validators, variables)
+	if !assert.Error(t, err) || !assert.Contains(t, err.Error(), "/0/id:") {
+		t.Fail()
+	}
+
+	// schema file not found
+	respObj.caseDir = filepath.Join(caseDir, "not_exist")
+	if !assert.Error(t, respObj.Validate(validators, variables)) {
+		t.Fail()
+	}
+}
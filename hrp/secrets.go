@@ -0,0 +1,68 @@
+package hrp
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/secrets"
+)
+
+// loadSecrets decrypts secrets file specified in config with key loaded from environment variables,
+// decrypted variables are cached in runner and registered to be masked in logs, summary and report.
+// Relative path is located in project root directory like openapi document.
+func (r *HRPRunner) loadSecrets(cfg *TConfig) (map[string]interface{}, error) {
+	path := cfg.SecretsFile
+	if !filepath.IsAbs(path) && cfg.Path != "" {
+		projectRootDir, err := getProjectRootDirPath(cfg.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get project root dir")
+		}
+		path = filepath.Join(projectRootDir, path)
+	}
+	cached, ok := r.secrets.Load(path)
+	if !ok {
+		key, err := secrets.LoadKey("")
+		if err != nil {
+			return nil, err
+		}
+		variables, err := secrets.LoadFile(path, key)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Str("path", path).Int("count", len(variables)).Msg("load secrets file")
+		cached, _ = r.secrets.LoadOrStore(path, variables)
+	}
+	variables := cached.(map[string]interface{})
+	// register secrets for each run, registered values are skipped
+	if err := r.redactor.AddSecretData(variables); err != nil {
+		return nil, err
+	}
+
+	// secrets are used literally instead of being parsed as variables or functions
+	return escapeDollar(variables).(map[string]interface{}), nil
+}
+
+// escapeDollar escapes $ with $$ in strings of value recursively
+func escapeDollar(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return strings.ReplaceAll(v, "$", "$$")
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = escapeDollar(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, item := range v {
+			l[i] = escapeDollar(item)
+		}
+		return l
+	default:
+		return v
+	}
+}
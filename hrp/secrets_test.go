@@ -0,0 +1,60 @@
+package hrp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/httprunner/httprunner/hrp/internal/secrets"
+)
+
+func TestLoadSecretsFile(t *testing.T) {
+	key := "my-secrets-key"
+	os.Setenv(secrets.KeyEnv, key)
+	defer os.Unsetenv(secrets.KeyEnv)
+
+	encrypted, err := secrets.Encrypt([]byte("password: p@ss$word\nuser: admin\ntoken: secret-token\n"), []byte(key))
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	secretsPath := filepath.Join(t.TempDir(), "secrets.enc")
+	if err := os.WriteFile(secretsPath, encrypted, 0o600); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	testcase := &TestCase{
+		Config: NewConfig("secrets").
+			WithVariables(map[string]interface{}{
+				"user":  "debugtalk",
+				"login": "$user:$password",
+			}).
+			SetSecretsFile(secretsPath),
+	}
+	caseRunner := NewRunner(t).newCaseRunner(testcase)
+	if err := caseRunner.parseConfig(testcase.Config); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	// config variables take precedence, secrets are used literally
+	expected := map[string]interface{}{
+		"user":     "debugtalk",
+		"password": "p@ss$word",
+		"token":    "secret-token",
+		"login":    "debugtalk:p@ss$word",
+	}
+	if !assert.Equal(t, expected, testcase.Config.Variables) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "debugtalk:******, ******", caseRunner.hrpRunner.redactor.String("debugtalk:p@ss$word, secret-token")) {
+		t.Fail()
+	}
+
+	// wrong key
+	os.Setenv(secrets.KeyEnv, "wrong-key")
+	testcase.Config.SecretsFile = secretsPath
+	if err := NewRunner(t).newCaseRunner(testcase).parseConfig(testcase.Config); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,130 @@
+package hrp
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/dom"
+)
+
+// prefixes of extract/check expressions to query XML/HTML response body
+const (
+	xpathExprPrefix = "xpath:"
+	cssExprPrefix   = "css:"
+)
+
+// isSelectorExpr checks if expression is xpath, css selector or jsonpath, which should not be regarded as
+// jmespath or variable reference, e.g. css:a[href$='.pdf'], jsonpath:$.items[0]
+func isSelectorExpr(expr string) bool {
+	return strings.HasPrefix(expr, xpathExprPrefix) || strings.HasPrefix(expr, cssExprPrefix) ||
+		strings.HasPrefix(expr, jsonPathExprPrefix)
+}
+
+func isXMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// parseXMLBody parses XML response body to map, thus it could be searched with jmespath,
+// the parsed document is returned to be cached for xpath and css selector queries.
+func parseXMLBody(contentType string, body []byte) (interface{}, *dom.Document) {
+	if !isXMLContentType(contentType) || isHTMLContentType(contentType) {
+		return nil, nil
+	}
+	doc, err := dom.ParseXML(body)
+	if err != nil {
+		log.Warn().Err(err).Str("contentType", contentType).Msg("parse xml body failed, use raw body")
+		return nil, nil
+	}
+	return dom.ToMap(doc), doc
+}
+
+// getDocument parses response body to document lazily, body is parsed as HTML if content type is HTML,
+// otherwise it is parsed as XML first and falls back to HTML.
+func (v *responseObject) getDocument() (*dom.Document, error) {
+	if v.document != nil {
+		return v.document, nil
+	}
+	if v.rawBody == nil {
+		return nil, errors.New("response body is empty")
+	}
+	var doc *dom.Document
+	var err error
+	if !isHTMLContentType(v.contentType) {
+		doc, err = dom.ParseXML(v.rawBody)
+	}
+	if doc == nil {
+		doc, err = dom.ParseHTML(v.rawBody)
+		if err != nil {
+			return nil, err
+		}
+	}
+	v.document = doc
+	return doc, nil
+}
+
+// searchXPath queries response body with xpath, e.g. xpath://book[1]/title,
+// nil is returned if no node matched, and list is returned if multiple nodes matched.
+func (v *responseObject) searchXPath(expr string) interface{} {
+	value, err := v.queryXPath(expr)
+	if err != nil {
+		log.Error().Str("expr", expr).Err(err).Msg("search xpath failed")
+		return expr
+	}
+	return value
+}
+
+// searchCSS queries response body with css selector, e.g. css:ul > li a::attr(href),
+// nil is returned if no element matched, and list is returned if multiple elements matched.
+func (v *responseObject) searchCSS(expr string) interface{} {
+	value, err := v.queryCSS(expr)
+	if err != nil {
+		log.Error().Str("expr", expr).Err(err).Msg("search css selector failed")
+		return expr
+	}
+	return value
+}
+
+func (v *responseObject) queryXPath(expr string) (interface{}, error) {
+	doc, err := v.getDocument()
+	if err != nil {
+		return nil, errors.Wrap(err, "parse document failed")
+	}
+	return dom.XPathValue(doc, strings.TrimSpace(strings.TrimPrefix(expr, xpathExprPrefix)))
+}
+
+func (v *responseObject) queryCSS(expr string) (interface{}, error) {
+	doc, err := v.getDocument()
+	if err != nil {
+		return nil, errors.Wrap(err, "parse document failed")
+	}
+	values, err := dom.CSS(doc, strings.TrimSpace(strings.TrimPrefix(expr, cssExprPrefix)))
+	if err != nil {
+		return nil, err
+	}
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		return values[0], nil
+	}
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result, nil
+}
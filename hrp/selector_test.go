@@ -0,0 +1,117 @@
+package hrp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestResponseObjectWithContentType(t *testing.T, contentType, body string) *responseObject {
+	resp := http.Response{StatusCode: 200, Header: http.Header{}}
+	resp.Header.Set("Content-Type", contentType)
+	resp.Body = io.NopCloser(strings.NewReader(body))
+	respObj, err := newResponseObject(t, newParser(), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return respObj
+}
+
+const testXMLBody = `<?xml version="1.0" encoding="UTF-8"?>
+<order id="1001">
+    <item sku="a-1"><name>pen</name><price>1.5</price></item>
+    <item sku="b-2"><name>book</name><price>20</price></item>
+    <status>paid</status>
+</order>`
+
+func TestExtractXMLBody(t *testing.T) {
+	respObj := newTestResponseObjectWithContentType(t, "application/xml; charset=utf-8", testXMLBody)
+	testData := []struct {
+		expr     string
+		expected interface{}
+	}{
+		// xml body is converted to map, thus jmespath keeps working
+		{"body.order.status", "paid"},
+		{`body.order."@id"`, "1001"},
+		{"body.order.item[1].name", "book"},
+		{"xpath:/order/status", "paid"},
+		{"xpath: //item[price>10]/@sku", "b-2"},
+		{"xpath://item/name", []interface{}{"pen", "book"}},
+		{"xpath:count(//item)", 2},
+		{"xpath://item[@sku='c-3']", nil},
+		{"css:item[sku$='-2'] name", "book"},
+		{"css:order::attr(id)", "1001"},
+		{`<status>(.*)</status>`, "paid"},
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, respObj.extractField(data.expr), data.expr) {
+			t.Fail()
+		}
+	}
+}
+
+func TestExtractHTMLBody(t *testing.T) {
+	respObj := newTestResponseObjectWithContentType(t, "text/html; charset=utf-8", `<!DOCTYPE html>
+<html><head><title>Login</title></head><body>
+<form action="/login"><input type="hidden" name="csrf_token" value="abc123"><br>
+<a href="/help.pdf">help</a><a href="/about">about</a></form></body></html>`)
+	testData := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"xpath://title", "Login"},
+		{"xpath://input[@name='csrf_token']/@value", "abc123"},
+		{"css:input[name=csrf_token]::attr(value)", "abc123"},
+		{"css:form a::attr(href)", []interface{}{"/help.pdf", "/about"}},
+		{"css:.missing", nil},
+		{"css:a[", "css:a["}, // invalid selector, return the expression
+	}
+	for _, data := range testData {
+		if !assert.Equal(t, data.expected, respObj.extractField(data.expr), data.expr) {
+			t.Fail()
+		}
+	}
+	// html body is kept as raw string
+	if !assert.Contains(t, respObj.extractField("body"), "csrf_token") {
+		t.Fail()
+	}
+}
+
+func TestValidateWithSelector(t *testing.T) {
+	respObj := newTestResponseObjectWithContentType(t, "text/xml", testXMLBody)
+	validators := []interface{}{
+		Validator{Check: "xpath://order/@id", Assert: "equals", Expect: "1001"},
+		// $ in css selector should not be regarded as variable reference
+		Validator{Check: "css:item[sku$='-1'] price", Assert: "equals", Expect: "1.5"},
+		Validator{Check: "xpath:count(//item)", Assert: "equals", Expect: 2},
+	}
+	err := respObj.Validate(validators, map[string]interface{}{})
+	if !assert.Nil(t, err) {
+		t.Fail()
+	}
+}
+
+func TestParseXMLBody(t *testing.T) {
+	// invalid xml falls back to raw body
+	respObj := newTestResponseObjectWithContentType(t, "application/xml", "<order><status>paid</order>")
+	if !assert.Equal(t, "<order><status>paid</order>", respObj.extractField("body")) {
+		t.Fail()
+	}
+	// xml body without xml content type is kept as raw body, but could still be queried with xpath
+	respObj = newTestResponseObject(t, testXMLBody)
+	if !assert.Equal(t, testXMLBody, respObj.extractField("body")) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "paid", respObj.extractField("xpath:/order/status")) {
+		t.Fail()
+	}
+	if !assert.True(t, isXMLContentType("application/soap+xml; charset=utf-8")) {
+		t.Fail()
+	}
+	if !assert.False(t, isXMLContentType("application/json")) {
+		t.Fail()
+	}
+}
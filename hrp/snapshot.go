@@ -0,0 +1,129 @@
+package hrp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+	"github.com/httprunner/httprunner/hrp/internal/json"
+	"github.com/httprunner/httprunner/hrp/internal/snapshot"
+)
+
+const assertSnapshot = "snapshot"
+
+// snapshotExpect is the expect value of snapshot validator, which is either golden file path
+// or the following object, golden file path is relative to testcase directory.
+type snapshotExpect struct {
+	File string `json:"file" yaml:"file"`
+	snapshot.Options
+}
+
+func parseSnapshotExpect(expect interface{}) (*snapshotExpect, error) {
+	if file, ok := expect.(string); ok {
+		return &snapshotExpect{File: file}, nil
+	}
+	content, err := json.Marshal(expect)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode snapshot expect failed")
+	}
+	se := &snapshotExpect{}
+	if err := json.Unmarshal(content, se); err != nil {
+		return nil, errors.Wrap(err, "decode snapshot expect failed")
+	}
+	if se.File == "" {
+		return nil, errors.New("snapshot file is not specified")
+	}
+	return se, nil
+}
+
+// validateSnapshot compares check value with golden file, golden file is created if not exists,
+// and rewritten in update snapshots mode. Each difference is recorded as a failed validation result.
+func (v *responseObject) validateSnapshot(validator Validator, checkValue interface{},
+	variablesMapping map[string]interface{}) error {
+
+	se, err := parseSnapshotExpect(validator.Expect)
+	if err != nil {
+		return err
+	}
+	file, err := v.parser.parseData(se.File, variablesMapping)
+	if err != nil {
+		return err
+	}
+	se.File = fmt.Sprint(file)
+	path := se.File
+	if !filepath.IsAbs(path) && v.caseDir != "" {
+		path = filepath.Join(v.caseDir, path)
+	}
+
+	actual, err := snapshot.Normalize(checkValue)
+	if err != nil {
+		return err
+	}
+	validResult := &validationResult{
+		Validator: Validator{
+			Check:   validator.Check,
+			Expect:  se.File,
+			Assert:  assertSnapshot,
+			Message: validator.Message,
+		},
+		CheckValue:  checkValue,
+		CheckResult: "pass",
+	}
+
+	if v.updateSnapshots {
+		if err := snapshot.Save(path, actual); err != nil {
+			return err
+		}
+		v.validationResults = append(v.validationResults, validResult)
+		return nil
+	}
+	if !builtin.IsFilePathExists(path) {
+		validResult.CheckResult = "fail"
+		validResult.Message = "snapshot not found, run with --update-snapshots"
+		v.validationResults = append(v.validationResults, validResult)
+		v.t.Fail()
+		return errors.Errorf("snapshot %s not found, run with --update-snapshots", se.File)
+	}
+
+	expected, err := snapshot.Load(path)
+	if err != nil {
+		return err
+	}
+	differences := snapshot.Compare(expected, actual, &se.Options)
+	log.Info().
+		Str("checkExpr", validator.Check).
+		Str("assertMethod", assertSnapshot).
+		Str("snapshot", path).
+		Int("differences", len(differences)).
+		Msgf("validate %s", validator.Check)
+	if len(differences) == 0 {
+		v.validationResults = append(v.validationResults, validResult)
+		return nil
+	}
+
+	var messages []string
+	for _, difference := range differences {
+		message := fmt.Sprintf("%s: %s", difference.Path, difference.Kind)
+		if validator.Message != "" {
+			message = fmt.Sprintf("%s, %s", validator.Message, message)
+		}
+		v.validationResults = append(v.validationResults, &validationResult{
+			Validator: Validator{
+				Check:   validator.Check + difference.Path,
+				Expect:  difference.Expected,
+				Assert:  assertSnapshot,
+				Message: message,
+			},
+			CheckValue:  difference.Actual,
+			CheckResult: "fail",
+		})
+		messages = append(messages, difference.String())
+	}
+	v.t.Fail()
+	return errors.Errorf("snapshot mismatched with %s, checkExpr: %v, %d differences: %s",
+		se.File, validator.Check, len(differences), strings.Join(messages, "; "))
+}
@@ -0,0 +1,98 @@
+package hrp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSnapshot(t *testing.T) {
+	caseDir := t.TempDir()
+	validators := []interface{}{
+		Validator{Check: "body", Assert: "snapshot", Expect: "snapshots/pet.json"},
+	}
+
+	// missing snapshot fails unless in update mode
+	respObj := newTestResponseObject(&testing.T{}, `{"id": 1, "name": "doggie", "tags": ["a", "b"]}`)
+	respObj.caseDir = caseDir
+	err := respObj.Validate(validators, map[string]interface{}{})
+	if !assert.Error(t, err) || !assert.Contains(t, err.Error(), "run with --update-snapshots") {
+		t.Fatal()
+	}
+	if !assert.NoFileExists(t, filepath.Join(caseDir, "snapshots", "pet.json")) {
+		t.Fatal()
+	}
+
+	// snapshot is created in update mode
+	respObj = newTestResponseObject(t, `{"id": 1, "name": "doggie", "tags": ["a", "b"]}`)
+	respObj.caseDir = caseDir
+	respObj.updateSnapshots = true
+	if !assert.NoError(t, respObj.Validate(validators, map[string]interface{}{})) {
+		t.Fatal()
+	}
+	if !assert.FileExists(t, filepath.Join(caseDir, "snapshots", "pet.json")) {
+		t.Fatal()
+	}
+
+	// differences are reported with their locations
+	respObj = newTestResponseObject(&testing.T{}, `{"id": 2, "name": "doggie", "tags": ["b", "a"]}`)
+	respObj.caseDir = caseDir
+	err = respObj.Validate(validators, map[string]interface{}{})
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	var checks []string
+	for _, result := range respObj.validationResults {
+		checks = append(checks, result.Check)
+	}
+	if !assert.Equal(t, []string{"body/id", "body/tags/0", "body/tags/1"}, checks) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "/id: changed", respObj.validationResults[0].Message) {
+		t.Fail()
+	}
+	if !assert.Contains(t, err.Error(), "/id: changed from 1 to 2") {
+		t.Fail()
+	}
+
+	// ignore volatile paths and array order
+	validatorsWithOptions := []interface{}{
+		Validator{Check: "body", Assert: "snapshot", Expect: map[string]interface{}{
+			"file":         "snapshots/pet.json",
+			"ignore_paths": []interface{}{"/id"},
+			"ignore_order": true,
+		}},
+	}
+	respObj = newTestResponseObject(t, `{"id": 2, "name": "doggie", "tags": ["b", "a"]}`)
+	respObj.caseDir = caseDir
+	if !assert.NoError(t, respObj.Validate(validatorsWithOptions, map[string]interface{}{})) {
+		t.Fail()
+	}
+
+	// rewrite snapshot in update mode
+	respObj = newTestResponseObject(t, `{"id": 3, "name": "kitty"}`)
+	respObj.caseDir = caseDir
+	respObj.updateSnapshots = true
+	if !assert.NoError(t, respObj.Validate(validators, map[string]interface{}{})) {
+		t.Fail()
+	}
+	respObj = newTestResponseObject(t, `{"id": 3, "name": "kitty"}`)
+	respObj.caseDir = caseDir
+	if !assert.NoError(t, respObj.Validate(validators, map[string]interface{}{})) {
+		t.Fail()
+	}
+}
+
+func TestParseSnapshotExpect(t *testing.T) {
+	se, err := parseSnapshotExpect(map[string]interface{}{"file": "a.json", "ignore_paths": []interface{}{"/id"}})
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, "a.json", se.File) || !assert.Equal(t, []string{"/id"}, se.IgnorePaths) {
+		t.Fail()
+	}
+	if _, err := parseSnapshotExpect(map[string]interface{}{"ignore_order": true}); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,400 @@
+package hrp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+	"github.com/httprunner/httprunner/hrp/internal/json"
+)
+
+const (
+	socketTCP string = "tcp"
+	socketTLS string = "tls"
+	socketUDP string = "udp"
+)
+
+const (
+	encodingText   string = "text"
+	encodingHex    string = "hex"
+	encodingBase64 string = "base64"
+)
+
+const (
+	defaultSocketTimeout float32 = 5     // seconds
+	maxUDPDatagramSize   int     = 65535 // bytes
+)
+
+// TCP makes a raw TCP socket step.
+func (s *StepRequest) TCP(address string) *StepSocket {
+	return s.socket(socketTCP, address)
+}
+
+// TLS makes a TLS-over-TCP socket step.
+func (s *StepRequest) TLS(address string) *StepSocket {
+	return s.socket(socketTLS, address)
+}
+
+// UDP makes a raw UDP socket step.
+func (s *StepRequest) UDP(address string) *StepSocket {
+	return s.socket(socketUDP, address)
+}
+
+func (s *StepRequest) socket(network, address string) *StepSocket {
+	s.step.Socket = &Socket{
+		Network: network,
+		Address: address,
+	}
+	return &StepSocket{
+		step: s.step,
+	}
+}
+
+// StepSocket implements IStep interface.
+type StepSocket struct {
+	step *TStep
+}
+
+// WithPayload sets payload to send for current socket step.
+func (s *StepSocket) WithPayload(payload interface{}) *StepSocket {
+	s.step.Socket.Payload = payload
+	return s
+}
+
+// WithEncoding sets payload encoding for current socket step, text(default), hex or base64.
+func (s *StepSocket) WithEncoding(encoding string) *StepSocket {
+	s.step.Socket.Encoding = encoding
+	return s
+}
+
+// ReadUntil reads response until delimiter received, the delimiter is excluded from response.
+func (s *StepSocket) ReadUntil(delimiter string) *StepSocket {
+	s.step.Socket.Delimiter = delimiter
+	return s
+}
+
+// ReadLength reads response until length bytes received.
+func (s *StepSocket) ReadLength(length int) *StepSocket {
+	s.step.Socket.Length = length
+	return s
+}
+
+// SetTimeout sets timeout in seconds for current socket step.
+func (s *StepSocket) SetTimeout(timeout float32) *StepSocket {
+	s.step.Socket.Timeout = timeout
+	return s
+}
+
+// SetVerify sets whether to verify server certificate for TLS socket.
+func (s *StepSocket) SetVerify(verify bool) *StepSocket {
+	s.step.Socket.Verify = verify
+	return s
+}
+
+// TeardownHook adds a teardown hook for current teststep.
+func (s *StepSocket) TeardownHook(hook string) *StepSocket {
+	s.step.TeardownHooks = append(s.step.TeardownHooks, hook)
+	return s
+}
+
+// Validate switches to step validation.
+func (s *StepSocket) Validate() *StepRequestValidation {
+	return &StepRequestValidation{
+		step: s.step,
+	}
+}
+
+// Extract switches to step extraction.
+func (s *StepSocket) Extract() *StepRequestExtraction {
+	s.step.Extract = make(map[string]string)
+	return &StepRequestExtraction{
+		step: s.step,
+	}
+}
+
+func (s *StepSocket) Name() string {
+	if s.step.Name != "" {
+		return s.step.Name
+	}
+	return fmt.Sprintf("%s %s", s.step.Socket.Network, s.step.Socket.Address)
+}
+
+func (s *StepSocket) Type() string {
+	return fmt.Sprintf("socket-%v", s.step.Socket.Network)
+}
+
+func (s *StepSocket) ToStruct() *TStep {
+	return s.step
+}
+
+// encodePayload converts parsed payload to bytes with specified encoding
+func encodePayload(payload interface{}, encoding string) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	var raw string
+	switch v := payload.(type) {
+	case string:
+		raw = v
+	case []byte:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		// send json for structured payload
+		return json.Marshal(v)
+	default:
+		raw = fmt.Sprint(v)
+	}
+
+	switch strings.ToLower(encoding) {
+	case "", encodingText:
+		return []byte(raw), nil
+	case encodingHex:
+		// tolerate whitespaces in hex payload, e.g. "01 02 0a"
+		return hex.DecodeString(strings.Join(strings.Fields(raw), ""))
+	case encodingBase64:
+		return base64.StdEncoding.DecodeString(raw)
+	default:
+		return nil, fmt.Errorf("unsupported payload encoding: %s", encoding)
+	}
+}
+
+func dialSocket(socket *Socket, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	switch strings.ToLower(socket.Network) {
+	case socketTCP:
+		return dialer.Dial("tcp", socket.Address)
+	case socketUDP:
+		return dialer.Dial("udp", socket.Address)
+	case socketTLS:
+		return tls.DialWithDialer(dialer, "tcp", socket.Address, &tls.Config{
+			InsecureSkipVerify: !socket.Verify,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported socket network: %s", socket.Network)
+	}
+}
+
+// readSocket reads data from connection until delimiter, length or timeout reached
+func readSocket(conn net.Conn, socket *Socket) ([]byte, error) {
+	// udp is message oriented, receive one datagram and trim it
+	if strings.ToLower(socket.Network) == socketUDP {
+		buf := make([]byte, maxUDPDatagramSize)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		data := buf[:n]
+		if socket.Delimiter != "" {
+			if index := bytes.Index(data, []byte(socket.Delimiter)); index != -1 {
+				data = data[:index]
+			}
+		}
+		if socket.Length > 0 && len(data) > socket.Length {
+			data = data[:socket.Length]
+		}
+		return data, nil
+	}
+
+	// read until length bytes received
+	if socket.Length > 0 {
+		data := make([]byte, socket.Length)
+		n, err := io.ReadFull(conn, data)
+		if err != nil {
+			return data[:n], errors.Wrapf(err, "expect %d bytes, got %d", socket.Length, n)
+		}
+		return data, nil
+	}
+
+	// read until delimiter received
+	if socket.Delimiter != "" {
+		delimiter := []byte(socket.Delimiter)
+		var data []byte
+		buf := make([]byte, 4096)
+		for {
+			n, err := conn.Read(buf)
+			data = append(data, buf[:n]...)
+			if index := bytes.Index(data, delimiter); index != -1 {
+				return data[:index], nil
+			}
+			if err != nil {
+				return data, errors.Wrapf(err, "delimiter %q not received", socket.Delimiter)
+			}
+		}
+	}
+
+	// read until timeout or connection closed by server
+	data, err := io.ReadAll(conn)
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return data, nil
+	}
+	return data, err
+}
+
+func (r *caseRunner) runStepSocket(step *TStep) (stepResult *stepData, err error) {
+	stepResult = &stepData{
+		Name:        step.Name,
+		StepType:    stepTypeSocket,
+		Success:     false,
+		ContentSize: 0,
+	}
+	sessionData := newSessionData()
+	// copy socket to avoid data racing
+	socketCopy := *step.Socket
+	socket := &socketCopy
+
+	// parse socket address
+	address, err := r.parser.parseString(socket.Address, step.Variables)
+	if err != nil {
+		return stepResult, errors.Wrap(err, "parse socket address failed")
+	}
+	socket.Address = convertString(address)
+
+	// prepare payload, keep leading and trailing whitespaces for text payload, e.g. "PING\r\n"
+	var payload interface{}
+	if rawPayload, ok := socket.Payload.(string); ok {
+		payload, err = r.parser.parseString(rawPayload, step.Variables)
+	} else {
+		payload, err = r.parser.parseData(socket.Payload, step.Variables)
+	}
+	if err != nil {
+		return stepResult, errors.Wrap(err, "parse socket payload failed")
+	}
+	payloadBytes, err := encodePayload(payload, socket.Encoding)
+	if err != nil {
+		return stepResult, errors.Wrap(err, "encode socket payload failed")
+	}
+
+	requestMap := map[string]interface{}{
+		"network":   socket.Network,
+		"address":   socket.Address,
+		"payload":   payload,
+		"encoding":  socket.Encoding,
+		"delimiter": socket.Delimiter,
+		"length":    socket.Length,
+	}
+	sessionData.ReqResps.Request = maskSessionMap(r.hrpRunner.redactor, requestMap)
+
+	// add request object to step variables, could be used in setup hooks
+	step.Variables["hrp_step_name"] = step.Name
+	step.Variables["hrp_step_request"] = requestMap
+
+	// deal with setup hooks
+	for _, setupHook := range step.SetupHooks {
+		_, err = r.parser.parseData(setupHook, step.Variables)
+		if err != nil {
+			return stepResult, errors.Wrap(err, "run setup hooks failed")
+		}
+	}
+
+	timeout := socket.Timeout
+	if timeout <= 0 {
+		timeout = defaultSocketTimeout
+	}
+	timeoutDuration := time.Duration(timeout*1000) * time.Millisecond
+
+	if r.hrpRunner.requestsLogOn || r.hrpRunner.dryRun {
+		fmt.Println("-------------------- socket ---------------------")
+		// payload fields matched by redact JSON paths are masked
+		maskedBytes, err := encodePayload(r.hrpRunner.redactor.MaskJSON(payload), socket.Encoding)
+		if err != nil {
+			return stepResult, errors.Wrap(err, "encode socket payload failed")
+		}
+		fmt.Println(r.hrpRunner.redactor.String(
+			fmt.Sprintf("%s %s\n%s", socket.Network, socket.Address, string(maskedBytes))))
+	}
+
+	// render payload only in dry run mode, skip network
+	if r.hrpRunner.dryRun {
+		return r.dryRunStep(step, stepResult, sessionData), nil
+	}
+
+	// do socket action
+	start := time.Now()
+	conn, err := dialSocket(socket, timeoutDuration)
+	if err != nil {
+		stepResult.Elapsed = time.Since(start).Milliseconds()
+		return stepResult, errors.Wrap(err, "dial socket failed")
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(start.Add(timeoutDuration)); err != nil {
+		return stepResult, errors.Wrap(err, "set socket deadline failed")
+	}
+	if len(payloadBytes) > 0 {
+		if _, err = conn.Write(payloadBytes); err != nil {
+			stepResult.Elapsed = time.Since(start).Milliseconds()
+			return stepResult, errors.Wrap(err, "send socket payload failed")
+		}
+	}
+	received, err := readSocket(conn, socket)
+	stepResult.Elapsed = time.Since(start).Milliseconds()
+	if err != nil {
+		return stepResult, errors.Wrap(err, "read socket failed")
+	}
+	stepResult.ContentSize = int64(len(received))
+
+	// new response object
+	respObj, err := newSocketResponseObject(r.hrpRunner.t, r.parser, received)
+	if err != nil {
+		return stepResult, errors.Wrap(err, "init ResponseObject error")
+	}
+
+	if r.hrpRunner.requestsLogOn {
+		fmt.Println("==================== received ===================")
+		fmt.Println(r.hrpRunner.redactor.String(string(maskBody(r.hrpRunner.redactor, "", received))))
+		fmt.Println("--------------------------------------------------")
+	}
+
+	// add response object to step variables, could be used in teardown hooks
+	step.Variables["hrp_step_response"] = respObj.respObjMeta
+
+	// deal with teardown hooks
+	for _, teardownHook := range step.TeardownHooks {
+		_, err = r.parser.parseData(teardownHook, step.Variables)
+		if err != nil {
+			return stepResult, errors.Wrap(err, "run teardown hooks failed")
+		}
+	}
+
+	sessionData.ReqResps.Response = builtin.FormatResponse(
+		maskSessionMap(r.hrpRunner.redactor, respObj.respObjMeta.(map[string]interface{})))
+
+	// extract variables from received data
+	respObj.redactor = r.hrpRunner.redactor
+	extractMapping, err := respObj.Extract(step.Extract, step.Extractors)
+	stepResult.ExportVars = extractMapping
+	if err != nil {
+		stepResult.Data = sessionData
+		return stepResult, err
+	}
+
+	// override step variables with extracted variables
+	stepVariables := mergeVariables(step.Variables, extractMapping)
+
+	// validate received data
+	if r.Config.Path != "" {
+		respObj.caseDir = filepath.Dir(r.Config.Path)
+	}
+	respObj.updateSnapshots = r.hrpRunner.updateSnapshots
+	respObj.softAssert = r.hrpRunner.softAssert || step.SoftAssert
+	err = respObj.Validate(step.Validators, stepVariables)
+	sessionData.Validators = respObj.validationResults
+	if err == nil {
+		sessionData.Success = true
+		stepResult.Success = true
+	}
+	stepResult.Data = sessionData
+
+	log.Info().Str("address", socket.Address).Int64("size", stepResult.ContentSize).Msg("socket step done")
+	return stepResult, err
+}
@@ -0,0 +1,215 @@
+package hrp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startLineServer starts a line-based TCP server, which replies "echo: <line>\r\n" for each received line.
+func startLineServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					conn.Write([]byte("echo: " + strings.TrimSpace(line) + "\r\n"))
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func startUDPEchoServer(t *testing.T) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(buf[:n], addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestEncodePayload(t *testing.T) {
+	testData := []struct {
+		payload  interface{}
+		encoding string
+		expected []byte
+	}{
+		{"PING\r\n", "", []byte("PING\r\n")},
+		{"PING\r\n", "text", []byte("PING\r\n")},
+		{"50 49 4e 47", "hex", []byte("PING")},
+		{"UElORw==", "base64", []byte("PING")},
+		{123, "", []byte("123")},
+		{map[string]interface{}{"a": 1}, "", []byte(`{"a":1}`)},
+	}
+	for _, data := range testData {
+		value, err := encodePayload(data.payload, data.encoding)
+		if !assert.NoError(t, err) {
+			t.Fail()
+		}
+		if !assert.Equal(t, data.expected, value) {
+			t.Fail()
+		}
+	}
+
+	if _, err := encodePayload("abc", "unknown"); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestRunStepSocketTCP(t *testing.T) {
+	address := startLineServer(t)
+	testcase := &TestCase{
+		Config: NewConfig("socket").
+			WithVariables(map[string]interface{}{"address": address, "cmd": "PING"}),
+		TestSteps: []IStep{
+			NewStep("tcp with delimiter").
+				TCP("$address").
+				WithPayload("$cmd\n").
+				ReadUntil("\r\n").
+				Extract().
+				WithJmesPath("body", "reply").
+				Validate().
+				AssertEqual("body", "echo: PING", "check received line").
+				AssertRegexp("body", "^echo", "check received prefix"),
+			NewStep("tcp with hex payload and length").
+				TCP("$address").
+				WithPayload("48 49 0a"). // HI\n
+				WithEncoding("hex").
+				ReadLength(8).
+				Validate().
+				AssertEqual("body", "echo: HI", "check received bytes"),
+		},
+	}
+	runner := NewRunner(t).newCaseRunner(testcase)
+	if err := runner.parseConfig(testcase.Config); err != nil {
+		t.Fatal(err)
+	}
+	stepResult, err := runner.runStep(0, testcase.Config)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	if !assert.Equal(t, stepTypeSocket, stepResult.StepType) {
+		t.Fail()
+	}
+	if !assert.Equal(t, "echo: PING", runner.sessionVariables["reply"]) {
+		t.Fail()
+	}
+	if _, err := runner.runStep(1, testcase.Config); !assert.NoError(t, err) {
+		t.Fail()
+	}
+}
+
+func TestRunStepSocketUDP(t *testing.T) {
+	address := startUDPEchoServer(t)
+	testcase := &TestCase{
+		Config: NewConfig("socket"),
+		TestSteps: []IStep{
+			NewStep("udp echo").
+				UDP(address).
+				WithPayload(`{"status": "ok", "count": 3}`).
+				SetTimeout(1).
+				Validate().
+				AssertEqual("body.status", "ok", "check json field").
+				AssertEqual("body.count", 3, "check json number"),
+		},
+	}
+	runner := NewRunner(t).newCaseRunner(testcase)
+	if _, err := runner.runStep(0, testcase.Config); !assert.NoError(t, err) {
+		t.Fail()
+	}
+}
+
+func TestRunStepSocketWithRedact(t *testing.T) {
+	address := startUDPEchoServer(t)
+	testcase := &TestCase{
+		Config: NewConfig("socket").
+			WithVariables(map[string]interface{}{"password": "p@ssw0rd"}).
+			SetRedact(&RedactConfig{
+				Variables: []string{"password"},
+				JSONPaths: []string{"$.token", "$.password"},
+			}),
+		TestSteps: []IStep{
+			NewStep("udp echo").
+				UDP(address).
+				WithPayload(map[string]interface{}{"password": "$password", "token": "tk-12345"}).
+				SetTimeout(1).
+				Validate().
+				AssertEqual("body.token", "tk-12345", "check token"),
+		},
+	}
+	runner := NewRunner(t).SetRequestsLogOn().newCaseRunner(testcase)
+	if err := runner.parseConfig(testcase.Config); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = writer
+	_, err = runner.runStep(0, testcase.Config)
+	os.Stdout = stdout
+	writer.Close()
+	output, _ := io.ReadAll(reader)
+	if !assert.NoError(t, err) {
+		t.Fail()
+	}
+	if !assert.Contains(t, string(output), "received") {
+		t.Fail()
+	}
+	for _, secret := range []string{"p@ssw0rd", "tk-12345"} {
+		if !assert.NotContains(t, string(output), secret) {
+			t.Fail()
+		}
+	}
+}
+
+func TestRunStepSocketTimeout(t *testing.T) {
+	address := startLineServer(t)
+	testcase := &TestCase{
+		Config: NewConfig("socket"),
+		TestSteps: []IStep{
+			NewStep("delimiter not received").
+				TCP(address).
+				WithPayload("PING\n").
+				ReadUntil("END").
+				SetTimeout(0.2),
+		},
+	}
+	runner := NewRunner(t).newCaseRunner(testcase)
+	if _, err := runner.runStep(0, testcase.Config); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
@@ -21,6 +21,13 @@ func (c *TConfig) WithVariables(variables map[string]interface{}) *TConfig {
 	return c
 }
 
+// WithVariablesFiles sets JSON/YAML files of shared variables for current testcase,
+// later files override earlier ones and variables set by WithVariables take precedence.
+func (c *TConfig) WithVariablesFiles(paths ...string) *TConfig {
+	c.VariablesFiles = paths
+	return c
+}
+
 // SetBaseURL sets base URL for current testcase.
 func (c *TConfig) SetBaseURL(baseURL string) *TConfig {
 	c.BaseURL = baseURL
@@ -63,6 +70,31 @@ func (c *TConfig) SetWeight(weight int) *TConfig {
 	return c
 }
 
+// SetOpenAPI sets OpenAPI document path, responses of request steps are validated against matched operations.
+func (c *TConfig) SetOpenAPI(path string) *TConfig {
+	c.OpenAPI = path
+	return c
+}
+
+// AllowOpenAPIUnmatched allows requests matching no OpenAPI operation, which fail contract validation by default.
+func (c *TConfig) AllowOpenAPIUnmatched() *TConfig {
+	c.OpenAPIUnmatched = true
+	return c
+}
+
+// SetRedact sets secrets to be masked in request logs, summary and HTML report for current testcase.
+func (c *TConfig) SetRedact(redact *RedactConfig) *TConfig {
+	c.Redact = redact
+	return c
+}
+
+// SetSecretsFile sets encrypted secrets file, variables in it are decrypted in memory
+// and merged into config variables, config variables take precedence.
+func (c *TConfig) SetSecretsFile(path string) *TConfig {
+	c.SecretsFile = path
+	return c
+}
+
 // NewStep returns a new constructed teststep with specified step name.
 func NewStep(name string) *StepRequest {
 	return &StepRequest{
@@ -83,6 +115,13 @@ func (s *StepRequest) WithVariables(variables map[string]interface{}) *StepReque
 	return s
 }
 
+// WithParameters sets parameters for current teststep, the step is run once for each parameters row,
+// e.g. calling referenced testcase or api with data-driven parameters.
+func (s *StepRequest) WithParameters(parameters map[string]interface{}) *StepRequest {
+	s.step.Parameters = parameters
+	return s
+}
+
 // SetupHook adds a setup hook for current teststep.
 func (s *StepRequest) SetupHook(hook string) *StepRequest {
 	s.step.SetupHooks = append(s.step.SetupHooks, hook)
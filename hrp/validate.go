@@ -13,10 +13,16 @@ func (s *StepRequestValidation) Name() string {
 	if s.step.Name != "" {
 		return s.step.Name
 	}
+	if s.step.Socket != nil {
+		return fmt.Sprintf("%s %s", s.step.Socket.Network, s.step.Socket.Address)
+	}
 	return fmt.Sprintf("%s %s", s.step.Request.Method, s.step.Request.URL)
 }
 
 func (s *StepRequestValidation) Type() string {
+	if s.step.Socket != nil {
+		return fmt.Sprintf("socket-%v", s.step.Socket.Network)
+	}
 	return fmt.Sprintf("request-%v", s.step.Request.Method)
 }
 
@@ -24,6 +30,13 @@ func (s *StepRequestValidation) ToStruct() *TStep {
 	return s.step
 }
 
+// SoftAssert evaluates all validators of current step and reports every failure,
+// instead of returning on the first failed validator.
+func (s *StepRequestValidation) SoftAssert() *StepRequestValidation {
+	s.step.SoftAssert = true
+	return s
+}
+
 func (s *StepRequestValidation) AssertEqual(jmesPath string, expected interface{}, msg string) *StepRequestValidation {
 	v := Validator{
 		Check:   jmesPath,
@@ -221,3 +234,103 @@ func (s *StepRequestValidation) AssertLengthGreaterOrEquals(jmesPath string, exp
 	s.step.Validators = append(s.step.Validators, v)
 	return s
 }
+
+// AssertJSONSchema validates value against json schema, schema is either inline schema
+// or schema file path relative to testcase file.
+func (s *StepRequestValidation) AssertJSONSchema(jmesPath string, schema interface{}, msg string) *StepRequestValidation {
+	v := Validator{
+		Check:   jmesPath,
+		Assert:  "json_schema",
+		Expect:  schema,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+// AssertSnapshot compares value with golden file relative to testcase file,
+// options could be specified with snapshot object, e.g. ignore_paths and ignore_order.
+func (s *StepRequestValidation) AssertSnapshot(jmesPath string, snapshot interface{}, msg string) *StepRequestValidation {
+	v := Validator{
+		Check:   jmesPath,
+		Assert:  "snapshot",
+		Expect:  snapshot,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+func (s *StepRequestValidation) AssertNotContains(jmesPath string, expected interface{}, msg string) *StepRequestValidation {
+	v := Validator{
+		Check:   jmesPath,
+		Assert:  "not_contains",
+		Expect:  expected,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+func (s *StepRequestValidation) AssertNotRegexp(jmesPath string, expected interface{}, msg string) *StepRequestValidation {
+	v := Validator{
+		Check:   jmesPath,
+		Assert:  "not_regex_match",
+		Expect:  expected,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+func (s *StepRequestValidation) AssertNotStartsWith(jmesPath string, expected interface{}, msg string) *StepRequestValidation {
+	v := Validator{
+		Check:   jmesPath,
+		Assert:  "not_startswith",
+		Expect:  expected,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+func (s *StepRequestValidation) AssertNotEndsWith(jmesPath string, expected interface{}, msg string) *StepRequestValidation {
+	v := Validator{
+		Check:   jmesPath,
+		Assert:  "not_endswith",
+		Expect:  expected,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+// AssertAnyOf passes if any of the nested validators passes
+func (s *StepRequestValidation) AssertAnyOf(validators []Validator, msg string) *StepRequestValidation {
+	v := Validator{
+		AnyOf:   validators,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+// AssertAllOf passes if all of the nested validators pass
+func (s *StepRequestValidation) AssertAllOf(validators []Validator, msg string) *StepRequestValidation {
+	v := Validator{
+		AllOf:   validators,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
+
+// AssertNot passes if the nested validator fails
+func (s *StepRequestValidation) AssertNot(validator Validator, msg string) *StepRequestValidation {
+	v := Validator{
+		Not:     &validator,
+		Message: msg,
+	}
+	s.step.Validators = append(s.step.Validators, v)
+	return s
+}
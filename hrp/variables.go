@@ -0,0 +1,112 @@
+package hrp
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/httprunner/httprunner/hrp/internal/builtin"
+)
+
+// locateVariablesFile locates variables file, relative path is located in testcase directory first,
+// and then in project root directory like referenced api and testcase.
+func locateVariablesFile(path, casePath string) (string, error) {
+	if filepath.IsAbs(path) || casePath == "" {
+		return path, nil
+	}
+	candidate := filepath.Join(filepath.Dir(casePath), path)
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate, nil
+	}
+	projectRootDir, err := getProjectRootDirPath(casePath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get project root dir")
+	}
+	candidate = filepath.Join(projectRootDir, path)
+	if _, err := os.Stat(candidate); err != nil {
+		return "", errors.Errorf("variables file %s not found in testcase directory or project root directory", path)
+	}
+	return candidate, nil
+}
+
+// loadVariablesFiles loads variables files specified in config in JSON or YAML format,
+// later files override earlier ones. Loaded files are cached in runner.
+func (r *HRPRunner) loadVariablesFiles(cfg *TConfig) (map[string]interface{}, error) {
+	variables := make(map[string]interface{})
+	for _, file := range cfg.VariablesFiles {
+		path, err := locateVariablesFile(file, cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		var fileVariables map[string]interface{}
+		if cached, ok := r.variablesFiles.Load(path); ok {
+			fileVariables = cached.(map[string]interface{})
+		} else {
+			if err := builtin.LoadFile(path, &fileVariables); err != nil {
+				return nil, errors.Wrapf(err, "load variables file %s failed", path)
+			}
+			r.variablesFiles.Store(path, fileVariables)
+		}
+		for name, value := range fileVariables {
+			variables[name] = value
+		}
+	}
+	return variables, nil
+}
+
+// mergeFileVariables merges variables loaded from variables files into config variables, the merge order from
+// low to high priority is: variables files inherited from caller testcases, variables files of the testcase in
+// listed order, and config variables. Names of variables only defined in variables files are returned,
+// thus they could be inherited by referenced testcases without overriding their own variables.
+func (r *caseRunner) mergeFileVariables(cfg *TConfig) (map[string]bool, error) {
+	if len(cfg.VariablesFiles) == 0 && len(r.inheritedVariables) == 0 {
+		return nil, nil
+	}
+	fileVariables, err := r.hrpRunner.loadVariablesFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	fileVariables = mergeVariables(fileVariables, r.inheritedVariables)
+
+	// config may be parsed repeatedly, e.g. for each parameters iteration, thus names are recorded
+	// before file variables are merged into config variables for the first time
+	if cfg.fileVariableNames == nil {
+		cfg.fileVariableNames = make(map[string]bool)
+		for name := range fileVariables {
+			if _, ok := cfg.Variables[name]; !ok {
+				cfg.fileVariableNames[name] = true
+			}
+		}
+	}
+	cfg.Variables = mergeVariables(cfg.Variables, fileVariables)
+	return cfg.fileVariableNames, nil
+}
+
+// splitInheritedVariables removes variables passed to referenced testcase which are loaded from variables files
+// and not overridden by step, session or parameters variables whose names are specified in overridden,
+// they are returned to be inherited with the lowest priority.
+func (r *caseRunner) splitInheritedVariables(step *TStep, overridden map[string]bool) map[string]interface{} {
+	inherited := make(map[string]interface{})
+	for name := range r.fileVariables {
+		if overridden[name] {
+			continue
+		}
+		if value, ok := step.Variables[name]; ok {
+			inherited[name] = value
+			delete(step.Variables, name)
+		}
+	}
+	return inherited
+}
+
+// variableNames returns names of variables in all variables mappings
+func variableNames(variablesList ...map[string]interface{}) map[string]bool {
+	names := make(map[string]bool)
+	for _, variables := range variablesList {
+		for name := range variables {
+			names[name] = true
+		}
+	}
+	return names
+}
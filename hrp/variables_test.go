@@ -0,0 +1,146 @@
+package hrp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeVariablesFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseConfigWithVariablesFiles(t *testing.T) {
+	dir := writeVariablesFiles(t, map[string]string{
+		"common.yml":      "base_url: https://example.com\nuser: common\nretry: 3\n",
+		"data/users.json": `{"user": "json-user", "password": "123456"}`,
+	})
+
+	testcase := &TestCase{
+		Config: NewConfig("variables files").
+			WithVariables(map[string]interface{}{
+				"user":  "inline",
+				"login": "$user:$password@$base_url",
+			}).
+			WithVariablesFiles("common.yml", "data/users.json"),
+	}
+	testcase.Config.Path = filepath.Join(dir, "testcase.yml")
+	caseRunner := NewRunner(t).newCaseRunner(testcase)
+	if err := caseRunner.parseConfig(testcase.Config); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	// later files override earlier ones, config variables take precedence
+	expected := map[string]interface{}{
+		"base_url": "https://example.com",
+		"user":     "inline",
+		"retry":    3,
+		"password": "123456",
+		"login":    "inline:123456@https://example.com",
+	}
+	if !assert.Equal(t, expected, testcase.Config.Variables) {
+		t.Fail()
+	}
+	if !assert.Equal(t, map[string]interface{}{"base_url": "https://example.com", "retry": 3, "password": "123456"},
+		caseRunner.fileVariables) {
+		t.Fail()
+	}
+
+	testcase.Config.VariablesFiles = []string{"not_found.yml"}
+	if err := caseRunner.parseConfig(testcase.Config); !assert.Error(t, err) {
+		t.Fail()
+	}
+}
+
+func TestReferencedTestCaseInheritVariablesFiles(t *testing.T) {
+	dir := writeVariablesFiles(t, map[string]string{
+		"common.yml":    "base_url: https://example.com\nuser: common\ntoken: common-token\n",
+		"sub/child.yml": "token: child-token\n",
+	})
+
+	child := &TestCase{
+		Config: NewConfig("child").
+			WithVariables(map[string]interface{}{"user": "child"}).
+			WithVariablesFiles("child.yml"),
+	}
+	child.Config.Path = filepath.Join(dir, "sub", "child_test.yml")
+	parent := &TestCase{
+		Config: NewConfig("parent").
+			WithVariables(map[string]interface{}{"password": "parent"}).
+			WithVariablesFiles("common.yml"),
+		TestSteps: []IStep{
+			NewStep("call child").
+				WithVariables(map[string]interface{}{"password": "step"}).
+				CallRefCase(child),
+		},
+	}
+	parent.Config.Path = filepath.Join(dir, "parent_test.yml")
+
+	caseRunner := NewRunner(t).newCaseRunner(parent)
+	if err := caseRunner.parseConfig(parent.Config); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	stepResult, err := caseRunner.runStep(0, parent.Config)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	// variables files of caller are inherited with the lowest priority,
+	// step variables override variables of referenced testcase
+	expected := map[string]interface{}{
+		"base_url": "https://example.com",
+		"user":     "child",
+		"token":    "child-token",
+		"password": "step",
+	}
+	if !assert.Equal(t, expected, stepResult.Data.(*testCaseSummary).InOut.ConfigVars) {
+		t.Fail()
+	}
+}
+
+func TestReferencedTestCaseStepVariableEqualToFileVariable(t *testing.T) {
+	dir := writeVariablesFiles(t, map[string]string{
+		"common.yml": "user: alice\n",
+	})
+
+	child := &TestCase{
+		Config: NewConfig("child").
+			WithVariables(map[string]interface{}{"user": "bob"}),
+	}
+	parent := &TestCase{
+		Config: NewConfig("parent").
+			WithVariablesFiles("common.yml"),
+		TestSteps: []IStep{
+			NewStep("call child").
+				WithVariables(map[string]interface{}{"user": "alice"}).
+				CallRefCase(child),
+		},
+	}
+	parent.Config.Path = filepath.Join(dir, "parent_test.yml")
+
+	caseRunner := NewRunner(t).newCaseRunner(parent)
+	if err := caseRunner.parseConfig(parent.Config); !assert.NoError(t, err) {
+		t.Fatal()
+	}
+	stepResult, err := caseRunner.runStep(0, parent.Config)
+	if !assert.NoError(t, err) {
+		t.Fatal()
+	}
+
+	// step variable overrides variable of referenced testcase even if it equals the file variable
+	if !assert.Equal(t, "alice", stepResult.Data.(*testCaseSummary).InOut.ConfigVars["user"]) {
+		t.Fail()
+	}
+}